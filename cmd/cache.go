@@ -0,0 +1,43 @@
+package cmd
+
+import (
+	"path/filepath"
+
+	"github.com/jtoloui/depviz/internal/cli"
+	"github.com/jtoloui/depviz/internal/scanner"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	cacheCmd.AddCommand(cacheCleanCmd)
+	rootCmd.AddCommand(cacheCmd)
+}
+
+var cacheCmd = &cobra.Command{
+	Use:   "cache",
+	Short: "Manage the on-disk scan cache",
+}
+
+var cacheCleanCmd = &cobra.Command{
+	Use:   "clean [path]",
+	Short: "Delete a project's scan cache",
+	Args:  cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path := "."
+		if len(args) == 1 {
+			path = args[0]
+		}
+		root, err := filepath.Abs(path)
+		if err != nil {
+			return err
+		}
+
+		file := cachePath(root)
+		removed, err := scanner.RemoveCache(file)
+		if err != nil {
+			return err
+		}
+		cli.CacheCleaned(file, removed)
+		return nil
+	},
+}
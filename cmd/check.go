@@ -0,0 +1,48 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/jtoloui/depviz/internal/cli"
+	"github.com/jtoloui/depviz/internal/graph"
+	"github.com/jtoloui/depviz/internal/rules"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	rootCmd.AddCommand(checkCmd)
+}
+
+var checkCmd = &cobra.Command{
+	Use:   "check [path]",
+	Short: "Check imports against the architecture rules in .depviz.yml",
+	Long:  "Check every import against the rules section of .depviz.yml. Exits non-zero when any rule is violated, so it can gate CI.",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		p, err := loadProject(args[0])
+		if err != nil {
+			return err
+		}
+		if len(p.cfg.Rules) == 0 {
+			return errors.New("no rules defined in .depviz.yml")
+		}
+
+		engine, err := rules.New(p.cfg.Rules)
+		if err != nil {
+			return fmt.Errorf("compiling rules: %w", err)
+		}
+
+		res, err := graph.NewResolver(p.root, p.cfg.Layout, p.results)
+		if err != nil {
+			return fmt.Errorf("building resolver: %w", err)
+		}
+
+		violations := engine.Check(p.results, res)
+		cli.Check(violations, len(p.cfg.Rules))
+		if len(violations) > 0 {
+			return findings("%d rule violation(s) found", len(violations))
+		}
+		return nil
+	},
+}
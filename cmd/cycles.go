@@ -0,0 +1,47 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/jtoloui/depviz/internal/cli"
+	"github.com/jtoloui/depviz/internal/config"
+	"github.com/jtoloui/depviz/internal/graph"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	rootCmd.AddCommand(cyclesCmd)
+}
+
+var cyclesCmd = &cobra.Command{
+	Use:   "cycles [path]",
+	Short: "Detect import cycles between internal files",
+	Long:  "Detect import cycles between internal files. Exits non-zero when any cycle is found, so it can gate CI.",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		p, err := loadProject(args[0])
+		if err != nil {
+			return err
+		}
+
+		g, err := graph.Build(p.root, p.cfg.Layout, p.results)
+		if err != nil {
+			return fmt.Errorf("building graph: %w", err)
+		}
+
+		cycles := internalOnly(g, p).Cycles()
+		cli.Cycles(cycles)
+		if len(cycles) > 0 {
+			return findings("%d import cycle(s) found", len(cycles))
+		}
+		return nil
+	},
+}
+
+// internalOnly drops edges whose import the classifier doesn't consider
+// internal, so user classify rules decide what counts as "ours".
+func internalOnly(g *graph.Graph, p *project) *graph.Graph {
+	return g.Filter(func(e graph.Edge) bool {
+		return p.cl.ClassifyWithLang(e.Import.Path, g.Node(e.From).Lang) == config.Internal
+	})
+}
@@ -0,0 +1,49 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/jtoloui/depviz/internal/cli"
+	"github.com/jtoloui/depviz/internal/graph"
+	"github.com/jtoloui/depviz/internal/usage"
+	"github.com/spf13/cobra"
+)
+
+var deadJSON bool
+
+func init() {
+	deadExportsCmd.Flags().BoolVar(&deadJSON, "json", false, "print unused exports as JSON")
+	rootCmd.AddCommand(deadExportsCmd)
+}
+
+var deadExportsCmd = &cobra.Command{
+	Use:   "dead-exports [path]",
+	Short: "List exported symbols no other file imports",
+	Long: `List exported symbols that no other file in the project imports, following
+re-exports, namespace imports and default exports. For Go, only exported
+identifiers in internal/ packages are checked, since nothing outside the
+module can use them.
+
+Results are candidates: entry points, framework conventions and use via
+reflection or string lookups aren't visible to the scan.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		p, err := loadProject(args[0])
+		if err != nil {
+			return err
+		}
+
+		g, err := graph.Build(p.root, p.cfg.Layout, p.results)
+		if err != nil {
+			return fmt.Errorf("building graph: %w", err)
+		}
+
+		dead := usage.DeadExports(p.results, g)
+		if deadJSON {
+			return cli.DeadExportsJSON(os.Stdout, dead)
+		}
+		cli.DeadExports(dead)
+		return nil
+	},
+}
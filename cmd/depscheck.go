@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/jtoloui/depviz/internal/cli"
+	"github.com/jtoloui/depviz/internal/config"
+	"github.com/jtoloui/depviz/internal/depcheck"
+	"github.com/jtoloui/depviz/internal/glob"
+	"github.com/jtoloui/depviz/internal/manifest"
+	"github.com/spf13/cobra"
+)
+
+var (
+	devFlags      []string
+	depsCheckJSON bool
+)
+
+func init() {
+	depsCheckCmd.Flags().StringSliceVar(&devFlags, "dev", nil, "selector for files that may import dev dependencies (glob, or regex starting with ^); repeatable")
+	depsCheckCmd.Flags().BoolVar(&depsCheckJSON, "json", false, "print the findings as JSON")
+	rootCmd.AddCommand(depsCheckCmd)
+}
+
+var depsCheckCmd = &cobra.Command{
+	Use:   "deps-check [path]",
+	Short: "Compare external imports with the dependencies go.mod and package.json declare",
+	Long: `Compare every external and private import with the dependencies declared in
+go.mod and package.json. Reports packages imported without being declared
+(including go.mod requirements marked // indirect), dependencies nothing
+imports, and devDependencies imported by production code — anything outside
+the --dev selectors (tests, stories, mocks and tool configs by default).
+Test files are always scanned, whatever --tests says, and their imports
+count as dev uses.
+Exits non-zero on any finding, so it can gate CI.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// Dependencies used only by tests are still used, so tests are
+		// always scanned; their imports count as dev uses.
+		p, err := loadProjectTests(args[0], config.TestsInclude)
+		if err != nil {
+			return err
+		}
+
+		m, err := manifest.Load(p.root, p.cfg.Layout)
+		if err != nil {
+			return fmt.Errorf("reading manifests: %w", err)
+		}
+		if !m.Covers("go") && !m.Covers("js") {
+			return errors.New("no go.mod or package.json found")
+		}
+
+		sels := devFlags
+		if len(sels) == 0 {
+			sels = depcheck.DefaultDev
+		}
+		dev, err := glob.CompileAll(sels)
+		if err != nil {
+			return fmt.Errorf("invalid dev selector: %w", err)
+		}
+
+		r := depcheck.Check(p.results, p.cl, m, dev)
+		if depsCheckJSON {
+			if err := cli.DepsCheckJSON(os.Stdout, r); err != nil {
+				return err
+			}
+		} else {
+			cli.DepsCheck(r)
+		}
+		if r.Len() > 0 {
+			return findings("%d dependency problem(s) found", r.Len())
+		}
+		return nil
+	},
+}
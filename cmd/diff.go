@@ -0,0 +1,109 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/jtoloui/depviz/internal/cli"
+	"github.com/jtoloui/depviz/internal/diff"
+	"github.com/jtoloui/depviz/internal/git"
+	"github.com/jtoloui/depviz/internal/graph"
+	"github.com/jtoloui/depviz/internal/manifest"
+	"github.com/spf13/cobra"
+)
+
+var diffFormat string
+
+func init() {
+	diffCmd.Flags().StringVarP(&diffFormat, "format", "f", "text", "output format: text, json, markdown")
+	rootCmd.AddCommand(diffCmd)
+}
+
+var diffCmd = &cobra.Command{
+	Use:   "diff <base> <head> [path]",
+	Short: "Compare dependencies between two git revisions",
+	Long: "Scan the project at two git revisions and report added and removed imports, new external packages, " +
+		"changed exports and new import cycles. Each revision is checked out into a temporary worktree and " +
+		"scanned with its own .depviz.yml; the working tree is left untouched.",
+	Args: cobra.RangeArgs(2, 3),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path := "."
+		if len(args) == 3 {
+			path = args[2]
+		}
+		switch diffFormat {
+		case "text", "json", "markdown":
+		default:
+			return fmt.Errorf("unsupported format: %q", diffFormat)
+		}
+
+		base, err := snapshotAt(path, args[0])
+		if err != nil {
+			return err
+		}
+		head, err := snapshotAt(path, args[1])
+		if err != nil {
+			return err
+		}
+
+		rep := diff.Compute(*base, *head)
+		switch diffFormat {
+		case "json":
+			return cli.DiffJSON(os.Stdout, rep)
+		case "markdown":
+			return cli.DiffMarkdown(os.Stdout, rep)
+		}
+		cli.Diff(rep)
+		return nil
+	},
+}
+
+// snapshotAt scans path as it was at rev. path may be a subdirectory of the
+// repository; the same subdirectory is scanned in the checkout.
+func snapshotAt(path, rev string) (*diff.Snapshot, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+	top, err := git.TopLevel(abs)
+	if err != nil {
+		return nil, err
+	}
+	// Compare resolved paths: TopLevel reports symlink-free paths.
+	if resolved, err := filepath.EvalSymlinks(abs); err == nil {
+		abs = resolved
+	}
+	rel, err := filepath.Rel(top, abs)
+	if err != nil {
+		return nil, err
+	}
+
+	tree, cleanup, err := git.Checkout(top, rev)
+	if err != nil {
+		return nil, fmt.Errorf("checking out %s: %w", rev, err)
+	}
+	defer cleanup()
+
+	p, err := loadProject(filepath.Join(tree, rel))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", rev, err)
+	}
+	g, err := graph.Build(p.root, p.cfg.Layout, p.results)
+	if err != nil {
+		return nil, fmt.Errorf("%s: building graph: %w", rev, err)
+	}
+
+	m, err := manifest.Load(p.root, p.cfg.Layout)
+	if err != nil {
+		return nil, fmt.Errorf("%s: reading manifests: %w", rev, err)
+	}
+
+	return &diff.Snapshot{
+		Rev:        rev,
+		Results:    p.results,
+		Classifier: p.cl,
+		Manifests:  m,
+		Cycles:     internalOnly(g, p).Cycles(),
+	}, nil
+}
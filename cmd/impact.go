@@ -0,0 +1,105 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/jtoloui/depviz/internal/cli"
+	"github.com/jtoloui/depviz/internal/git"
+	"github.com/jtoloui/depviz/internal/graph"
+	"github.com/spf13/cobra"
+)
+
+var (
+	impactSince string
+	impactJSON  bool
+)
+
+func init() {
+	impactCmd.Flags().StringVar(&impactSince, "since", "", "use the files changed in a git revision range (e.g. main...HEAD)")
+	impactCmd.Flags().BoolVar(&impactJSON, "json", false, "print the impact report as JSON")
+	rootCmd.AddCommand(impactCmd)
+}
+
+var impactCmd = &cobra.Command{
+	Use:   "impact [path] [file...]",
+	Short: "List every file that transitively imports the given files",
+	Long: `List every file that transitively imports the given files, grouped by depth.
+Files are relative to the project (or to the current directory). With --since,
+the files changed in a git revision range are used instead.`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) == 1 && impactSince == "" {
+			return errors.New("give at least one file or --since")
+		}
+		p, err := loadProject(args[0])
+		if err != nil {
+			return err
+		}
+
+		g, err := graph.Build(p.root, p.cfg.Layout, p.results)
+		if err != nil {
+			return fmt.Errorf("building graph: %w", err)
+		}
+		g = internalOnly(g, p)
+
+		var targets []string
+		for _, arg := range args[1:] {
+			f := projectFile(p.root, arg)
+			if g.Node(f) == nil {
+				return fmt.Errorf("%s is not a scanned file", arg)
+			}
+			targets = append(targets, f)
+		}
+		if impactSince != "" {
+			changed, err := git.ChangedFiles(p.root, impactSince)
+			if err != nil {
+				return fmt.Errorf("listing changed files: %w", err)
+			}
+			// Deleted files and non-source files simply aren't in the graph.
+			for _, f := range changed {
+				if g.Node(f) != nil {
+					targets = append(targets, f)
+				}
+			}
+		}
+		sort.Strings(targets)
+		targets = dedupe(targets)
+
+		levels := g.Dependents(targets)
+		if impactJSON {
+			return cli.ImpactJSON(os.Stdout, targets, levels)
+		}
+		cli.Impact(targets, levels)
+		return nil
+	},
+}
+
+// projectFile maps a file argument to a path relative to root. Paths that
+// exist relative to the working directory are taken as such; anything else
+// is assumed to be relative to root already.
+func projectFile(root, arg string) string {
+	if abs, err := filepath.Abs(arg); err == nil {
+		if _, err := os.Stat(abs); err == nil {
+			if rel, err := filepath.Rel(root, abs); err == nil && !strings.HasPrefix(rel, "..") {
+				return rel
+			}
+		}
+	}
+	return filepath.Clean(arg)
+}
+
+// dedupe removes adjacent duplicates from a sorted slice.
+func dedupe(sorted []string) []string {
+	out := sorted[:0]
+	for i, s := range sorted {
+		if i == 0 || s != sorted[i-1] {
+			out = append(out, s)
+		}
+	}
+	return out
+}
@@ -69,6 +69,9 @@ var initCmd = &cobra.Command{
 						huh.NewOption("Go", "go"),
 						huh.NewOption("JavaScript/TypeScript", "js"),
 						huh.NewOption("Multi (Go + JS/TS)", "multi"),
+						huh.NewOption("Python", "python"),
+						huh.NewOption("Rust", "rust"),
+						huh.NewOption("Java/Kotlin", "jvm"),
 					).
 					Value(&lang),
 
@@ -144,8 +147,17 @@ var initCmd = &cobra.Command{
 }
 
 func detectLang(root string) string {
-	hasGo := fileExists(filepath.Join(root, "go.mod"))
+	hasGo := fileExists(filepath.Join(root, "go.mod")) || fileExists(filepath.Join(root, "go.work"))
 	hasJS := fileExists(filepath.Join(root, "package.json"))
+	hasPy := fileExists(filepath.Join(root, "pyproject.toml")) ||
+		fileExists(filepath.Join(root, "requirements.txt")) ||
+		fileExists(filepath.Join(root, "setup.py"))
+	hasRust := fileExists(filepath.Join(root, "Cargo.toml"))
+	hasJVM := fileExists(filepath.Join(root, "pom.xml")) ||
+		fileExists(filepath.Join(root, "build.gradle")) ||
+		fileExists(filepath.Join(root, "build.gradle.kts")) ||
+		fileExists(filepath.Join(root, "settings.gradle")) ||
+		fileExists(filepath.Join(root, "settings.gradle.kts"))
 
 	if hasGo && hasJS {
 		return "multi"
@@ -153,6 +165,15 @@ func detectLang(root string) string {
 	if hasJS {
 		return "js"
 	}
+	if hasPy && !hasGo {
+		return "python"
+	}
+	if hasRust && !hasGo {
+		return "rust"
+	}
+	if hasJVM && !hasGo {
+		return "jvm"
+	}
 	return "go"
 }
 
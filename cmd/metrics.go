@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/jtoloui/depviz/internal/cli"
+	"github.com/jtoloui/depviz/internal/graph"
+	"github.com/jtoloui/depviz/internal/metrics"
+	"github.com/spf13/cobra"
+)
+
+var (
+	metricsSort string
+	metricsJSON bool
+)
+
+func init() {
+	metricsCmd.Flags().StringVarP(&metricsSort, "sort", "s", "distance", "sort by: "+strings.Join(metrics.SortKeys, ", "))
+	metricsCmd.Flags().BoolVar(&metricsJSON, "json", false, "print the metrics as JSON")
+	rootCmd.AddCommand(metricsCmd)
+}
+
+var metricsCmd = &cobra.Command{
+	Use:   "metrics [path]",
+	Short: "Show package coupling, instability and abstractness metrics",
+	Long: `Show Robert C. Martin's package metrics: afferent (Ca) and efferent (Ce)
+coupling between internal packages, instability I = Ce/(Ca+Ce), abstractness A
+(the share of public exports that are interfaces or type-only) and the
+distance from the main sequence D = |A+I-1|. Packages are grouped as by
+--group-by package.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		p, err := loadProject(args[0])
+		if err != nil {
+			return err
+		}
+
+		g, err := graph.Build(p.root, p.cfg.Layout, p.results)
+		if err != nil {
+			return fmt.Errorf("building graph: %w", err)
+		}
+
+		pkgs := metrics.Compute(p.results, internalOnly(g, p))
+		if err := metrics.Sort(pkgs, metricsSort); err != nil {
+			return err
+		}
+		if metricsJSON {
+			return cli.MetricsJSON(os.Stdout, pkgs)
+		}
+		cli.Metrics(pkgs)
+		return nil
+	},
+}
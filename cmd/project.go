@@ -0,0 +1,117 @@
+package cmd
+
+import (
+	"fmt"
+	"log/slog"
+	"path/filepath"
+
+	"github.com/jtoloui/depviz/internal/classify"
+	"github.com/jtoloui/depviz/internal/config"
+	"github.com/jtoloui/depviz/internal/scanner"
+	"github.com/spf13/cobra"
+)
+
+// groupBy backs --group-by on the commands that render or summarise a scan.
+var groupBy string
+
+func addGroupFlag(c *cobra.Command) {
+	c.Flags().StringVar(&groupBy, "group-by", "", "merge files into nodes: package, dir or depth=N")
+}
+
+// project is a scanned project ready for rendering or analysis.
+type project struct {
+	root    string
+	cfg     *config.Config
+	cl      *classify.Classifier
+	results []scanner.FileImports
+}
+
+// loadProject loads config for path, scans it and builds a classifier —
+// the shared front half of every command.
+func loadProject(path string) (*project, error) {
+	return loadProjectTests(path, config.TestsDefault)
+}
+
+// loadProjectTests is loadProject with test files handled as t says,
+// overriding --tests and .depviz.yml, unless t is TestsDefault.
+func loadProjectTests(path string, t config.Tests) (*project, error) {
+	root, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg, err := config.Load(root, lang)
+	if err != nil {
+		return nil, fmt.Errorf("loading config: %w", err)
+	}
+	slog.Debug("config loaded", "language", cfg.Language, "excludes", len(cfg.Exclude))
+
+	cfg.Build = buildSettings(cfg.Build, build)
+	if err := cfg.Build.Validate(); err != nil {
+		return nil, fmt.Errorf("build settings: %w", err)
+	}
+	switch {
+	case t != config.TestsDefault:
+		cfg.Tests = t
+	case cfg.Tests == config.TestsDefault:
+		cfg.Tests = config.Tests(tests)
+	}
+	if err := cfg.Tests.Validate(); err != nil {
+		return nil, err
+	}
+
+	s, err := getScanner(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	cl, err := classify.New(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("creating classifier: %w", err)
+	}
+
+	var cache *scanner.Cache
+	if cs, ok := s.(scanner.Cacheable); ok && !noCache {
+		cache = scanner.OpenCache(cachePath(root), scanner.CacheFingerprint(rootCmd.Version, cfg))
+		cs.UseCache(cache)
+	}
+
+	slog.Debug("scanning", "root", root)
+	results, err := s.Scan(root)
+	if err != nil {
+		return nil, fmt.Errorf("scanning: %w", err)
+	}
+
+	if cache != nil {
+		hits, misses := cache.Stats()
+		slog.Debug("scan cache", "hits", hits, "parsed", misses)
+		// A cache that can't be written only costs speed next time.
+		if err := cache.Save(); err != nil {
+			slog.Warn("saving scan cache", "err", err)
+		}
+	}
+
+	return &project{root: root, cfg: cfg, cl: cl, results: results}, nil
+}
+
+// buildSettings fills each Go build setting .depviz.yml leaves unset from
+// the flags.
+func buildSettings(cfg, flags config.Build) config.Build {
+	if len(cfg.Tags) == 0 {
+		cfg.Tags = flags.Tags
+	}
+	if cfg.GOOS == "" {
+		cfg.GOOS = flags.GOOS
+	}
+	if cfg.GOARCH == "" {
+		cfg.GOARCH = flags.GOARCH
+	}
+	cfg.AllPlatforms = cfg.AllPlatforms || flags.AllPlatforms
+	return cfg
+}
+
+// cachePath is where a project's scan cache lives, next to the default HTML
+// output.
+func cachePath(root string) string {
+	return filepath.Join(root, ".depviz", "scan-cache.gob")
+}
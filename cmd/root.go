@@ -1,16 +1,18 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
 	"log/slog"
 	"os"
 
+	"github.com/jtoloui/depviz/internal/config"
 	"github.com/spf13/cobra"
 )
 
 var rootCmd = &cobra.Command{
 	Use:           "depviz",
-	Short:         "Visualise Go and JS/TS project dependencies",
+	Short:         "Visualise Go, JS/TS, Python, Rust and Java/Kotlin project dependencies",
 	SilenceUsage:  true,
 	SilenceErrors: true,
 	PersistentPreRun: func(cmd *cobra.Command, args []string) {
@@ -27,15 +29,42 @@ var rootCmd = &cobra.Command{
 var (
 	lang    string
 	verbose bool
+	noCache bool
+	build   config.Build
+	tests   string
 )
 
 func init() {
-	rootCmd.PersistentFlags().StringVarP(&lang, "lang", "l", "go", "language: go, js")
+	rootCmd.PersistentFlags().StringVarP(&lang, "lang", "l", "go", "language: go, js, python, rust, jvm (Java/Kotlin) or multi (Go + JS/TS)")
 	rootCmd.PersistentFlags().BoolVarP(&verbose, "verbose", "v", false, "enable debug logging")
+	rootCmd.PersistentFlags().BoolVar(&noCache, "no-cache", false, "parse every file instead of reusing .depviz/scan-cache.gob")
+	rootCmd.PersistentFlags().StringSliceVar(&build.Tags, "tags", nil, "Go build tags; skips files whose build constraints don't hold")
+	rootCmd.PersistentFlags().StringVar(&build.GOOS, "goos", "", "target GOOS for Go build constraints (default: host)")
+	rootCmd.PersistentFlags().StringVar(&build.GOARCH, "goarch", "", "target GOARCH for Go build constraints (default: host)")
+	rootCmd.PersistentFlags().BoolVar(&build.AllPlatforms, "all-platforms", false, "scan Go files for every platform, recording each file's build constraint")
+	rootCmd.PersistentFlags().StringVar(&tests, "tests", "", "test files: include, exclude or only (default: Go excludes, others include)")
+}
+
+// findingsError marks a check that ran successfully but found problems.
+// The findings are already printed, so Execute exits non-zero without the
+// usage hint.
+type findingsError struct {
+	msg string
+}
+
+func (e *findingsError) Error() string { return e.msg }
+
+func findings(format string, args ...any) error {
+	return &findingsError{msg: fmt.Sprintf(format, args...)}
 }
 
 func Execute() {
 	if err := rootCmd.Execute(); err != nil {
+		var fe *findingsError
+		if errors.As(err, &fe) {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+			os.Exit(1)
+		}
 		fmt.Fprintf(os.Stderr, "Error: %s\nRun 'depviz <command> -h' for help.\n", err)
 		os.Exit(1)
 	}
@@ -2,22 +2,29 @@ package cmd
 
 import (
 	"fmt"
-	"log/slog"
+	"io"
 	"os"
 	"path/filepath"
 
-	"github.com/jtoloui/depviz/internal/classify"
 	"github.com/jtoloui/depviz/internal/cli"
 	"github.com/jtoloui/depviz/internal/config"
+	"github.com/jtoloui/depviz/internal/group"
 	"github.com/jtoloui/depviz/internal/render"
 	"github.com/jtoloui/depviz/internal/scanner"
 	"github.com/spf13/cobra"
 )
 
-var output string
+var (
+	output   string
+	format   string
+	collapse bool
+)
 
 func init() {
-	scanCmd.Flags().StringVarP(&output, "output", "o", "", "output file path (default: <project>/.depviz/deps.html)")
+	scanCmd.Flags().StringVarP(&output, "output", "o", "", "output file path (default: <project>/.depviz/deps.html for html, stdout otherwise)")
+	scanCmd.Flags().StringVarP(&format, "format", "f", "html", "output format: html, json, dot, mermaid")
+	scanCmd.Flags().BoolVar(&collapse, "collapse", false, "dot/mermaid: one node per directory (Go package) instead of per file")
+	addGroupFlag(scanCmd)
 	rootCmd.AddCommand(scanCmd)
 }
 
@@ -26,57 +33,87 @@ var scanCmd = &cobra.Command{
 	Short: "Scan a project and generate a dependency map",
 	Args:  cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		cli.Banner()
-
-		root, err := filepath.Abs(args[0])
+		mode, err := group.Parse(groupBy)
 		if err != nil {
 			return err
 		}
-
-		cfg, err := config.Load(root, lang)
-		if err != nil {
-			return fmt.Errorf("loading config: %w", err)
+		if format != "html" {
+			return scanText(args[0], mode)
 		}
-		slog.Debug("config loaded", "language", cfg.Language, "excludes", len(cfg.Exclude))
 
-		s, err := getScanner(cfg)
+		cli.Banner()
+
+		p, err := loadProject(args[0])
 		if err != nil {
 			return err
 		}
 
-		cl, err := classify.New(cfg)
-		if err != nil {
-			return fmt.Errorf("creating classifier: %w", err)
+		out := resolveOutput(p.cfg, output, p.root)
+		if err := writeFile(out, func(w io.Writer) error {
+			return render.HTML(w, p.root, p.results, p.cl, render.Options{Group: mode, Layout: p.cfg.Layout})
+		}); err != nil {
+			return err
 		}
 
-		slog.Debug("scanning", "root", root)
-		results, err := s.Scan(root)
-		if err != nil {
-			return fmt.Errorf("scanning: %w", err)
-		}
+		cli.ScanResult(p.results, out)
+		return nil
+	},
+}
 
-		out := resolveOutput(cfg, output, root)
-		if err := os.MkdirAll(filepath.Dir(out), 0o755); err != nil {
-			return fmt.Errorf("creating output dir: %w", err)
+// scanText renders a machine-readable format to -o, or to stdout so it can
+// be piped. No banner or summary is printed — stdout is the payload.
+func scanText(path string, mode group.Mode) error {
+	diagram := func(p *project) render.DiagramOptions {
+		return render.DiagramOptions{Collapse: collapse, Group: mode, Layout: p.cfg.Layout}
+	}
+	var renderFn func(w io.Writer, p *project) error
+	switch format {
+	case "json":
+		renderFn = func(w io.Writer, p *project) error {
+			return render.JSON(w, p.root, p.results, p.cl, render.Options{Group: mode, Layout: p.cfg.Layout})
 		}
+	case "dot":
+		renderFn = func(w io.Writer, p *project) error { return render.DOT(w, p.root, p.results, p.cl, diagram(p)) }
+	case "mermaid":
+		renderFn = func(w io.Writer, p *project) error { return render.Mermaid(w, p.root, p.results, p.cl, diagram(p)) }
+	default:
+		return fmt.Errorf("unsupported format: %q", format)
+	}
 
-		f, err := os.Create(out)
-		if err != nil {
-			return fmt.Errorf("creating output: %w", err)
-		}
+	p, err := loadProject(path)
+	if err != nil {
+		return err
+	}
 
-		if err := render.HTML(f, root, results, cl); err != nil {
-			_ = f.Close()
+	if output == "" {
+		if err := renderFn(os.Stdout, p); err != nil {
 			return fmt.Errorf("rendering: %w", err)
 		}
+		return nil
+	}
+	return writeFile(output, func(w io.Writer) error { return renderFn(w, p) })
+}
 
-		if err := f.Close(); err != nil {
-			return fmt.Errorf("closing output: %w", err)
-		}
+// writeFile creates path (and its directory) and renders into it.
+func writeFile(path string, renderFn func(io.Writer) error) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("creating output dir: %w", err)
+	}
 
-		cli.ScanResult(results, out)
-		return nil
-	},
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("creating output: %w", err)
+	}
+
+	if err := renderFn(f); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("rendering: %w", err)
+	}
+
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("closing output: %w", err)
+	}
+	return nil
 }
 
 func getScanner(cfg *config.Config) (scanner.Scanner, error) {
@@ -87,6 +124,12 @@ func getScanner(cfg *config.Config) (scanner.Scanner, error) {
 		return scanner.NewTreeSitterScanner(cfg), nil
 	case "multi":
 		return scanner.NewMultiScanner(cfg), nil
+	case "python":
+		return scanner.NewPythonScanner(cfg), nil
+	case "rust":
+		return scanner.NewRustScanner(cfg), nil
+	case "jvm":
+		return scanner.NewJVMScanner(cfg), nil
 	default:
 		return nil, fmt.Errorf("unsupported language: %q", cfg.Language)
 	}
@@ -8,21 +8,27 @@ import (
 	"net"
 	"net/http"
 	"os/signal"
-	"path/filepath"
 	"syscall"
 	"time"
 
-	"github.com/jtoloui/depviz/internal/classify"
 	"github.com/jtoloui/depviz/internal/cli"
 	"github.com/jtoloui/depviz/internal/config"
+	"github.com/jtoloui/depviz/internal/group"
 	"github.com/jtoloui/depviz/internal/render"
+	"github.com/jtoloui/depviz/internal/scanner"
+	"github.com/jtoloui/depviz/internal/watch"
 	"github.com/spf13/cobra"
 )
 
-var port string
+var (
+	port     string
+	watching bool
+)
 
 func init() {
 	serveCmd.Flags().StringVarP(&port, "port", "p", "3000", "port to serve on")
+	serveCmd.Flags().BoolVarP(&watching, "watch", "w", false, "rescan changed files and live-reload the page")
+	addGroupFlag(serveCmd)
 	rootCmd.AddCommand(serveCmd)
 }
 
@@ -31,44 +37,51 @@ var serveCmd = &cobra.Command{
 	Short: "Scan a project and serve the dependency map in the browser",
 	Args:  cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		cli.Banner()
-
-		root, err := filepath.Abs(args[0])
+		mode, err := group.Parse(groupBy)
 		if err != nil {
 			return err
 		}
 
-		cfg, err := config.Load(root, lang)
-		if err != nil {
-			return fmt.Errorf("loading config: %w", err)
-		}
-		slog.Debug("config loaded", "language", cfg.Language, "excludes", len(cfg.Exclude))
+		cli.Banner()
 
-		s, err := getScanner(cfg)
+		p, err := loadProject(args[0])
 		if err != nil {
 			return err
 		}
+		opts := render.Options{Group: mode, Layout: p.cfg.Layout}
 
-		cl, err := classify.New(cfg)
-		if err != nil {
-			return fmt.Errorf("creating classifier: %w", err)
-		}
+		results := func() []scanner.FileImports { return p.results }
+		page := render.HTML
+		mux := http.NewServeMux()
 
-		slog.Debug("scanning", "root", root)
-		results, err := s.Scan(root)
-		if err != nil {
-			return fmt.Errorf("scanning: %w", err)
+		var w *watch.Watcher
+		hub := watch.NewHub()
+		if watching {
+			s, err := getScanner(p.cfg)
+			if err != nil {
+				return err
+			}
+			fs, ok := s.(scanner.FileScanner)
+			if !ok {
+				return fmt.Errorf("--watch is not supported for language %q", p.cfg.Language)
+			}
+			w, err = watch.New(p.root, fs, p.cfg.Exclude, p.results)
+			if err != nil {
+				return err
+			}
+			results = w.Results
+			page = render.LiveHTML
+			mux.Handle("/events", hub)
 		}
 
-		mux := http.NewServeMux()
-		mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
-			w.Header().Set("Content-Type", "text/html")
-			if err := render.HTML(w, root, results, cl); err != nil {
-				http.Error(w, "render error", http.StatusInternalServerError)
+		mux.HandleFunc("/", func(rw http.ResponseWriter, r *http.Request) {
+			rw.Header().Set("Content-Type", "text/html")
+			if err := page(rw, p.root, results(), p.cl, opts); err != nil {
+				http.Error(rw, "render error", http.StatusInternalServerError)
 			}
 		})
 
-		addr := resolvePort(cfg, port)
+		addr := resolvePort(p.cfg, port)
 		ln, err := net.Listen("tcp", addr)
 		if err != nil {
 			slog.Debug("port in use, picking free port", "tried", addr)
@@ -82,10 +95,25 @@ var serveCmd = &cobra.Command{
 			Handler:           mux,
 			ReadHeaderTimeout: 5 * time.Second,
 		}
+		srv.RegisterOnShutdown(hub.Close)
 
 		ctx, stop := signal.NotifyContext(cmd.Context(), syscall.SIGINT, syscall.SIGTERM)
 		defer stop()
 
+		if w != nil {
+			go func() {
+				_ = w.Run(ctx, func(results []scanner.FileImports, changed []string) {
+					cli.WatchUpdate(changed, len(results))
+					data, err := render.Data(p.root, results, p.cl, opts)
+					if err != nil {
+						slog.Warn("encoding update", "err", err)
+						return
+					}
+					hub.Publish(data)
+				})
+			}()
+		}
+
 		go func() {
 			<-ctx.Done()
 			slog.Info("shutting down server")
@@ -95,7 +123,10 @@ var serveCmd = &cobra.Command{
 		}()
 
 		actualPort := ln.Addr().(*net.TCPAddr).Port
-		cli.ServeResult(results, actualPort)
+		cli.ServeResult(p.results, actualPort)
+		if w != nil {
+			cli.Watching(p.root)
+		}
 
 		if err := srv.Serve(ln); !errors.Is(err, http.ErrServerClosed) {
 			return err
@@ -1,17 +1,18 @@
 package cmd
 
 import (
-	"fmt"
-	"log/slog"
-	"path/filepath"
+	"os"
 
-	"github.com/jtoloui/depviz/internal/classify"
 	"github.com/jtoloui/depviz/internal/cli"
-	"github.com/jtoloui/depviz/internal/config"
+	"github.com/jtoloui/depviz/internal/group"
 	"github.com/spf13/cobra"
 )
 
+var statsJSON bool
+
 func init() {
+	statsCmd.Flags().BoolVar(&statsJSON, "json", false, "print stats as JSON")
+	addGroupFlag(statsCmd)
 	rootCmd.AddCommand(statsCmd)
 }
 
@@ -20,34 +21,20 @@ var statsCmd = &cobra.Command{
 	Short: "Show dependency statistics for a project",
 	Args:  cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		root, err := filepath.Abs(args[0])
+		mode, err := group.Parse(groupBy)
 		if err != nil {
 			return err
 		}
-
-		cfg, err := config.Load(root, lang)
-		if err != nil {
-			return fmt.Errorf("loading config: %w", err)
-		}
-		slog.Debug("config loaded", "language", cfg.Language, "excludes", len(cfg.Exclude))
-
-		s, err := getScanner(cfg)
+		p, err := loadProject(args[0])
 		if err != nil {
 			return err
 		}
 
-		cl, err := classify.New(cfg)
-		if err != nil {
-			return fmt.Errorf("creating classifier: %w", err)
+		results := group.Merge(p.results, mode)
+		if statsJSON {
+			return cli.StatsJSON(os.Stdout, results, p.cl)
 		}
-
-		slog.Debug("scanning", "root", root)
-		results, err := s.Scan(root)
-		if err != nil {
-			return fmt.Errorf("scanning: %w", err)
-		}
-
-		cli.Stats(results, cl)
+		cli.Stats(results, p.cl)
 		return nil
 	},
 }
@@ -0,0 +1,89 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/jtoloui/depviz/internal/cli"
+	"github.com/jtoloui/depviz/internal/config"
+	"github.com/jtoloui/depviz/internal/glob"
+	"github.com/jtoloui/depviz/internal/graph"
+	"github.com/jtoloui/depviz/internal/scanner"
+	"github.com/spf13/cobra"
+)
+
+var (
+	entryFlags      []string
+	unreachableJSON bool
+)
+
+func init() {
+	unreachableCmd.Flags().StringSliceVarP(&entryFlags, "entry", "e", nil, "entry point selector (glob, or regex starting with ^); repeatable")
+	unreachableCmd.Flags().BoolVar(&unreachableJSON, "json", false, "print unreachable files as JSON")
+	rootCmd.AddCommand(unreachableCmd)
+}
+
+var unreachableCmd = &cobra.Command{
+	Use:   "unreachable [path]",
+	Short: "List files no entry point reaches through internal imports",
+	Long: `List files that can't be reached from any entry point by following internal
+imports. Entry points come from entry in .depviz.yml, else --entry, else the
+language's conventional ones (main.go, src/index.*, pages/**, test files, ...).`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		p, err := loadProject(args[0])
+		if err != nil {
+			return err
+		}
+
+		sels := entrySelectors(p.cfg, entryFlags)
+		entries, err := glob.CompileAll(sels)
+		if err != nil {
+			return fmt.Errorf("invalid entry selector: %w", err)
+		}
+		var roots []string
+		for _, fi := range p.results {
+			if entries.Match(filepath.ToSlash(fi.File)) {
+				roots = append(roots, fi.File)
+			}
+		}
+		if len(roots) == 0 {
+			return errors.New("no files match the entry points; set entry in .depviz.yml or pass --entry")
+		}
+
+		g, err := graph.Build(p.root, p.cfg.Layout, p.results)
+		if err != nil {
+			return fmt.Errorf("building graph: %w", err)
+		}
+		reached := internalOnly(g, p).Reachable(roots)
+
+		var orphans []scanner.FileImports
+		for _, fi := range p.results {
+			if !reached[fi.File] {
+				orphans = append(orphans, fi)
+			}
+		}
+		sort.Slice(orphans, func(i, j int) bool { return orphans[i].File < orphans[j].File })
+
+		if unreachableJSON {
+			return cli.UnreachableJSON(os.Stdout, orphans)
+		}
+		cli.Unreachable(orphans, len(roots), len(p.results))
+		return nil
+	},
+}
+
+// entrySelectors picks the entry points: .depviz.yml, then --entry, then
+// the language defaults.
+func entrySelectors(cfg *config.Config, flags []string) []string {
+	if len(cfg.Entry) > 0 {
+		return cfg.Entry
+	}
+	if len(flags) > 0 {
+		return flags
+	}
+	return config.DefaultEntries(cfg.Language)
+}
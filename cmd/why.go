@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/jtoloui/depviz/internal/cli"
+	"github.com/jtoloui/depviz/internal/graph"
+	"github.com/jtoloui/depviz/internal/why"
+	"github.com/spf13/cobra"
+)
+
+var (
+	whyAll   bool
+	whyLimit int
+	whyJSON  bool
+)
+
+func init() {
+	whyCmd.Flags().BoolVarP(&whyAll, "all", "a", false, "show every simple import path, not just the shortest")
+	whyCmd.Flags().IntVar(&whyLimit, "limit", 20, "maximum number of paths to show with --all")
+	whyCmd.Flags().BoolVar(&whyJSON, "json", false, "print the paths as JSON")
+	rootCmd.AddCommand(whyCmd)
+}
+
+var whyCmd = &cobra.Command{
+	Use:   "why <from> <to> [path]",
+	Short: "Explain why one file depends on another file or package",
+	Long: `Print the shortest chain of imports from a source file to a target, with the
+import statement and line behind each hop. The target is a scanned file or an
+external package (e.g. react, github.com/spf13/cobra); subpath imports such as
+lodash/fp count towards lodash. Exits 1 when no path exists.`,
+	Args: cobra.RangeArgs(2, 3),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path := "."
+		if len(args) == 3 {
+			path = args[2]
+		}
+		if whyLimit < 1 {
+			return fmt.Errorf("--limit must be at least 1, got %d", whyLimit)
+		}
+		p, err := loadProject(path)
+		if err != nil {
+			return err
+		}
+
+		g, err := graph.Build(p.root, p.cfg.Layout, p.results)
+		if err != nil {
+			return fmt.Errorf("building graph: %w", err)
+		}
+
+		from := projectFile(p.root, args[0])
+		if g.Node(from) == nil {
+			return fmt.Errorf("%s is not a scanned file", args[0])
+		}
+		to := args[1]
+		if f := projectFile(p.root, to); g.Node(f) != nil {
+			to = f
+		}
+
+		f := why.New(g, p.results)
+		var paths []why.Path
+		truncated := false
+		if whyAll {
+			paths, truncated = f.All(from, to, whyLimit)
+		} else if sp := f.Shortest(from, to); sp != nil {
+			paths = []why.Path{sp}
+		}
+
+		if whyJSON {
+			if err := cli.WhyJSON(os.Stdout, from, to, paths, truncated); err != nil {
+				return err
+			}
+		} else {
+			cli.Why(from, to, paths, truncated)
+		}
+		if len(paths) == 0 {
+			return findings("no import path from %s to %s", from, to)
+		}
+		return nil
+	},
+}
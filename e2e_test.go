@@ -71,7 +71,7 @@ type Config struct{ Name string }
 	if err != nil {
 		t.Fatal(err)
 	}
-	if err := render.HTML(f, dir, results, cl); err != nil {
+	if err := render.HTML(f, dir, results, cl, render.Options{}); err != nil {
 		_ = f.Close()
 		t.Fatalf("render.HTML: %v", err)
 	}
@@ -201,7 +201,7 @@ export type Config = { name: string };
 	if err != nil {
 		t.Fatal(err)
 	}
-	if err := render.HTML(f, dir, results, cl); err != nil {
+	if err := render.HTML(f, dir, results, cl, render.Options{}); err != nil {
 		_ = f.Close()
 		t.Fatal(err)
 	}
@@ -349,7 +349,7 @@ export const app = express();
 	if err != nil {
 		t.Fatal(err)
 	}
-	if err := render.HTML(f, dir, results, cl); err != nil {
+	if err := render.HTML(f, dir, results, cl, render.Options{}); err != nil {
 		_ = f.Close()
 		t.Fatal(err)
 	}
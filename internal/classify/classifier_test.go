@@ -140,11 +140,137 @@ func TestNew_InvalidPrivatePattern(t *testing.T) {
 	}
 }
 
-func TestClassify_UnsupportedLang(t *testing.T) {
+func TestClassify_Python(t *testing.T) {
+	t.Parallel()
+
+	cfg := &config.Config{
+		Language: "python",
+		Classify: config.ClassifyRules{
+			Internal: []string{`^\.`, `^app(\.|$)`},
+		},
+	}
+
+	cl, err := classify.New(cfg)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		imp  string
+		want config.Category
+	}{
+		{"stdlib", "os", config.Stdlib},
+		{"stdlib submodule", "os.path", config.Stdlib},
+		{"future", "__future__", config.Stdlib},
+		{"relative", ".models", config.Internal},
+		{"parent relative", "..", config.Internal},
+		{"own package", "app.services.db", config.Internal},
+		{"package lookalike", "apple", config.External},
+		{"third party", "requests", config.External},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got := cl.Classify(tt.imp)
+			if got != tt.want {
+				t.Errorf("Classify(%q) = %q, want %q", tt.imp, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClassify_Rust(t *testing.T) {
 	t.Parallel()
 
 	cfg := &config.Config{
 		Language: "rust",
+		Classify: config.ClassifyRules{
+			Internal: []string{`^(crate|super|self)(::|$)`, `^my_app(::|$)`},
+		},
+	}
+
+	cl, err := classify.New(cfg)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		imp  string
+		want config.Category
+	}{
+		{"std", "std::collections", config.Stdlib},
+		{"core", "core::fmt", config.Stdlib},
+		{"alloc", "alloc", config.Stdlib},
+		{"absolute std", "::std::io", config.Stdlib},
+		{"crate", "crate::db", config.Internal},
+		{"super", "super", config.Internal},
+		{"mod decl", "self::handlers", config.Internal},
+		{"own crate", "my_app::config", config.Internal},
+		{"crate lookalike", "crates_io", config.External},
+		{"third party", "serde", config.External},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got := cl.Classify(tt.imp)
+			if got != tt.want {
+				t.Errorf("Classify(%q) = %q, want %q", tt.imp, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClassify_JVM(t *testing.T) {
+	t.Parallel()
+
+	cfg := &config.Config{
+		Language: "jvm",
+		Classify: config.ClassifyRules{
+			Internal: []string{`^com\.acme\.app(\.|$)`},
+		},
+	}
+
+	cl, err := classify.New(cfg)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		imp  string
+		lang string
+		want config.Category
+	}{
+		{"java", "java.util.List", "java", config.Stdlib},
+		{"javax", "javax.inject.Inject", "java", config.Stdlib},
+		{"kotlin", "kotlin.collections.List", "kotlin", config.Stdlib},
+		{"internal", "com.acme.app.db.Store", "java", config.Internal},
+		{"internal wildcard", "com.acme.app", "kotlin", config.Internal},
+		{"sibling org package", "com.acme.application", "java", config.External},
+		{"kotlinx is not stdlib", "kotlinx.coroutines.flow", "kotlin", config.External},
+		{"third party", "org.junit.Assert", "java", config.External},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got := cl.ClassifyWithLang(tt.imp, tt.lang)
+			if got != tt.want {
+				t.Errorf("ClassifyWithLang(%q, %q) = %q, want %q", tt.imp, tt.lang, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClassify_UnsupportedLang(t *testing.T) {
+	t.Parallel()
+
+	cfg := &config.Config{
+		Language: "cobol",
 		Classify: config.ClassifyRules{},
 	}
 
@@ -0,0 +1,47 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jtoloui/depviz/internal/rules"
+)
+
+// Check prints rule violations grouped by rule, each with the file, line
+// and import statement that broke it.
+func Check(violations []rules.Violation, ruleCount int) {
+	fmt.Printf("\n  %s%sdepviz check%s\n\n", bold, magenta, reset)
+
+	if len(violations) == 0 {
+		fmt.Printf("  %s%s✓ %d rule(s) passed%s\n\n", bold, green, ruleCount, reset)
+		return
+	}
+
+	fmt.Printf("  %s%s✗ %d rule violation(s)%s\n\n", bold, red, len(violations), reset)
+
+	var order []string
+	byRule := map[string][]rules.Violation{}
+	for _, v := range violations {
+		if _, ok := byRule[v.Rule]; !ok {
+			order = append(order, v.Rule)
+		}
+		byRule[v.Rule] = append(byRule[v.Rule], v)
+	}
+
+	for _, name := range order {
+		fmt.Printf("  %s%s%s\n", bold, name, reset)
+		for _, v := range byRule[name] {
+			snippet := oneLine(v.Import.Snippet)
+			if snippet == "" {
+				snippet = v.Import.Path
+			}
+			fmt.Printf("    %s%s%s  %s%s%s\n", yellow, v.File+lineRef(v.Import.Line), reset, dim, snippet, reset)
+		}
+		fmt.Println()
+	}
+}
+
+// oneLine collapses a multi-line snippet so each finding stays on one row.
+func oneLine(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
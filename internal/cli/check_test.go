@@ -0,0 +1,32 @@
+package cli_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jtoloui/depviz/internal/cli"
+	"github.com/jtoloui/depviz/internal/rules"
+	"github.com/jtoloui/depviz/internal/scanner"
+)
+
+func TestCheck(t *testing.T) {
+	violations := []rules.Violation{
+		{Rule: "ui-no-db", File: "src/ui/Button.tsx", Import: scanner.ImportDetail{Path: "../db/query", Line: 2, Snippet: "import {\n  q\n} from '../db/query';"}},
+		{Rule: "no-lodash", File: "src/app.ts", Import: scanner.ImportDetail{Path: "lodash"}},
+	}
+
+	out := captureStdout(t, func() { cli.Check(violations, 2) })
+
+	for _, want := range []string{"2 rule violation", "ui-no-db", "src/ui/Button.tsx:2", "import { q } from '../db/query';", "no-lodash", "lodash"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q", want)
+		}
+	}
+}
+
+func TestCheckPassed(t *testing.T) {
+	out := captureStdout(t, func() { cli.Check(nil, 3) })
+	if !strings.Contains(out, "3 rule(s) passed") {
+		t.Error("expected pass message")
+	}
+}
@@ -0,0 +1,39 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/jtoloui/depviz/internal/graph"
+)
+
+// Cycles prints each import cycle as an ordered file chain with the line of
+// the import that forms each hop.
+func Cycles(cycles []graph.Cycle) {
+	fmt.Printf("\n  %s%sdepviz cycles%s\n\n", bold, magenta, reset)
+
+	if len(cycles) == 0 {
+		fmt.Printf("  %s%s✓ No import cycles%s\n\n", bold, green, reset)
+		return
+	}
+
+	fmt.Printf("  %s%s✗ %d import cycle(s)%s\n\n", bold, red, len(cycles), reset)
+	for i, c := range cycles {
+		fmt.Printf("  %s%d.%s %d files", bold, i+1, reset, len(c.Files))
+		if len(c.Path) < len(c.Files) {
+			fmt.Printf(" %s(shortest loop: %d)%s", dim, len(c.Path), reset)
+		}
+		fmt.Println()
+		for _, e := range c.Path {
+			fmt.Printf("    %s%s%s → %s%s\n", yellow, e.From, lineRef(e.Import.Line), e.To, reset)
+		}
+		fmt.Println()
+	}
+}
+
+// lineRef formats a line number as a ":N" suffix, or nothing when unknown.
+func lineRef(line int) string {
+	if line == 0 {
+		return ""
+	}
+	return fmt.Sprintf(":%d", line)
+}
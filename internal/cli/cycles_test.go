@@ -0,0 +1,35 @@
+package cli_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jtoloui/depviz/internal/cli"
+	"github.com/jtoloui/depviz/internal/graph"
+	"github.com/jtoloui/depviz/internal/scanner"
+)
+
+func TestCycles(t *testing.T) {
+	cycles := []graph.Cycle{{
+		Files: []string{"src/a.ts", "src/b.ts"},
+		Path: []graph.Edge{
+			{From: "src/a.ts", To: "src/b.ts", Import: scanner.ImportDetail{Path: "./b", Line: 3}},
+			{From: "src/b.ts", To: "src/a.ts", Import: scanner.ImportDetail{Path: "./a", Line: 7}},
+		},
+	}}
+
+	out := captureStdout(t, func() { cli.Cycles(cycles) })
+
+	for _, want := range []string{"1 import cycle", "src/a.ts:3", "src/b.ts:7", "2 files"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q", want)
+		}
+	}
+}
+
+func TestCyclesNone(t *testing.T) {
+	out := captureStdout(t, func() { cli.Cycles(nil) })
+	if !strings.Contains(out, "No import cycles") {
+		t.Error("expected no-cycles message")
+	}
+}
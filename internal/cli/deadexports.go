@@ -0,0 +1,36 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/jtoloui/depviz/internal/usage"
+)
+
+// DeadExports prints each unused export as file:line with its name and kind.
+func DeadExports(dead []usage.DeadExport) {
+	fmt.Printf("\n  %s%sdepviz dead-exports%s\n\n", bold, magenta, reset)
+
+	if len(dead) == 0 {
+		fmt.Printf("  %s%s✓ No unused exports%s\n\n", bold, green, reset)
+		return
+	}
+
+	fmt.Printf("  %s%s%d unused export(s)%s\n", bold, yellow, len(dead), reset)
+	fmt.Printf("  %sCandidates only: check entry points and dynamic use before deleting.%s\n\n", dim, reset)
+	for _, d := range dead {
+		fmt.Printf("    %s%s%s%s  %s%s%s %s%s%s\n", cyan, d.File, lineRef(d.Line), reset, bold, d.Name, reset, dim, d.Kind, reset)
+	}
+	fmt.Println()
+}
+
+// DeadExportsJSON writes unused exports as a JSON array.
+func DeadExportsJSON(w io.Writer, dead []usage.DeadExport) error {
+	if dead == nil {
+		dead = []usage.DeadExport{}
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(dead)
+}
@@ -0,0 +1,49 @@
+package cli_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/jtoloui/depviz/internal/cli"
+	"github.com/jtoloui/depviz/internal/scanner"
+	"github.com/jtoloui/depviz/internal/usage"
+)
+
+func TestDeadExports(t *testing.T) {
+	dead := []usage.DeadExport{
+		{File: "src/util.ts", Name: "parse", Kind: scanner.ExportFunction, Line: 2},
+		{File: "src/util.ts", Name: "MAX", Kind: scanner.ExportConst, Line: 9},
+		{File: "internal/store/store.go", Name: "Close", Kind: scanner.ExportFunction, Line: 7},
+	}
+
+	out := captureStdout(t, func() { cli.DeadExports(dead) })
+
+	for _, want := range []string{"3 unused export", "src/util.ts:2", "parse", "func", "src/util.ts:9", "MAX", "internal/store/store.go:7"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q", want)
+		}
+	}
+}
+
+func TestDeadExportsNone(t *testing.T) {
+	out := captureStdout(t, func() { cli.DeadExports(nil) })
+	if !strings.Contains(out, "No unused exports") {
+		t.Error("expected no-dead-exports message")
+	}
+}
+
+func TestDeadExportsJSON(t *testing.T) {
+	var buf bytes.Buffer
+	if err := cli.DeadExportsJSON(&buf, nil); err != nil {
+		t.Fatal(err)
+	}
+	var got []usage.DeadExport
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if got == nil || len(got) != 0 {
+		t.Errorf("got %v, want empty array", got)
+	}
+}
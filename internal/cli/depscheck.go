@@ -0,0 +1,63 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/jtoloui/depviz/internal/depcheck"
+)
+
+// maxUses caps how many imports are listed under each finding.
+const maxUses = 3
+
+// DepsCheck prints the dependency findings in three sections: missing,
+// dev dependencies used in production code, and unused.
+func DepsCheck(r depcheck.Report) {
+	fmt.Printf("\n  %s%sdepviz deps-check%s\n\n", bold, magenta, reset)
+
+	if r.Len() == 0 {
+		fmt.Printf("  %s%s✓ Imports match the declared dependencies%s\n\n", bold, green, reset)
+		return
+	}
+
+	fmt.Printf("  %s%s✗ %d dependency problem(s)%s\n\n", bold, red, r.Len(), reset)
+	depsSection("Imported but not declared", r.Missing)
+	depsSection("Dev dependencies imported by production code", r.DevInProd)
+	depsSection("Declared but never imported", r.Unused)
+}
+
+func depsSection(title string, fs []depcheck.Finding) {
+	if len(fs) == 0 {
+		return
+	}
+	fmt.Printf("  %s%s (%d)%s\n", bold, title, len(fs), reset)
+	for _, f := range fs {
+		declared := fmt.Sprintf("  %s%s%s", dim, f.Manifest, reset)
+		if d := f.Declared; d != nil {
+			declared = fmt.Sprintf("  %s%s %s in %s%s", dim, d.Kind, d.Version, d.Manifest, reset)
+		}
+		fmt.Printf("    %s%s%s%s\n", yellow, f.Name, reset, declared)
+		for i, u := range f.Uses {
+			if i == maxUses {
+				fmt.Printf("      %s… %d more%s\n", dim, len(f.Uses)-maxUses, reset)
+				break
+			}
+			fmt.Printf("      %s%s%s  %s%s%s\n", cyan, u.File+lineRef(u.Line), reset, dim, u.Import, reset)
+		}
+	}
+	fmt.Println()
+}
+
+// DepsCheckJSON writes the report as {missing, unused, devInProd}, each a
+// JSON array.
+func DepsCheckJSON(w io.Writer, r depcheck.Report) error {
+	for _, list := range []*[]depcheck.Finding{&r.Missing, &r.Unused, &r.DevInProd} {
+		if *list == nil {
+			*list = []depcheck.Finding{}
+		}
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(r)
+}
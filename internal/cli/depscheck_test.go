@@ -0,0 +1,59 @@
+package cli_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/jtoloui/depviz/internal/cli"
+	"github.com/jtoloui/depviz/internal/depcheck"
+	"github.com/jtoloui/depviz/internal/manifest"
+)
+
+func TestDepsCheck(t *testing.T) {
+	var uses []depcheck.Use
+	for _, f := range []string{"src/a.ts", "src/b.ts", "src/c.ts", "src/d.ts", "src/e.ts"} {
+		uses = append(uses, depcheck.Use{File: f, Line: 1, Import: "lodash/fp"})
+	}
+	r := depcheck.Report{
+		Missing:   []depcheck.Finding{{Name: "lodash", Lang: "js", Uses: uses}},
+		Unused:    []depcheck.Finding{{Name: "left-pad", Lang: "js", Declared: &manifest.Dependency{Name: "left-pad", Kind: manifest.Direct, Version: "^1.0.0", Manifest: "package.json"}}},
+		DevInProd: []depcheck.Finding{{Name: "msw", Lang: "js", Declared: &manifest.Dependency{Name: "msw", Kind: manifest.Dev, Version: "^2.0.0", Manifest: "package.json"}, Uses: []depcheck.Use{{File: "src/api.ts", Line: 3, Import: "msw"}}}},
+	}
+
+	out := captureStdout(t, func() { cli.DepsCheck(r) })
+
+	for _, want := range []string{"3 dependency problem", "Imported but not declared (1)", "lodash", "src/a.ts:1", "… 2 more",
+		"Dev dependencies imported by production code", "dev ^2.0.0 in package.json", "src/api.ts:3", "Declared but never imported", "left-pad"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q", want)
+		}
+	}
+	if strings.Contains(out, "src/d.ts") {
+		t.Error("uses past the third should be summarised")
+	}
+}
+
+func TestDepsCheckClean(t *testing.T) {
+	out := captureStdout(t, func() { cli.DepsCheck(depcheck.Report{}) })
+	if !strings.Contains(out, "Imports match the declared dependencies") {
+		t.Error("expected pass message")
+	}
+}
+
+func TestDepsCheckJSON(t *testing.T) {
+	var buf bytes.Buffer
+	if err := cli.DepsCheckJSON(&buf, depcheck.Report{}); err != nil {
+		t.Fatalf("DepsCheckJSON: %v", err)
+	}
+	var got map[string][]any
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	for _, k := range []string{"missing", "unused", "devInProd"} {
+		if list, ok := got[k]; !ok || list == nil {
+			t.Errorf("%s = %v, want an empty array", k, list)
+		}
+	}
+}
@@ -0,0 +1,157 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/jtoloui/depviz/internal/diff"
+)
+
+// Diff prints a coloured summary of dependency changes between two revisions.
+func Diff(rep *diff.Report) {
+	fmt.Printf("\n  %s%sdepviz diff%s %s%s..%s%s\n\n", bold, magenta, reset, dim, rep.Base, rep.Head, reset)
+
+	if rep.Empty() {
+		fmt.Printf("  %s%s✓ No dependency changes%s\n\n", bold, green, reset)
+		return
+	}
+
+	fileList := func(title, sign, colour string, files []string) {
+		if len(files) == 0 {
+			return
+		}
+		fmt.Printf("  %s%s%s (%d)%s\n", bold, cyan, title, len(files), reset)
+		for _, f := range files {
+			fmt.Printf("    %s%s %s%s\n", colour, sign, f, reset)
+		}
+		fmt.Println()
+	}
+	importList := func(title, sign, colour string, changes []diff.ImportChange) {
+		if len(changes) == 0 {
+			return
+		}
+		fmt.Printf("  %s%s%s (%d)%s\n", bold, cyan, title, len(changes), reset)
+		for _, c := range changes {
+			fmt.Printf("    %s%s %s%s %s[%s]%s\n", colour, sign, c.Import, reset, dim, c.Category, reset)
+			for _, f := range c.Files {
+				fmt.Printf("        %s%s%s\n", dim, f, reset)
+			}
+		}
+		fmt.Println()
+	}
+	exportList := func(title, sign, colour string, changes []diff.ExportChange) {
+		if len(changes) == 0 {
+			return
+		}
+		fmt.Printf("  %s%s%s (%d)%s\n", bold, cyan, title, len(changes), reset)
+		for _, e := range changes {
+			fmt.Printf("    %s%s %s%s %s%s (%s)%s\n", colour, sign, e.Name, reset, dim, e.File, e.Kind, reset)
+		}
+		fmt.Println()
+	}
+
+	fileList("New packages", "+", yellow, rep.NewPackages)
+	fileList("Dropped packages", "-", dim, rep.DroppedPackages)
+	importList("Added imports", "+", green, rep.AddedImports)
+	importList("Removed imports", "-", red, rep.RemovedImports)
+	exportList("Added exports", "+", green, rep.AddedExports)
+	exportList("Removed exports", "-", red, rep.RemovedExports)
+	fileList("Added files", "+", green, rep.AddedFiles)
+	fileList("Removed files", "-", red, rep.RemovedFiles)
+
+	if len(rep.NewCycles) > 0 {
+		fmt.Printf("  %s%s✗ %d new import cycle(s)%s\n", bold, red, len(rep.NewCycles), reset)
+		for _, c := range rep.NewCycles {
+			for _, e := range c.Path {
+				fmt.Printf("    %s%s%s → %s%s\n", yellow, e.From, lineRef(e.Import.Line), e.To, reset)
+			}
+			fmt.Println()
+		}
+	}
+}
+
+// DiffJSON writes the diff report as indented JSON.
+func DiffJSON(w io.Writer, rep *diff.Report) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(rep)
+}
+
+// DiffMarkdown writes the diff report as Markdown, suitable for a PR comment.
+func DiffMarkdown(w io.Writer, rep *diff.Report) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "## Dependency changes `%s`..`%s`\n\n", rep.Base, rep.Head)
+
+	if rep.Empty() {
+		b.WriteString("No dependency changes.\n")
+		_, err := io.WriteString(w, b.String())
+		return err
+	}
+
+	list := func(title string, items []string) {
+		if len(items) == 0 {
+			return
+		}
+		fmt.Fprintf(&b, "### %s (%d)\n\n", title, len(items))
+		for _, it := range items {
+			fmt.Fprintf(&b, "- `%s`\n", it)
+		}
+		b.WriteString("\n")
+	}
+	imports := func(title string, changes []diff.ImportChange) {
+		if len(changes) == 0 {
+			return
+		}
+		fmt.Fprintf(&b, "### %s (%d)\n\n| Import | Category | Files |\n| --- | --- | --- |\n", title, len(changes))
+		for _, c := range changes {
+			fmt.Fprintf(&b, "| `%s` | %s | %s |\n", c.Import, c.Category, codeList(c.Files))
+		}
+		b.WriteString("\n")
+	}
+	exports := func(title string, changes []diff.ExportChange) {
+		if len(changes) == 0 {
+			return
+		}
+		fmt.Fprintf(&b, "### %s (%d)\n\n| File | Symbol | Kind |\n| --- | --- | --- |\n", title, len(changes))
+		for _, e := range changes {
+			fmt.Fprintf(&b, "| `%s` | `%s` | %s |\n", e.File, e.Name, e.Kind)
+		}
+		b.WriteString("\n")
+	}
+
+	if len(rep.NewCycles) > 0 {
+		fmt.Fprintf(&b, "### :warning: New import cycles (%d)\n\n", len(rep.NewCycles))
+		for i, c := range rep.NewCycles {
+			var hops []string
+			for _, e := range c.Path {
+				hops = append(hops, fmt.Sprintf("`%s%s`", e.From, lineRef(e.Import.Line)))
+			}
+			if len(c.Path) > 0 {
+				hops = append(hops, fmt.Sprintf("`%s`", c.Path[len(c.Path)-1].To))
+			}
+			fmt.Fprintf(&b, "%d. %s\n", i+1, strings.Join(hops, " → "))
+		}
+		b.WriteString("\n")
+	}
+	list("New packages", rep.NewPackages)
+	list("Dropped packages", rep.DroppedPackages)
+	imports("Added imports", rep.AddedImports)
+	imports("Removed imports", rep.RemovedImports)
+	exports("Added exports", rep.AddedExports)
+	exports("Removed exports", rep.RemovedExports)
+	list("Added files", rep.AddedFiles)
+	list("Removed files", rep.RemovedFiles)
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func codeList(items []string) string {
+	quoted := make([]string, len(items))
+	for i, it := range items {
+		quoted[i] = "`" + it + "`"
+	}
+	return strings.Join(quoted, ", ")
+}
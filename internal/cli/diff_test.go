@@ -0,0 +1,90 @@
+package cli_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/jtoloui/depviz/internal/cli"
+	"github.com/jtoloui/depviz/internal/config"
+	"github.com/jtoloui/depviz/internal/diff"
+	"github.com/jtoloui/depviz/internal/graph"
+	"github.com/jtoloui/depviz/internal/scanner"
+)
+
+func sampleDiff() *diff.Report {
+	return &diff.Report{
+		Base:           "main",
+		Head:           "HEAD",
+		AddedFiles:     []string{"src/new.ts"},
+		RemovedFiles:   []string{},
+		NewPackages:    []string{"zod"},
+		AddedImports:   []diff.ImportChange{{Import: "zod", Category: config.External, Files: []string{"src/new.ts"}}},
+		RemovedImports: []diff.ImportChange{{Import: "yup", Category: config.External, Files: []string{"src/form.ts"}}},
+		AddedExports:   []diff.ExportChange{{File: "src/form.ts", Name: "schema", Kind: scanner.ExportConst}},
+		NewCycles: []graph.Cycle{{
+			Files: []string{"src/a.ts", "src/b.ts"},
+			Path: []graph.Edge{
+				{From: "src/a.ts", To: "src/b.ts", Import: scanner.ImportDetail{Path: "./b", Line: 2}},
+				{From: "src/b.ts", To: "src/a.ts", Import: scanner.ImportDetail{Path: "./a", Line: 4}},
+			},
+		}},
+	}
+}
+
+func TestDiff(t *testing.T) {
+	out := captureStdout(t, func() { cli.Diff(sampleDiff()) })
+
+	for _, want := range []string{"main..HEAD", "New packages (1)", "+ zod", "- yup", "schema", "src/a.ts:2", "1 new import cycle"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q", want)
+		}
+	}
+}
+
+func TestDiffNone(t *testing.T) {
+	out := captureStdout(t, func() { cli.Diff(&diff.Report{Base: "a", Head: "b"}) })
+	if !strings.Contains(out, "No dependency changes") {
+		t.Error("expected no-changes message")
+	}
+}
+
+func TestDiffMarkdown(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	if err := cli.DiffMarkdown(&buf, sampleDiff()); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	for _, want := range []string{
+		"## Dependency changes `main`..`HEAD`",
+		"| `zod` | external | `src/new.ts` |",
+		"| `src/form.ts` | `schema` | const |",
+		"`src/a.ts:2` → `src/b.ts:4` → `src/a.ts`",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("markdown missing %q\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "Removed files") {
+		t.Error("empty sections should be omitted")
+	}
+}
+
+func TestDiffJSON(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	if err := cli.DiffJSON(&buf, sampleDiff()); err != nil {
+		t.Fatal(err)
+	}
+	var got diff.Report
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if len(got.NewPackages) != 1 || got.NewPackages[0] != "zod" {
+		t.Errorf("NewPackages = %v", got.NewPackages)
+	}
+}
@@ -0,0 +1,70 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// Impact prints every file that transitively depends on targets, grouped
+// by how many imports away it is.
+func Impact(targets []string, levels [][]string) {
+	fmt.Printf("\n  %s%sdepviz impact%s\n\n", bold, magenta, reset)
+	if len(targets) == 0 {
+		fmt.Printf("  %s%s✓ No scanned files changed%s\n\n", bold, green, reset)
+		return
+	}
+	for _, t := range targets {
+		fmt.Printf("  %s%s%s\n", cyan, t, reset)
+	}
+	fmt.Println()
+
+	total := 0
+	for _, l := range levels {
+		total += len(l)
+	}
+	if total == 0 {
+		fmt.Printf("  %s%s✓ Nothing depends on this%s\n\n", bold, green, reset)
+		return
+	}
+
+	fmt.Printf("  %s%s%d dependent file(s)%s %s(%d level(s) deep)%s\n\n", bold, yellow, total, reset, dim, len(levels), reset)
+	for i, l := range levels {
+		label := "direct"
+		if i > 0 {
+			label = fmt.Sprintf("depth %d", i+1)
+		}
+		fmt.Printf("  %s%s%s %s(%d)%s\n", bold, label, reset, dim, len(l), reset)
+		for _, f := range l {
+			fmt.Printf("    %s\n", f)
+		}
+		fmt.Println()
+	}
+}
+
+type impactLevel struct {
+	Depth int      `json:"depth"`
+	Files []string `json:"files"`
+}
+
+type impactReport struct {
+	Targets []string      `json:"targets"`
+	Total   int           `json:"total"`
+	Levels  []impactLevel `json:"levels"`
+}
+
+// ImpactJSON writes the impact report as JSON: the targets, the total
+// number of dependents and the files at each depth.
+func ImpactJSON(w io.Writer, targets []string, levels [][]string) error {
+	rep := impactReport{Targets: targets, Levels: []impactLevel{}}
+	if rep.Targets == nil {
+		rep.Targets = []string{}
+	}
+	for i, l := range levels {
+		rep.Total += len(l)
+		rep.Levels = append(rep.Levels, impactLevel{Depth: i + 1, Files: l})
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(rep)
+}
@@ -0,0 +1,55 @@
+package cli_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/jtoloui/depviz/internal/cli"
+)
+
+func TestImpact(t *testing.T) {
+	levels := [][]string{{"src/page.ts"}, {"src/app.ts", "src/other.ts"}}
+
+	out := captureStdout(t, func() { cli.Impact([]string{"src/button.ts"}, levels) })
+
+	for _, want := range []string{"src/button.ts", "3 dependent file", "2 level", "direct", "(1)", "depth 2", "(2)", "src/other.ts"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q", want)
+		}
+	}
+}
+
+func TestImpactNone(t *testing.T) {
+	out := captureStdout(t, func() { cli.Impact([]string{"main.go"}, nil) })
+	if !strings.Contains(out, "Nothing depends on this") {
+		t.Error("expected no-dependents message")
+	}
+
+	out = captureStdout(t, func() { cli.Impact(nil, nil) })
+	if !strings.Contains(out, "No scanned files changed") {
+		t.Error("expected no-targets message")
+	}
+}
+
+func TestImpactJSON(t *testing.T) {
+	var buf bytes.Buffer
+	if err := cli.ImpactJSON(&buf, []string{"a.ts"}, [][]string{{"b.ts", "c.ts"}, {"d.ts"}}); err != nil {
+		t.Fatal(err)
+	}
+	var got struct {
+		Targets []string `json:"targets"`
+		Total   int      `json:"total"`
+		Levels  []struct {
+			Depth int      `json:"depth"`
+			Files []string `json:"files"`
+		} `json:"levels"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if got.Total != 3 || len(got.Levels) != 2 || got.Levels[1].Depth != 2 || got.Levels[1].Files[0] != "d.ts" {
+		t.Errorf("got %+v", got)
+	}
+}
@@ -0,0 +1,45 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/jtoloui/depviz/internal/metrics"
+)
+
+// Metrics prints the package metrics table. Distances of 0.5 and more are
+// highlighted: those packages sit in the zone of pain (stable and concrete)
+// or the zone of uselessness (abstract and unstable).
+func Metrics(pkgs []metrics.Package) {
+	fmt.Printf("\n  %s%sdepviz metrics%s\n\n", bold, magenta, reset)
+	if len(pkgs) == 0 {
+		fmt.Printf("  %sNo packages import one another.%s\n\n", dim, reset)
+		return
+	}
+
+	width := len("package")
+	for _, p := range pkgs {
+		width = max(width, len(p.Name))
+	}
+	fmt.Printf("  %s%-*s  %5s  %4s  %4s  %5s  %5s  %5s%s\n", bold, width, "package", "files", "Ca", "Ce", "I", "A", "D", reset)
+	for _, p := range pkgs {
+		colour := ""
+		if p.Distance >= 0.5 {
+			colour = yellow
+		}
+		fmt.Printf("  %-*s  %5d  %4d  %4d  %5.2f  %5.2f  %s%5.2f%s\n",
+			width, p.Name, p.Files, p.Ca, p.Ce, p.Instability, p.Abstractness, colour, p.Distance, reset)
+	}
+	fmt.Printf("\n  %sCa/Ce: packages importing / imported. I = Ce/(Ca+Ce), A = abstract/public exports, D = |A+I-1|.%s\n\n", dim, reset)
+}
+
+// MetricsJSON writes the package metrics as a JSON array.
+func MetricsJSON(w io.Writer, pkgs []metrics.Package) error {
+	if pkgs == nil {
+		pkgs = []metrics.Package{}
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(pkgs)
+}
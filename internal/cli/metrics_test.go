@@ -0,0 +1,58 @@
+package cli_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/jtoloui/depviz/internal/cli"
+	"github.com/jtoloui/depviz/internal/metrics"
+)
+
+func TestMetrics(t *testing.T) {
+	pkgs := []metrics.Package{
+		{Name: "internal/store", Files: 3, Ca: 4, Ce: 0, Instability: 0, Abstractness: 0, Distance: 1},
+		{Name: "cmd", Files: 2, Ca: 0, Ce: 4, Instability: 1, Abstractness: 0, Distance: 0},
+	}
+
+	out := captureStdout(t, func() { cli.Metrics(pkgs) })
+
+	for _, want := range []string{"package", "Ca", "Ce", "internal/store", "1.00", "0.00", "D = |A+I-1|"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q", want)
+		}
+	}
+	if strings.Index(out, "internal/store") > strings.Index(out, "cmd ") {
+		t.Error("rows should keep the given order")
+	}
+}
+
+func TestMetricsNone(t *testing.T) {
+	out := captureStdout(t, func() { cli.Metrics(nil) })
+	if !strings.Contains(out, "No packages import one another") {
+		t.Error("expected empty message")
+	}
+}
+
+func TestMetricsJSON(t *testing.T) {
+	var buf bytes.Buffer
+	if err := cli.MetricsJSON(&buf, nil); err != nil {
+		t.Fatal(err)
+	}
+	if strings.TrimSpace(buf.String()) != "[]" {
+		t.Errorf("nil metrics = %s, want []", buf.String())
+	}
+
+	buf.Reset()
+	if err := cli.MetricsJSON(&buf, []metrics.Package{{Name: "a", Ca: 1, Instability: 0.5}}); err != nil {
+		t.Fatal(err)
+	}
+	var got []map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if len(got) != 1 || got[0]["name"] != "a" || got[0]["instability"] != 0.5 || got[0]["ca"] != 1.0 {
+		t.Errorf("got %v", got)
+	}
+}
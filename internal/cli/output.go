@@ -11,6 +11,7 @@ const (
 	reset   = "\033[0m"
 	bold    = "\033[1m"
 	dim     = "\033[2m"
+	red     = "\033[31m"
 	green   = "\033[32m"
 	cyan    = "\033[36m"
 	magenta = "\033[35m"
@@ -50,6 +51,29 @@ func ServeResult(results []scanner.FileImports, port int) {
 	fmt.Printf("\n  %s%s→%s http://localhost:%d\n\n", bold, green, reset, port)
 }
 
+// Watching prints a note that serve is watching root for changes.
+func Watching(root string) {
+	fmt.Printf("  %s%s👀 Watching%s %s%s%s for changes\n\n", bold, cyan, reset, dim, root, reset)
+}
+
+// WatchUpdate prints a line for each rescan triggered by file changes.
+func WatchUpdate(changed []string, total int) {
+	label := changed[0]
+	if len(changed) > 1 {
+		label = fmt.Sprintf("%s and %d more", changed[0], len(changed)-1)
+	}
+	fmt.Printf("  %s↻%s %s %s— %d files%s\n", green, reset, label, dim, total, reset)
+}
+
+// CacheCleaned reports the outcome of `depviz cache clean`.
+func CacheCleaned(path string, removed bool) {
+	if !removed {
+		fmt.Printf("  %s%s✓ No cache to clean%s %s%s%s\n\n", bold, green, reset, dim, path, reset)
+		return
+	}
+	fmt.Printf("  %s%s✓ Cache removed%s %s%s%s\n\n", bold, green, reset, dim, path, reset)
+}
+
 // InitResult prints a coloured summary after generating config.
 func InitResult(lang, path string) {
 	fmt.Printf("  %s%s✓ Config created%s\n", bold, green, reset)
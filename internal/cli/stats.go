@@ -1,81 +1,154 @@
 package cli
 
 import (
+	"encoding/json"
 	"fmt"
+	"io"
 	"sort"
 	"strings"
 
 	"github.com/jtoloui/depviz/internal/classify"
+	"github.com/jtoloui/depviz/internal/config"
 	"github.com/jtoloui/depviz/internal/scanner"
 )
 
 const barWidth = 20
 
-// Stats prints a coloured terminal stats dashboard.
-func Stats(results []scanner.FileImports, cl *classify.Classifier) {
-	totalFiles := len(results)
-	totalImports := 0
-	totalExports := 0
-	totalLines := 0
-	langCount := map[string]int{}
-	catCount := map[string]int{}
+// hotspotMin is the import count at which a file is listed as a coupling hotspot.
+const hotspotMin = 8
+
+// Count is a labelled tally, e.g. an import and how many files use it.
+type Count struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+// StatsReport is the data behind the stats dashboard.
+type StatsReport struct {
+	Files      int            `json:"files"`
+	TestFiles  int            `json:"testFiles"`
+	Lines      int            `json:"lines"`
+	Imports    int            `json:"imports"`
+	Exports    int            `json:"exports"`
+	AvgPerFile int            `json:"avgPerFile"`
+	Languages  map[string]int `json:"languages"`
+	Categories map[string]int `json:"categories"`
+	TopImports []Count        `json:"topImports"`
+	Hotspots   []Count        `json:"hotspots"`
+	// TestOnly are the external and private imports only test files use,
+	// with how many test files import each.
+	TestOnly []Count `json:"testOnly"`
+}
+
+// ComputeStats tallies totals, language and category breakdowns, the most
+// imported packages, coupling hotspots and the dependencies only tests use.
+func ComputeStats(results []scanner.FileImports, cl *classify.Classifier) StatsReport {
+	rep := StatsReport{
+		Files:      len(results),
+		Languages:  map[string]int{},
+		Categories: map[string]int{},
+		TopImports: []Count{},
+		Hotspots:   []Count{},
+		TestOnly:   []Count{},
+	}
 	impFreq := map[string]int{}
+	testUses := map[string]int{}
+	prodUses := map[string]bool{}
 
 	for _, r := range results {
-		totalImports += len(r.Imports)
-		totalExports += len(r.Exports)
-		totalLines += r.Lines
+		rep.Imports += len(r.Imports)
+		rep.Exports += len(r.Exports)
+		rep.Lines += r.Lines
 		lang := r.Lang
 		if lang == "" {
 			lang = "go"
 		}
-		langCount[lang]++
+		rep.Languages[lang]++
+		if r.IsTest {
+			rep.TestFiles++
+		}
 		for _, imp := range r.Imports {
-			cat := string(cl.ClassifyWithLang(imp, r.Lang))
-			catCount[cat]++
+			cat := cl.ClassifyWithLang(imp, r.Lang)
+			rep.Categories[string(cat)]++
 			impFreq[imp]++
+			if cat != config.External && cat != config.Private {
+				continue
+			}
+			if r.IsTest {
+				testUses[imp]++
+			} else {
+				prodUses[imp] = true
+			}
+		}
+		if len(r.Imports) >= hotspotMin {
+			rep.Hotspots = append(rep.Hotspots, Count{r.File, len(r.Imports)})
 		}
 	}
 
-	avg := 0
-	if totalFiles > 0 {
-		avg = totalImports / totalFiles
+	if rep.Files > 0 {
+		rep.AvgPerFile = rep.Imports / rep.Files
 	}
+	rep.TopImports = append(rep.TopImports, topN(impFreq, 5)...)
+	sortCounts(rep.Hotspots)
+	for imp, n := range testUses {
+		if !prodUses[imp] {
+			rep.TestOnly = append(rep.TestOnly, Count{imp, n})
+		}
+	}
+	sortCounts(rep.TestOnly)
+	return rep
+}
+
+// Stats prints a coloured terminal stats dashboard.
+func Stats(results []scanner.FileImports, cl *classify.Classifier) {
+	rep := ComputeStats(results, cl)
 
 	fmt.Printf("\n  %s%sdepviz stats%s\n\n", bold, magenta, reset)
 
-	fmt.Printf("  %sFiles%s      %-12d %sLines%s    %d\n", cyan, reset, totalFiles, cyan, reset, totalLines)
-	fmt.Printf("  %sImports%s    %-12d %sExports%s  %d\n", cyan, reset, totalImports, cyan, reset, totalExports)
-	fmt.Printf("  %sAvg/file%s   %d\n\n", cyan, reset, avg)
+	files := fmt.Sprint(rep.Files)
+	if rep.TestFiles > 0 {
+		files += fmt.Sprintf(" (%d test)", rep.TestFiles)
+	}
+	fmt.Printf("  %sFiles%s      %-12s %sLines%s    %d\n", cyan, reset, files, cyan, reset, rep.Lines)
+	fmt.Printf("  %sImports%s    %-12d %sExports%s  %d\n", cyan, reset, rep.Imports, cyan, reset, rep.Exports)
+	fmt.Printf("  %sAvg/file%s   %d\n\n", cyan, reset, rep.AvgPerFile)
 
 	fmt.Printf("  %s%sLanguages%s\n", bold, cyan, reset)
-	printBar(langCount, totalFiles, cyan)
+	printBar(rep.Languages, rep.Files, cyan)
 
 	fmt.Printf("  %s%sCategories%s\n", bold, cyan, reset)
-	printBarColoured(catCount, totalImports)
+	printBarColoured(rep.Categories, rep.Imports)
 
 	fmt.Printf("  %s%sTop 5 Imports%s\n", bold, cyan, reset)
-	for _, kv := range topN(impFreq, 5) {
-		fmt.Printf("    %s%-45s%s %d files\n", dim, kv.key, reset, kv.val)
+	for _, c := range rep.TopImports {
+		fmt.Printf("    %s%-45s%s %d files\n", dim, c.Name, reset, c.Count)
 	}
 	fmt.Println()
 
-	var spots []kv
-	for _, r := range results {
-		if len(r.Imports) >= 8 {
-			spots = append(spots, kv{r.File, len(r.Imports)})
+	if len(rep.TestOnly) > 0 {
+		fmt.Printf("  %s%sTest-only Dependencies%s\n", bold, cyan, reset)
+		for _, c := range rep.TestOnly {
+			fmt.Printf("    %s%-45s%s %d test files\n", dim, c.Name, reset, c.Count)
 		}
+		fmt.Println()
 	}
-	if len(spots) > 0 {
-		sort.Slice(spots, func(i, j int) bool { return spots[i].val > spots[j].val })
+
+	if len(rep.Hotspots) > 0 {
 		fmt.Printf("  %s%sCoupling Hotspots%s\n", bold, yellow, reset)
-		for _, s := range spots {
-			fmt.Printf("    %s%-45s%s %d imports\n", dim, s.key, reset, s.val)
+		for _, c := range rep.Hotspots {
+			fmt.Printf("    %s%-45s%s %d imports\n", dim, c.Name, reset, c.Count)
 		}
 		fmt.Println()
 	}
 }
 
+// StatsJSON writes the stats report as indented JSON.
+func StatsJSON(w io.Writer, results []scanner.FileImports, cl *classify.Classifier) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(ComputeStats(results, cl))
+}
+
 func printBar(counts map[string]int, total int, colour string) {
 	for label, count := range counts {
 		pct, filled := barCalc(count, total)
@@ -114,19 +187,24 @@ func barCalc(count, total int) (pct, filled int) {
 	return
 }
 
-type kv struct {
-	key string
-	val int
-}
-
-func topN(m map[string]int, n int) []kv {
-	items := make([]kv, 0, len(m))
+func topN(m map[string]int, n int) []Count {
+	items := make([]Count, 0, len(m))
 	for k, v := range m {
-		items = append(items, kv{k, v})
+		items = append(items, Count{k, v})
 	}
-	sort.Slice(items, func(i, j int) bool { return items[i].val > items[j].val })
+	sortCounts(items)
 	if len(items) > n {
 		items = items[:n]
 	}
 	return items
 }
+
+// sortCounts orders by count descending, then name, so output is stable.
+func sortCounts(items []Count) {
+	sort.Slice(items, func(i, j int) bool {
+		if items[i].Count != items[j].Count {
+			return items[i].Count > items[j].Count
+		}
+		return items[i].Name < items[j].Name
+	})
+}
@@ -2,6 +2,7 @@ package cli_test
 
 import (
 	"bytes"
+	"encoding/json"
 	"os"
 	"strings"
 	"testing"
@@ -159,6 +160,83 @@ func TestStatsNoImports(t *testing.T) {
 	}
 }
 
+func TestStatsJSON(t *testing.T) {
+	t.Parallel()
+
+	cl, err := classify.New(&config.Config{Language: "go"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	results := []scanner.FileImports{
+		{File: "a.go", Lang: "go", Imports: []string{"fmt", "os", "github.com/x/y"}, Lines: 10},
+		{File: "b.go", Lang: "go", Imports: []string{"fmt", "a", "b", "c", "d", "e", "f", "g"}, Exports: []scanner.ExportDetail{{Name: "B"}}, Lines: 5},
+	}
+
+	var buf bytes.Buffer
+	if err := cli.StatsJSON(&buf, results, cl); err != nil {
+		t.Fatalf("StatsJSON: %v", err)
+	}
+
+	var got cli.StatsReport
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+
+	if got.Files != 2 || got.Lines != 15 || got.Imports != 11 || got.Exports != 1 || got.AvgPerFile != 5 {
+		t.Errorf("totals = %+v", got)
+	}
+	if got.Languages["go"] != 2 {
+		t.Errorf("languages = %v", got.Languages)
+	}
+	if got.Categories["stdlib"] != 10 || got.Categories["external"] != 1 {
+		t.Errorf("categories = %v", got.Categories)
+	}
+	if len(got.TopImports) == 0 || got.TopImports[0] != (cli.Count{Name: "fmt", Count: 2}) {
+		t.Errorf("top imports = %v", got.TopImports)
+	}
+	if len(got.Hotspots) != 1 || got.Hotspots[0].Name != "b.go" {
+		t.Errorf("hotspots = %v", got.Hotspots)
+	}
+	if got.TestFiles != 0 || got.TestOnly == nil || len(got.TestOnly) != 0 {
+		t.Errorf("tests = %d %v, want 0 []", got.TestFiles, got.TestOnly)
+	}
+}
+
+func TestStats_TestOnly(t *testing.T) {
+	cl, err := classify.New(&config.Config{
+		Language: "go",
+		Classify: config.ClassifyRules{Internal: []string{`^example\.com/app`}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	results := []scanner.FileImports{
+		{File: "a.go", Lang: "go", Imports: []string{"fmt", "github.com/spf13/cobra"}},
+		{File: "a_test.go", Lang: "go", IsTest: true, Imports: []string{"testing", "github.com/stretchr/testify/assert", "github.com/spf13/cobra"}},
+		{File: "b_test.go", Lang: "go", IsTest: true, Imports: []string{"example.com/app/b", "github.com/stretchr/testify/assert", "github.com/google/go-cmp/cmp"}},
+	}
+
+	rep := cli.ComputeStats(results, cl)
+	want := []cli.Count{{Name: "github.com/stretchr/testify/assert", Count: 2}, {Name: "github.com/google/go-cmp/cmp", Count: 1}}
+	if rep.TestFiles != 2 || len(rep.TestOnly) != len(want) {
+		t.Fatalf("test files %d, test-only %v", rep.TestFiles, rep.TestOnly)
+	}
+	for i := range want {
+		if rep.TestOnly[i] != want[i] {
+			t.Errorf("test-only[%d] = %v, want %v", i, rep.TestOnly[i], want[i])
+		}
+	}
+
+	out := captureStdout(t, func() { cli.Stats(results, cl) })
+	for _, s := range []string{"3 (2 test)", "Test-only Dependencies", "testify/assert"} {
+		if !strings.Contains(out, s) {
+			t.Errorf("output missing %q", s)
+		}
+	}
+}
+
 func TestBanner(t *testing.T) {
 	out := captureStdout(t, func() { cli.Banner() })
 	if len(out) == 0 {
@@ -0,0 +1,48 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/jtoloui/depviz/internal/scanner"
+)
+
+// Unreachable prints the files no entry point reaches, with their line
+// counts so the biggest dead weight stands out.
+func Unreachable(orphans []scanner.FileImports, entries, total int) {
+	fmt.Printf("\n  %s%sdepviz unreachable%s\n\n", bold, magenta, reset)
+
+	if len(orphans) == 0 {
+		fmt.Printf("  %s%s✓ All %d files reachable from %d entry point(s)%s\n\n", bold, green, total, entries, reset)
+		return
+	}
+
+	lines := 0
+	for _, fi := range orphans {
+		lines += fi.Lines
+	}
+	fmt.Printf("  %s%s%d of %d files unreachable%s %s(%d lines, from %d entry point(s))%s\n\n",
+		bold, yellow, len(orphans), total, reset, dim, lines, entries, reset)
+	for _, fi := range orphans {
+		fmt.Printf("    %s%s%s  %s%d lines%s\n", cyan, fi.File, reset, dim, fi.Lines, reset)
+	}
+	fmt.Println()
+}
+
+type unreachableFile struct {
+	File  string `json:"file"`
+	Lines int    `json:"lines"`
+}
+
+// UnreachableJSON writes the unreachable files as a JSON array of
+// {file, lines}.
+func UnreachableJSON(w io.Writer, orphans []scanner.FileImports) error {
+	out := make([]unreachableFile, len(orphans))
+	for i, fi := range orphans {
+		out[i] = unreachableFile{File: fi.File, Lines: fi.Lines}
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(out)
+}
@@ -0,0 +1,47 @@
+package cli_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/jtoloui/depviz/internal/cli"
+	"github.com/jtoloui/depviz/internal/scanner"
+)
+
+func TestUnreachable(t *testing.T) {
+	orphans := []scanner.FileImports{
+		{File: "src/old/legacy.ts", Lines: 120},
+		{File: "src/utils/unused.ts", Lines: 30},
+	}
+
+	out := captureStdout(t, func() { cli.Unreachable(orphans, 3, 40) })
+
+	for _, want := range []string{"2 of 40 files unreachable", "150 lines", "3 entry point", "src/old/legacy.ts", "120 lines", "src/utils/unused.ts"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q", want)
+		}
+	}
+}
+
+func TestUnreachableNone(t *testing.T) {
+	out := captureStdout(t, func() { cli.Unreachable(nil, 1, 12) })
+	if !strings.Contains(out, "All 12 files reachable") {
+		t.Error("expected all-reachable message")
+	}
+}
+
+func TestUnreachableJSON(t *testing.T) {
+	var buf bytes.Buffer
+	if err := cli.UnreachableJSON(&buf, []scanner.FileImports{{File: "a.ts", Lines: 5, Imports: []string{"x"}}}); err != nil {
+		t.Fatal(err)
+	}
+	var got []map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if len(got) != 1 || got[0]["file"] != "a.ts" || got[0]["lines"] != float64(5) || len(got[0]) != 2 {
+		t.Errorf("got %v", got)
+	}
+}
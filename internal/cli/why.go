@@ -0,0 +1,67 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/jtoloui/depviz/internal/why"
+)
+
+// Why prints each import chain from one file to another file or package,
+// one hop per line with the import statement that makes it.
+func Why(from, to string, paths []why.Path, truncated bool) {
+	fmt.Printf("\n  %s%sdepviz why%s\n\n", bold, magenta, reset)
+	fmt.Printf("  %s%s%s %s→%s %s%s%s\n\n", cyan, from, reset, dim, reset, cyan, to, reset)
+	if len(paths) == 0 {
+		fmt.Printf("  %s%s✗ No import path%s\n\n", bold, red, reset)
+		return
+	}
+
+	for i, p := range paths {
+		if len(paths) > 1 {
+			fmt.Printf("  %s%d.%s %s(%d hop(s))%s\n", bold, i+1, reset, dim, len(p), reset)
+		} else {
+			fmt.Printf("  %s%d hop(s)%s\n", bold, len(p), reset)
+		}
+		for _, e := range p {
+			fmt.Printf("    %s%s\n", e.From, lineRef(e.Import.Line))
+			if e.Import.Snippet != "" {
+				fmt.Printf("      %s%s%s\n", dim, e.Import.Snippet, reset)
+			}
+		}
+		fmt.Printf("    %s→ %s%s\n\n", cyan, p[len(p)-1].To, reset)
+	}
+	if truncated {
+		fmt.Printf("  %sShowing the first %d paths; raise --limit to see more.%s\n\n", dim, len(paths), reset)
+	}
+}
+
+type whyHop struct {
+	From    string `json:"from"`
+	To      string `json:"to"`
+	Line    int    `json:"line,omitempty"`
+	Snippet string `json:"snippet,omitempty"`
+}
+
+type whyReport struct {
+	From      string     `json:"from"`
+	To        string     `json:"to"`
+	Paths     [][]whyHop `json:"paths"`
+	Truncated bool       `json:"truncated,omitempty"`
+}
+
+// WhyJSON writes the import chains as JSON, each a list of hops.
+func WhyJSON(w io.Writer, from, to string, paths []why.Path, truncated bool) error {
+	rep := whyReport{From: from, To: to, Paths: [][]whyHop{}, Truncated: truncated}
+	for _, p := range paths {
+		hops := make([]whyHop, 0, len(p))
+		for _, e := range p {
+			hops = append(hops, whyHop{From: e.From, To: e.To, Line: e.Import.Line, Snippet: e.Import.Snippet})
+		}
+		rep.Paths = append(rep.Paths, hops)
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(rep)
+}
@@ -0,0 +1,79 @@
+package cli_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/jtoloui/depviz/internal/cli"
+	"github.com/jtoloui/depviz/internal/graph"
+	"github.com/jtoloui/depviz/internal/scanner"
+	"github.com/jtoloui/depviz/internal/why"
+)
+
+func whyPath() why.Path {
+	return why.Path{
+		{From: "app.tsx", To: "api.ts", Import: scanner.ImportDetail{Path: "./api", Line: 3, Snippet: "import { get } from './api'"}},
+		{From: "api.ts", To: "pg", Import: scanner.ImportDetail{Path: "pg", Line: 1, Snippet: "import pg from 'pg'"}},
+	}
+}
+
+func TestWhy(t *testing.T) {
+	out := captureStdout(t, func() { cli.Why("app.tsx", "pg", []why.Path{whyPath()}, false) })
+
+	for _, want := range []string{"2 hop(s)", "app.tsx:3", "import { get } from './api'", "api.ts:1", "→ pg"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q", want)
+		}
+	}
+}
+
+func TestWhyAll(t *testing.T) {
+	short := why.Path{graph.Edge{From: "app.tsx", To: "pg", Import: scanner.ImportDetail{Line: 9}}}
+	out := captureStdout(t, func() { cli.Why("app.tsx", "pg", []why.Path{short, whyPath()}, true) })
+
+	for _, want := range []string{"1.", "(1 hop(s))", "2.", "(2 hop(s))", "raise --limit"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q", want)
+		}
+	}
+}
+
+func TestWhyNone(t *testing.T) {
+	out := captureStdout(t, func() { cli.Why("a.ts", "b.ts", nil, false) })
+	if !strings.Contains(out, "No import path") {
+		t.Error("expected no-path message")
+	}
+}
+
+func TestWhyJSON(t *testing.T) {
+	var buf bytes.Buffer
+	if err := cli.WhyJSON(&buf, "app.tsx", "pg", []why.Path{whyPath()}, false); err != nil {
+		t.Fatal(err)
+	}
+	var got struct {
+		From  string `json:"from"`
+		To    string `json:"to"`
+		Paths [][]struct {
+			From    string `json:"from"`
+			To      string `json:"to"`
+			Line    int    `json:"line"`
+			Snippet string `json:"snippet"`
+		} `json:"paths"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if got.From != "app.tsx" || len(got.Paths) != 1 || len(got.Paths[0]) != 2 || got.Paths[0][1].To != "pg" || got.Paths[0][0].Line != 3 {
+		t.Errorf("got %+v", got)
+	}
+
+	buf.Reset()
+	if err := cli.WhyJSON(&buf, "a", "b", nil, false); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), `"paths": []`) {
+		t.Errorf("expected empty paths array, got %s", buf.String())
+	}
+}
@@ -0,0 +1,52 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Build holds the Go build settings scans evaluate `//go:build` lines and
+// _GOOS/_GOARCH file name suffixes against. Setting Tags, GOOS or GOARCH
+// skips the files a `go build` with those settings would leave out; an
+// unset GOOS or GOARCH means the host's. AllPlatforms instead keeps every
+// file and records its constraint. The zero Build scans every file.
+type Build struct {
+	Tags         []string `yaml:"tags,omitempty"`
+	GOOS         string   `yaml:"goos,omitempty"`
+	GOARCH       string   `yaml:"goarch,omitempty"`
+	AllPlatforms bool     `yaml:"all-platforms,omitempty"`
+}
+
+// Filters reports whether b skips files whose constraints don't hold.
+func (b Build) Filters() bool {
+	return len(b.Tags) > 0 || b.GOOS != "" || b.GOARCH != ""
+}
+
+// Validate checks GOOS and GOARCH are ones Go knows, and that b doesn't
+// both filter and keep every platform.
+func (b Build) Validate() error {
+	if b.GOOS != "" && !KnownOS[b.GOOS] {
+		return fmt.Errorf("unknown goos %q", b.GOOS)
+	}
+	if b.GOARCH != "" && !KnownArch[b.GOARCH] {
+		return fmt.Errorf("unknown goarch %q", b.GOARCH)
+	}
+	if b.AllPlatforms && b.Filters() {
+		return errors.New("all-platforms can't be combined with tags, goos or goarch")
+	}
+	return nil
+}
+
+// KnownOS and KnownArch are the GOOS and GOARCH values Go recognises,
+// including in file name suffixes, as listed in go/build's syslist.go.
+var (
+	KnownOS = toSet([]string{
+		"aix", "android", "darwin", "dragonfly", "freebsd", "hurd", "illumos", "ios", "js",
+		"linux", "nacl", "netbsd", "openbsd", "plan9", "solaris", "wasip1", "windows", "zos",
+	})
+	KnownArch = toSet([]string{
+		"386", "amd64", "amd64p32", "arm", "armbe", "arm64", "arm64be", "loong64",
+		"mips", "mipsle", "mips64", "mips64le", "mips64p32", "mips64p32le",
+		"ppc", "ppc64", "ppc64le", "riscv", "riscv64", "s390", "s390x", "sparc", "sparc64", "wasm",
+	})
+)
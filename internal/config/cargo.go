@@ -0,0 +1,78 @@
+package config
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+)
+
+// Cargo is the subset of a Cargo.toml depviz cares about.
+type Cargo struct {
+	Name         string     // crate name as written in code (dashes become underscores)
+	Dependencies []CargoDep // normal, dev and build dependencies, sorted by name
+}
+
+// CargoDep is a single crate dependency.
+type CargoDep struct {
+	Name    string // name as written in code, after any `package = ...` rename
+	Version string
+	Path    string // set for local path dependencies
+}
+
+type cargoFile struct {
+	Package struct {
+		Name string `toml:"name"`
+	} `toml:"package"`
+	Dependencies      map[string]any `toml:"dependencies"`
+	DevDependencies   map[string]any `toml:"dev-dependencies"`
+	BuildDependencies map[string]any `toml:"build-dependencies"`
+}
+
+// ReadCargo parses the Cargo.toml in root.
+func ReadCargo(root string) (*Cargo, error) {
+	var f cargoFile
+	if _, err := toml.DecodeFile(filepath.Join(root, "Cargo.toml"), &f); err != nil {
+		return nil, err
+	}
+
+	c := &Cargo{Name: crateIdent(f.Package.Name)}
+	seen := map[string]bool{}
+	for _, deps := range []map[string]any{f.Dependencies, f.DevDependencies, f.BuildDependencies} {
+		for name, spec := range deps {
+			dep, err := cargoDep(name, spec)
+			if err != nil {
+				return nil, err
+			}
+			if !seen[dep.Name] {
+				seen[dep.Name] = true
+				c.Dependencies = append(c.Dependencies, dep)
+			}
+		}
+	}
+	sort.Slice(c.Dependencies, func(i, j int) bool { return c.Dependencies[i].Name < c.Dependencies[j].Name })
+	return c, nil
+}
+
+// cargoDep reads either form of a dependency: `foo = "1.0"` or
+// `foo = { version = "1.0", path = "../foo" }`.
+func cargoDep(name string, spec any) (CargoDep, error) {
+	dep := CargoDep{Name: crateIdent(name)}
+	switch v := spec.(type) {
+	case string:
+		dep.Version = v
+	case map[string]any:
+		dep.Version, _ = v["version"].(string)
+		dep.Path, _ = v["path"].(string)
+	default:
+		return CargoDep{}, fmt.Errorf("dependency %q: unexpected value %v", name, spec)
+	}
+	return dep, nil
+}
+
+// crateIdent converts a package name to the identifier used in `use` paths.
+func crateIdent(name string) string {
+	return strings.ReplaceAll(name, "-", "_")
+}
@@ -6,7 +6,9 @@ import (
 	"os"
 	"path/filepath"
 	"regexp"
+	"slices"
 
+	"github.com/jtoloui/depviz/internal/glob"
 	"gopkg.in/yaml.v3"
 )
 
@@ -26,6 +28,52 @@ type ClassifyRules struct {
 	Private  []string `yaml:"private"`
 }
 
+// Rule is an architecture constraint checked by `depviz check`. Every
+// selector is a glob or, when it starts with ^, a regex.
+//
+// From picks the importing files the rule applies to. To optionally narrows
+// which of their imports it looks at. An import matching Deny is a
+// violation; when Allow is set, an import matching none of its selectors is
+// a violation too, and Allow also exempts imports from Deny. Imports are
+// matched by specifier and by the project file they resolve to.
+type Rule struct {
+	Name  string    `yaml:"name,omitempty"`
+	From  Selectors `yaml:"from"`
+	To    Selectors `yaml:"to,omitempty"`
+	Allow Selectors `yaml:"allow,omitempty"`
+	Deny  Selectors `yaml:"deny,omitempty"`
+}
+
+// Tests says what a scan does with test files.
+type Tests string
+
+const (
+	// TestsDefault keeps each language's own default: Go skips _test.go
+	// files, other languages scan their tests alongside the code.
+	TestsDefault Tests = ""
+	TestsInclude Tests = "include"
+	TestsExclude Tests = "exclude"
+	TestsOnly    Tests = "only"
+)
+
+// Selectors is a list of globs/regexes that may be written in YAML as a
+// single string or a sequence.
+type Selectors []string
+
+// UnmarshalYAML accepts both `from: src/**` and `from: [src/**, lib/**]`.
+func (s *Selectors) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		*s = Selectors{node.Value}
+		return nil
+	}
+	var list []string
+	if err := node.Decode(&list); err != nil {
+		return err
+	}
+	*s = list
+	return nil
+}
+
 // Config represents a .depviz.yml configuration.
 type Config struct {
 	Language string        `yaml:"language"`
@@ -33,9 +81,20 @@ type Config struct {
 	Output   string        `yaml:"output,omitempty"`
 	Exclude  []string      `yaml:"exclude"`
 	Classify ClassifyRules `yaml:"classify"`
+	Rules    []Rule        `yaml:"rules,omitempty"`
+	// Entry selects the files `depviz unreachable` starts from: binaries,
+	// app entry points, routes, tests. Empty means DefaultEntries.
+	Entry Selectors `yaml:"entry,omitempty"`
+	// Build picks the Go files a scan includes by their build constraints.
+	Build Build `yaml:"build,omitempty"`
+	// Tests includes, excludes or keeps only test files. Scanned test
+	// files are flagged either way.
+	Tests Tests `yaml:"tests,omitempty"`
+	// Layout is found on disk by Load, not configured.
+	Layout Layout `yaml:"-" json:"-"`
 }
 
-var supportedLangs = map[string]bool{"go": true, "js": true, "multi": true}
+var supportedLangs = map[string]bool{"go": true, "js": true, "multi": true, "python": true, "rust": true, "jvm": true}
 
 // Load reads .depviz.yml from root. If the file doesn't exist,
 // it returns DefaultFor(lang). Always returns a valid config or an error.
@@ -63,9 +122,35 @@ func Load(root, lang string) (*Config, error) {
 		return nil, fmt.Errorf("invalid config: %w", err)
 	}
 
+	if cfg.Layout, err = LoadLayout(root, cfg.Language); err != nil {
+		return nil, err
+	}
+
+	// Go modules, tsconfig aliases and workspace packages are part of how
+	// the project resolves imports, not a preference, so they apply even
+	// when .depviz.yml lists its own patterns.
+	cfg.Classify.Internal = appendMissing(cfg.Classify.Internal, goModulePatterns(cfg.Layout.Modules)...)
+	if cfg.Language == "js" || cfg.Language == "multi" {
+		aliases, err := tsAliasPatterns(root)
+		if err != nil {
+			return nil, err
+		}
+		cfg.Classify.Internal = appendMissing(cfg.Classify.Internal, aliases...)
+		cfg.Classify.Internal = appendMissing(cfg.Classify.Internal, workspacePackagePatterns(cfg.Layout.Packages)...)
+	}
+
 	return &cfg, nil
 }
 
+func appendMissing(list []string, items ...string) []string {
+	for _, it := range items {
+		if !slices.Contains(list, it) {
+			list = append(list, it)
+		}
+	}
+	return list
+}
+
 func (c *Config) validate() error {
 	if !supportedLangs[c.Language] {
 		return fmt.Errorf("unsupported language: %q", c.Language)
@@ -89,5 +174,51 @@ func (c *Config) validate() error {
 		}
 	}
 
+	for _, e := range c.Entry {
+		if _, err := glob.Compile(e); err != nil {
+			return fmt.Errorf("invalid entry selector %q: %w", e, err)
+		}
+	}
+
+	for i, r := range c.Rules {
+		if err := r.validate(); err != nil {
+			return fmt.Errorf("rule %d: %w", i+1, err)
+		}
+	}
+
+	if err := c.Build.Validate(); err != nil {
+		return fmt.Errorf("build: %w", err)
+	}
+
+	if err := c.Tests.Validate(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Validate checks t is one of the Tests values.
+func (t Tests) Validate() error {
+	switch t {
+	case TestsDefault, TestsInclude, TestsExclude, TestsOnly:
+		return nil
+	}
+	return fmt.Errorf("tests must be include, exclude or only, got %q", string(t))
+}
+
+func (r Rule) validate() error {
+	if len(r.From) == 0 {
+		return errors.New("from must not be empty")
+	}
+	if len(r.Allow) == 0 && len(r.Deny) == 0 {
+		return errors.New("needs at least one allow or deny selector")
+	}
+	for _, sels := range []Selectors{r.From, r.To, r.Allow, r.Deny} {
+		for _, s := range sels {
+			if _, err := glob.Compile(s); err != nil {
+				return fmt.Errorf("invalid selector %q: %w", s, err)
+			}
+		}
+	}
 	return nil
 }
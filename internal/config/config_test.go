@@ -3,6 +3,8 @@ package config_test
 import (
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
 	"testing"
 
 	"github.com/jtoloui/depviz/internal/config"
@@ -80,7 +82,7 @@ func TestLoad_FallbackGo(t *testing.T) {
 	if len(cfg.Classify.Internal) != 1 {
 		t.Fatalf("Internal len = %d, want 1", len(cfg.Classify.Internal))
 	}
-	want := `^github\.com\/example\/test/.*`
+	want := `^github\.com\/example\/test(/|$)`
 	if cfg.Classify.Internal[0] != want {
 		t.Errorf("Internal[0] = %q, want %q", cfg.Classify.Internal[0], want)
 	}
@@ -116,7 +118,7 @@ func TestLoad_InvalidLanguage(t *testing.T) {
 	t.Parallel()
 
 	dir := t.TempDir()
-	yaml := "language: python\n"
+	yaml := "language: cobol\n"
 	if err := os.WriteFile(filepath.Join(dir, ".depviz.yml"), []byte(yaml), 0o644); err != nil {
 		t.Fatal(err)
 	}
@@ -142,6 +144,82 @@ func TestLoad_InvalidRegex(t *testing.T) {
 	}
 }
 
+func TestLoad_Build(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	yaml := "language: go\nbuild:\n  tags: [integration, netgo]\n  goos: linux\n  goarch: arm64\n"
+	if err := os.WriteFile(filepath.Join(dir, ".depviz.yml"), []byte(yaml), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := config.Load(dir, "go")
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	b := cfg.Build
+	if len(b.Tags) != 2 || b.Tags[0] != "integration" || b.GOOS != "linux" || b.GOARCH != "arm64" || !b.Filters() {
+		t.Errorf("Build = %+v", b)
+	}
+}
+
+func TestLoad_Tests(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		yaml    string
+		want    config.Tests
+		wantErr bool
+	}{
+		{"unset", "language: go\n", config.TestsDefault, false},
+		{"only", "language: go\ntests: only\n", config.TestsOnly, false},
+		{"exclude", "language: js\ntests: exclude\n", config.TestsExclude, false},
+		{"invalid", "language: go\ntests: some\n", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			dir := t.TempDir()
+			if err := os.WriteFile(filepath.Join(dir, ".depviz.yml"), []byte(tt.yaml), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			cfg, err := config.Load(dir, "go")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Load() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && cfg.Tests != tt.want {
+				t.Errorf("Tests = %q, want %q", cfg.Tests, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuild_Validate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		build   config.Build
+		wantErr bool
+	}{
+		{"zero", config.Build{}, false},
+		{"platform", config.Build{GOOS: "windows", GOARCH: "386"}, false},
+		{"all platforms", config.Build{AllPlatforms: true}, false},
+		{"unknown goos", config.Build{GOOS: "beos"}, true},
+		{"unknown goarch", config.Build{GOARCH: "z80"}, true},
+		{"all platforms with tags", config.Build{AllPlatforms: true, Tags: []string{"integration"}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if err := tt.build.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
 func TestLoad_EmptyExclude(t *testing.T) {
 	t.Parallel()
 
@@ -179,12 +257,318 @@ func TestLoad_LanguageFallbackFromFlag(t *testing.T) {
 func TestDefaultFor_UnsupportedLang(t *testing.T) {
 	t.Parallel()
 
-	_, err := config.DefaultFor("python", t.TempDir())
+	_, err := config.DefaultFor("cobol", t.TempDir())
 	if err == nil {
 		t.Fatal("expected error for unsupported language")
 	}
 }
 
+func TestGoModules(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		files   map[string]string
+		want    []config.GoModule
+		wantErr bool
+	}{
+		{"single", map[string]string{"go.mod": "module example.com/app\n"}, []config.GoModule{
+			{Path: "example.com/app", Dir: "."},
+		}, false},
+		{"nested", map[string]string{
+			"go.mod":                  "module example.com/app\n",
+			"svc/api/go.mod":          "module example.com/api\n",
+			"vendor/x/go.mod":         "module example.com/x\n",
+			"svc/testdata/go.mod":     "module example.com/fixture\n",
+			".cache/mod/y/go.mod":     "module example.com/y\n",
+			"web/node_modules/go.mod": "module example.com/z\n",
+		}, []config.GoModule{
+			{Path: "example.com/app", Dir: "."},
+			{Path: "example.com/api", Dir: filepath.Join("svc", "api")},
+		}, false},
+		{"workspace", map[string]string{
+			"go.work":       "go 1.25\n\nuse (\n\t./b\n\t./a\n\t../outside\n)\n",
+			"a/go.mod":      "module example.com/a\n",
+			"b/go.mod":      "module example.com/b\n",
+			"unused/go.mod": "module example.com/unused\n",
+		}, []config.GoModule{
+			{Path: "example.com/a", Dir: "a"},
+			{Path: "example.com/b", Dir: "b"},
+		}, false},
+		{"none", map[string]string{"main.go": "package main\n"}, nil, true},
+		{"workspace module missing", map[string]string{"go.work": "go 1.25\n\nuse ./gone\n"}, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			dir := t.TempDir()
+			for name, content := range tt.files {
+				path := filepath.Join(dir, filepath.FromSlash(name))
+				if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			got, err := config.GoModules(dir)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GoModules() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("GoModules() = %v, want %v", got, tt.want)
+			}
+			for i := range tt.want {
+				if got[i] != tt.want[i] {
+					t.Errorf("module %d = %v, want %v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestModuleOf(t *testing.T) {
+	t.Parallel()
+
+	mods := []config.GoModule{
+		{Path: "example.com/app", Dir: "."},
+		{Path: "example.com/app/tools", Dir: "tools"},
+		{Path: "example.com/api", Dir: filepath.Join("svc", "api")},
+	}
+	files := []struct{ file, want string }{
+		{"main.go", "example.com/app"},
+		{filepath.Join("tools", "gen.go"), "example.com/app/tools"},
+		{filepath.Join("toolsx", "a.go"), "example.com/app"},
+		{filepath.Join("svc", "api", "h", "h.go"), "example.com/api"},
+		{filepath.Join("svc", "api"), "example.com/api"},
+	}
+	for _, tt := range files {
+		if m, _ := config.ModuleOf(mods, tt.file); m.Path != tt.want {
+			t.Errorf("ModuleOf(%s) = %q, want %q", tt.file, m.Path, tt.want)
+		}
+	}
+	imports := []struct{ spec, want string }{
+		{"example.com/app/internal/x", "example.com/app"},
+		{"example.com/app/tools/gen", "example.com/app/tools"},
+		{"example.com/app/toolsx", "example.com/app"},
+		{"example.com/api", "example.com/api"},
+		{"example.com/apix", ""},
+	}
+	for _, tt := range imports {
+		if m, _ := config.ModuleForImport(mods, tt.spec); m.Path != tt.want {
+			t.Errorf("ModuleForImport(%s) = %q, want %q", tt.spec, m.Path, tt.want)
+		}
+	}
+}
+
+func TestDefaultFor_GoWorkspace(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		"go.work":    "go 1.25\n\nuse (\n\t./api\n\t./lib\n)\n",
+		"api/go.mod": "module example.com/api\n",
+		"lib/go.mod": "module example.com/lib\n",
+	} {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cfg, err := config.DefaultFor("go", dir)
+	if err != nil {
+		t.Fatalf("DefaultFor: %v", err)
+	}
+	want := []string{`^example\.com\/api(/|$)`, `^example\.com\/lib(/|$)`}
+	if !slices.Equal(cfg.Classify.Internal, want) {
+		t.Errorf("Internal = %v, want %v", cfg.Classify.Internal, want)
+	}
+}
+
+func TestLoad_Layout(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		".depviz.yml":             "exclude: [vendor]\n",
+		"go.work":                 "go 1.25\n\nuse (\n\t./api\n\t./lib\n)\n",
+		"api/go.mod":              "module example.com/api\n",
+		"lib/go.mod":              "module example.com/lib\n",
+		"package.json":            `{"workspaces": ["web/*"]}`,
+		"web/ui/package.json":     `{"name": "@acme/ui"}`,
+		"web/ui/lib/package.json": `{"name": "nested"}`,
+		"api/client/package.json": `{"name": "client"}`,
+	} {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		lang         string
+		wantModules  []string
+		wantPackages []string
+	}{
+		{"go", []string{"example.com/api", "example.com/lib"}, nil},
+		{"js", nil, []string{"@acme/ui"}},
+		{"multi", []string{"example.com/api", "example.com/lib"}, []string{"@acme/ui"}},
+		{"python", nil, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.lang, func(t *testing.T) {
+			t.Parallel()
+			cfg, err := config.Load(dir, tt.lang)
+			if err != nil {
+				t.Fatalf("Load: %v", err)
+			}
+			var mods, pkgs []string
+			for _, m := range cfg.Layout.Modules {
+				mods = append(mods, m.Path)
+			}
+			for _, p := range cfg.Layout.Packages {
+				pkgs = append(pkgs, p.Name)
+			}
+			if !slices.Equal(mods, tt.wantModules) {
+				t.Errorf("Layout.Modules = %v, want %v", mods, tt.wantModules)
+			}
+			if !slices.Equal(pkgs, tt.wantPackages) {
+				t.Errorf("Layout.Packages = %v, want %v", pkgs, tt.wantPackages)
+			}
+		})
+	}
+}
+
+func TestLoad_GoModulesWithConfig(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		".depviz.yml": "language: go\nclassify:\n  internal: ['^example\\.com/tools(/|$)']\n",
+		"go.work":     "go 1.25\n\nuse (\n\t./api\n\t./lib\n)\n",
+		"api/go.mod":  "module example.com/api\n",
+		"lib/go.mod":  "module example.com/lib\n",
+	} {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cfg, err := config.Load(dir, "go")
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	want := []string{`^example\.com/tools(/|$)`, `^example\.com\/api(/|$)`, `^example\.com\/lib(/|$)`}
+	if !slices.Equal(cfg.Classify.Internal, want) {
+		t.Errorf("Internal = %v, want %v", cfg.Classify.Internal, want)
+	}
+}
+
+func TestJSWorkspaces(t *testing.T) {
+	t.Parallel()
+
+	pkgs := map[string]string{
+		"packages/ui/package.json":             `{"name": "@acme/ui", "main": "./dist/index.js", "exports": {".": {"types": "./dist/index.d.ts", "import": "./src/index.ts"}}}`,
+		"packages/legacy/package.json":         `{"name": "@acme/legacy"}`,
+		"packages/docs/README.md":              "no package.json",
+		"apps/web/package.json":                `{"name": "web", "source": "src/main.tsx"}`,
+		"apps/web/node_modules/x/package.json": `{"name": "x"}`,
+		"tools/package.json":                   `{"name": "tools"}`,
+		"tools/cli/bin/package.json":           `{"name": "cli"}`,
+	}
+	tests := []struct {
+		name    string
+		files   map[string]string
+		want    []string // name@dir:entries
+		wantErr bool
+	}{
+		{"npm", map[string]string{"package.json": `{"workspaces": ["packages/*", "apps/*"]}`}, []string{
+			"web@apps/web:src/main.tsx", "@acme/legacy@packages/legacy:", "@acme/ui@packages/ui:src/index.ts,dist/index.js",
+		}, false},
+		{"yarn", map[string]string{"package.json": `{"workspaces": {"packages": ["packages/**"], "nohoist": ["**"]}}`}, []string{
+			"@acme/legacy@packages/legacy:", "@acme/ui@packages/ui:src/index.ts,dist/index.js",
+		}, false},
+		{"pnpm", map[string]string{
+			"package.json":        `{"workspaces": ["tools"]}`,
+			"pnpm-workspace.yaml": "packages:\n  - 'packages/*'\n  - '!packages/legacy'\n  - apps/**\n",
+		}, []string{
+			"web@apps/web:src/main.tsx", "@acme/ui@packages/ui:src/index.ts,dist/index.js",
+		}, false},
+		{"patterns deeper than a wildcard", map[string]string{"package.json": `{"workspaces": ["tools/*/bin", "apps/web"]}`}, []string{
+			"web@apps/web:src/main.tsx", "cli@tools/cli/bin:",
+		}, false},
+		{"not a workspace", map[string]string{"package.json": `{"name": "app"}`}, nil, false},
+		{"bad pnpm-workspace.yaml", map[string]string{"pnpm-workspace.yaml": "packages: {"}, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			dir := t.TempDir()
+			for _, files := range []map[string]string{pkgs, tt.files} {
+				for name, content := range files {
+					path := filepath.Join(dir, filepath.FromSlash(name))
+					if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+						t.Fatal(err)
+					}
+					if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+						t.Fatal(err)
+					}
+				}
+			}
+			got, err := config.JSWorkspaces(dir)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("JSWorkspaces() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			var names []string
+			for _, p := range got {
+				names = append(names, p.Name+"@"+filepath.ToSlash(p.Dir)+":"+strings.Join(p.Entries, ","))
+			}
+			if !slices.Equal(names, tt.want) {
+				t.Errorf("JSWorkspaces() = %v\nwant %v", names, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoad_WorkspacePackages(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		".depviz.yml":              "language: js\nclassify:\n  internal:\n    - \"^~/\"\n",
+		"package.json":             `{"workspaces": ["packages/*"]}`,
+		"packages/ui/package.json": `{"name": "@acme/ui"}`,
+	} {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cfg, err := config.Load(dir, "js")
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	want := []string{`^~/`, `^@acme\/ui(/|$)`}
+	if !slices.Equal(cfg.Classify.Internal, want) {
+		t.Errorf("Internal = %v, want %v", cfg.Classify.Internal, want)
+	}
+}
+
 func TestDefaultFor_GoNoGoMod(t *testing.T) {
 	t.Parallel()
 
@@ -209,3 +593,249 @@ func TestDefaultFor_GoMalformedGoMod(t *testing.T) {
 		t.Fatal("expected error for go.mod without module directive")
 	}
 }
+
+func TestDefaultFor_Python(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	for _, pkg := range []string{"app", filepath.Join("src", "lib"), "scripts"} {
+		if err := os.MkdirAll(filepath.Join(dir, pkg), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// scripts/ has no __init__.py, so it isn't a package.
+	for _, f := range []string{filepath.Join("app", "__init__.py"), filepath.Join("src", "lib", "__init__.py")} {
+		if err := os.WriteFile(filepath.Join(dir, f), nil, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cfg, err := config.DefaultFor("python", dir)
+	if err != nil {
+		t.Fatalf("DefaultFor: %v", err)
+	}
+
+	want := []string{`^\.`, `^app(\.|$)`, `^lib(\.|$)`}
+	if len(cfg.Classify.Internal) != len(want) {
+		t.Fatalf("Internal = %v, want %v", cfg.Classify.Internal, want)
+	}
+	for i := range want {
+		if cfg.Classify.Internal[i] != want[i] {
+			t.Errorf("Internal[%d] = %q, want %q", i, cfg.Classify.Internal[i], want[i])
+		}
+	}
+}
+
+func TestDefaultFor_Rust(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	cargo := `[package]
+name = "my-app"
+version = "0.1.0"
+
+[dependencies]
+serde = { version = "1", features = ["derive"] }
+shared-types = { path = "../shared-types" }
+anyhow = "1.0"
+
+[dev-dependencies]
+tempfile = "3"
+`
+	if err := os.WriteFile(filepath.Join(dir, "Cargo.toml"), []byte(cargo), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := config.DefaultFor("rust", dir)
+	if err != nil {
+		t.Fatalf("DefaultFor: %v", err)
+	}
+	want := []string{`^(crate|super|self)(::|$)`, `^my_app(::|$)`, `^shared_types(::|$)`}
+	if len(cfg.Classify.Internal) != len(want) {
+		t.Fatalf("Internal = %v, want %v", cfg.Classify.Internal, want)
+	}
+	for i := range want {
+		if cfg.Classify.Internal[i] != want[i] {
+			t.Errorf("Internal[%d] = %q, want %q", i, cfg.Classify.Internal[i], want[i])
+		}
+	}
+
+	c, err := config.ReadCargo(dir)
+	if err != nil {
+		t.Fatalf("ReadCargo: %v", err)
+	}
+	var names []string
+	for _, d := range c.Dependencies {
+		names = append(names, d.Name)
+	}
+	if got := strings.Join(names, ","); got != "anyhow,serde,shared_types,tempfile" {
+		t.Errorf("Dependencies = %s", got)
+	}
+}
+
+func TestDefaultFor_RustNoCargo(t *testing.T) {
+	t.Parallel()
+
+	cfg, err := config.DefaultFor("rust", t.TempDir())
+	if err != nil {
+		t.Fatalf("DefaultFor: %v", err)
+	}
+	if len(cfg.Classify.Internal) != 1 {
+		t.Errorf("Internal = %v, want crate-relative pattern only", cfg.Classify.Internal)
+	}
+}
+
+func TestDefaultFor_JVM(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	for _, f := range []string{
+		// Multi-module build: each module's base package is found.
+		"app/src/main/java/com/acme/app/Main.java",
+		"app/src/main/java/com/acme/app/db/Store.java",
+		"lib/src/main/kotlin/com/acme/lib/Util.kt",
+		"lib/src/test/kotlin/com/acme/lib/UtilTest.kt",
+		// Build output is excluded.
+		"build/src/main/java/org/gen/Gen.java",
+	} {
+		path := filepath.Join(dir, filepath.FromSlash(f))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, nil, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cfg, err := config.DefaultFor("jvm", dir)
+	if err != nil {
+		t.Fatalf("DefaultFor: %v", err)
+	}
+
+	want := []string{`^com\.acme\.app(\.|$)`, `^com\.acme\.lib(\.|$)`}
+	if len(cfg.Classify.Internal) != len(want) {
+		t.Fatalf("Internal = %v, want %v", cfg.Classify.Internal, want)
+	}
+	for i := range want {
+		if cfg.Classify.Internal[i] != want[i] {
+			t.Errorf("Internal[%d] = %q, want %q", i, cfg.Classify.Internal[i], want[i])
+		}
+	}
+}
+
+func TestLoad_TSConfigAliases(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	tsconfig := `{ "compilerOptions": { "paths": { "@/*": ["src/*"], "~lib/*": ["lib/*"] } } }`
+	if err := os.WriteFile(filepath.Join(dir, "tsconfig.json"), []byte(tsconfig), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	// Defaults pick up the aliases...
+	cfg, err := config.Load(dir, "js")
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	want := []string{`^\.\.?/.*`, `^@/.*$`, `^~lib/.*$`}
+	if !slices.Equal(cfg.Classify.Internal, want) {
+		t.Errorf("default Internal = %v, want %v", cfg.Classify.Internal, want)
+	}
+
+	// ...and so does an explicit config, without duplicating patterns it
+	// already lists.
+	yaml := "language: js\nclassify:\n  internal:\n    - \"^@/.*$\"\n"
+	if err := os.WriteFile(filepath.Join(dir, ".depviz.yml"), []byte(yaml), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err = config.Load(dir, "js")
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	want = []string{`^@/.*$`, `^~lib/.*$`}
+	if !slices.Equal(cfg.Classify.Internal, want) {
+		t.Errorf("file Internal = %v, want %v", cfg.Classify.Internal, want)
+	}
+}
+
+func TestLoad_Rules(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		rules   string
+		wantErr bool
+	}{
+		{"valid deny", "  - from: [\"src/ui/**\"]\n    deny: [\"src/db/**\"]\n", false},
+		{"scalar selectors", "  - from: \"src/ui/**\"\n    deny: \"src/db/**\"\n", false},
+		{"valid allow", "  - name: core\n    from: [\"core/**\"]\n    allow: [\"^\\\\.\\\\./\"]\n", false},
+		{"missing from", "  - deny: [\"src/db/**\"]\n", true},
+		{"no allow or deny", "  - from: [\"src/**\"]\n", true},
+		{"bad regex", "  - from: [\"src/**\"]\n    deny: [\"^[bad\"]\n", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			dir := t.TempDir()
+			yaml := "language: js\nrules:\n" + tt.rules
+			if err := os.WriteFile(filepath.Join(dir, ".depviz.yml"), []byte(yaml), 0o644); err != nil {
+				t.Fatal(err)
+			}
+
+			cfg, err := config.Load(dir, "js")
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Load: %v", err)
+			}
+			if len(cfg.Rules) != 1 {
+				t.Errorf("Rules len = %d, want 1", len(cfg.Rules))
+			}
+		})
+	}
+}
+
+func TestLoad_Entry(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		entry   string
+		want    int
+		wantErr bool
+	}{
+		{"scalar", "entry: \"src/index.tsx\"\n", 1, false},
+		{"list", "entry: [\"src/index.tsx\", \"pages/**\", \"^.*\\\\.test\\\\.ts$\"]\n", 3, false},
+		{"absent", "", 0, false},
+		{"bad regex", "entry: [\"^[bad\"]\n", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			dir := t.TempDir()
+			if err := os.WriteFile(filepath.Join(dir, ".depviz.yml"), []byte("language: js\n"+tt.entry), 0o644); err != nil {
+				t.Fatal(err)
+			}
+
+			cfg, err := config.Load(dir, "js")
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Load: %v", err)
+			}
+			if len(cfg.Entry) != tt.want {
+				t.Errorf("Entry = %v, want %d selectors", cfg.Entry, tt.want)
+			}
+		})
+	}
+}
@@ -2,58 +2,143 @@ package config
 
 import (
 	"bufio"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
+
+	"github.com/jtoloui/depviz/internal/tsconfig"
 )
 
-// DefaultFor returns the built-in default config for a language.
-// For Go, it reads the module path from go.mod to set internal patterns.
+// DefaultFor returns the built-in default config for a language, with the
+// project's Layout. For Go, it reads the module paths from go.mod (or
+// go.work) to set internal patterns.
 func DefaultFor(lang, root string) (*Config, error) {
+	layout, err := LoadLayout(root, lang)
+	if err != nil {
+		return nil, err
+	}
+	var cfg *Config
 	switch lang {
 	case "js":
-		return defaultJS(), nil
+		cfg, err = defaultJS(root, layout.Packages)
 	case "go":
-		return defaultGo(root)
+		cfg, err = defaultGo(root, layout.Modules)
 	case "multi":
-		return defaultMulti(root)
+		cfg, err = defaultMulti(root, layout)
+	case "python":
+		cfg = defaultPython(root)
+	case "rust":
+		cfg, err = defaultRust(root)
+	case "jvm":
+		cfg, err = defaultJVM(root)
 	default:
 		return nil, fmt.Errorf("unsupported language: %q", lang)
 	}
+	if err != nil {
+		return nil, err
+	}
+	cfg.Layout = layout
+	return cfg, nil
 }
 
-func defaultJS() *Config {
+// DefaultEntries returns the conventional entry points for a language, used
+// when neither .depviz.yml nor --entry name any.
+func DefaultEntries(lang string) Selectors {
+	goEntries := Selectors{"**/main.go"}
+	jsEntries := Selectors{
+		"index.*", "main.*", "src/index.*", "src/main.*",
+		"pages/**", "app/**", "src/pages/**", "src/app/**",
+		"**/*.test.*", "**/*.spec.*", "**/__tests__/**", "**/*.stories.*", "**/*.config.*",
+	}
+	switch lang {
+	case "go":
+		return goEntries
+	case "js":
+		return jsEntries
+	case "multi":
+		return append(goEntries, jsEntries...)
+	case "jvm":
+		return Selectors{"**/Main.java", "**/Main.kt", "**/*Application.java", "**/*Application.kt", "**/src/test/**"}
+	case "rust":
+		return Selectors{"**/src/main.rs", "**/src/lib.rs", "**/src/bin/**", "**/tests/**", "**/examples/**", "**/benches/**", "**/build.rs"}
+	case "python":
+		return Selectors{"**/__main__.py", "main.py", "app.py", "manage.py", "**/test_*.py", "**/*_test.py", "**/conftest.py"}
+	}
+	return nil
+}
+
+func defaultJS(root string, pkgs []JSPackage) (*Config, error) {
+	aliases, err := tsAliasPatterns(root)
+	if err != nil {
+		return nil, err
+	}
 	return &Config{
 		Language: "js",
 		Exclude:  []string{"node_modules", ".git", "dist", "build", ".next", "coverage", ".depviz"},
 		Classify: ClassifyRules{
-			Internal: []string{`^\.\.?/.*`},
+			Internal: append(append([]string{`^\.\.?/.*`}, aliases...), workspacePackagePatterns(pkgs)...),
 		},
+	}, nil
+}
+
+// workspacePackagePatterns returns an internal pattern for each package of
+// an npm, yarn or pnpm workspace.
+func workspacePackagePatterns(pkgs []JSPackage) []string {
+	var patterns []string
+	for _, p := range pkgs {
+		patterns = appendMissing(patterns, `^`+regexpEscape(p.Name)+`(/|$)`)
 	}
+	return patterns
 }
 
-func defaultGo(root string) (*Config, error) {
-	mod, err := readModulePath(root)
+// tsAliasPatterns returns internal patterns for the baseUrl and paths
+// aliases in root's tsconfig.json/jsconfig.json, or nil if there is none.
+func tsAliasPatterns(root string) ([]string, error) {
+	ts, err := tsconfig.Load(root)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
 	if err != nil {
-		return nil, fmt.Errorf("reading go.mod: %w", err)
+		return nil, fmt.Errorf("reading tsconfig: %w", err)
 	}
+	return ts.Patterns(), nil
+}
 
+// defaultGo treats every module of the project as internal — root's go.mod,
+// or with a go.work or nested go.mod files, each module found — so imports
+// between a repository's modules are internal too.
+func defaultGo(root string, mods []GoModule) (*Config, error) {
+	if len(mods) == 0 {
+		return nil, fmt.Errorf("reading go.mod: no go.mod under %s: %w", root, os.ErrNotExist)
+	}
 	return &Config{
 		Language: "go",
 		Exclude:  []string{"vendor", ".git", ".depviz"},
-		Classify: ClassifyRules{
-			Internal: []string{`^` + regexpEscape(mod) + `/.*`},
-		},
+		Classify: ClassifyRules{Internal: goModulePatterns(mods)},
 	}, nil
 }
 
-func defaultMulti(root string) (*Config, error) {
-	goCfg, err := defaultGo(root)
+// goModulePatterns returns an internal pattern for each Go module.
+func goModulePatterns(mods []GoModule) []string {
+	var patterns []string
+	for _, m := range mods {
+		patterns = appendMissing(patterns, `^`+regexpEscape(m.Path)+`(/|$)`)
+	}
+	return patterns
+}
+
+func defaultMulti(root string, layout Layout) (*Config, error) {
+	goCfg, err := defaultGo(root, layout.Modules)
+	if err != nil {
+		return nil, err
+	}
+	jsCfg, err := defaultJS(root, layout.Packages)
 	if err != nil {
 		return nil, err
 	}
-	jsCfg := defaultJS()
 
 	exclude := append(goCfg.Exclude, jsCfg.Exclude...)
 	internal := append(goCfg.Classify.Internal, jsCfg.Classify.Internal...)
@@ -65,8 +150,165 @@ func defaultMulti(root string) (*Config, error) {
 	}, nil
 }
 
-// readModulePath extracts the module path from go.mod.
-func readModulePath(root string) (string, error) {
+// defaultPython treats relative imports and the project's own top-level
+// packages as internal. Packages are directories with an __init__.py at the
+// root or under src/ (the two common layouts).
+func defaultPython(root string) *Config {
+	internal := []string{`^\.`}
+	for _, dir := range []string{root, filepath.Join(root, "src")} {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, e := range entries {
+			if !e.IsDir() {
+				continue
+			}
+			if _, err := os.Stat(filepath.Join(dir, e.Name(), "__init__.py")); err == nil {
+				internal = append(internal, `^`+regexpEscape(e.Name())+`(\.|$)`)
+			}
+		}
+	}
+
+	return &Config{
+		Language: "python",
+		Exclude: []string{
+			".venv", "venv", "__pycache__", ".git", ".tox",
+			".mypy_cache", ".pytest_cache", "build", "dist", ".depviz",
+		},
+		Classify: ClassifyRules{Internal: internal},
+	}
+}
+
+// defaultRust treats crate-relative paths as internal, along with the crate's
+// own name (used by binaries and integration tests) and local path
+// dependencies from Cargo.toml. A missing Cargo.toml is not an error — loose
+// .rs files still scan.
+func defaultRust(root string) (*Config, error) {
+	internal := []string{`^(crate|super|self)(::|$)`}
+
+	cargo, err := ReadCargo(root)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+	case err != nil:
+		return nil, fmt.Errorf("reading Cargo.toml: %w", err)
+	default:
+		if cargo.Name != "" {
+			internal = append(internal, `^`+cargo.Name+`(::|$)`)
+		}
+		for _, d := range cargo.Dependencies {
+			if d.Path != "" {
+				internal = append(internal, `^`+d.Name+`(::|$)`)
+			}
+		}
+	}
+
+	return &Config{
+		Language: "rust",
+		Exclude:  []string{"target", ".git", ".depviz"},
+		Classify: ClassifyRules{Internal: internal},
+	}, nil
+}
+
+// jvmSourceRoots are the Maven/Gradle source directory layouts, relative to
+// each module.
+var jvmSourceRoots = []string{
+	"src/main/java", "src/main/kotlin", "src/test/java", "src/test/kotlin",
+}
+
+func defaultJVM(root string) (*Config, error) {
+	exclude := []string{"build", "target", "out", ".gradle", ".idea", ".git", ".depviz"}
+
+	pkgs, err := jvmBasePackages(root, toSet(exclude))
+	if err != nil {
+		return nil, fmt.Errorf("finding source roots: %w", err)
+	}
+	var internal []string
+	for _, p := range pkgs {
+		internal = append(internal, `^`+regexpEscape(p)+`(\.|$)`)
+	}
+
+	return &Config{
+		Language: "jvm",
+		Exclude:  exclude,
+		Classify: ClassifyRules{Internal: internal},
+	}, nil
+}
+
+// jvmBasePackages finds every Maven/Gradle source root under root (so
+// multi-module builds work) and returns the project's base packages. The
+// base package is where the directory tree stops being a single chain —
+// src/main/java/com/acme/app/{api,db} gives com.acme.app.
+func jvmBasePackages(root string, skip map[string]bool) ([]string, error) {
+	seen := map[string]bool{}
+	var pkgs []string
+	err := filepath.WalkDir(root, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() {
+			return nil
+		}
+		if path != root && (skip[d.Name()] || strings.HasPrefix(d.Name(), ".")) {
+			return filepath.SkipDir
+		}
+		rel, _ := filepath.Rel(root, path)
+		for _, sr := range jvmSourceRoots {
+			if rel != filepath.FromSlash(sr) && !strings.HasSuffix(rel, string(filepath.Separator)+filepath.FromSlash(sr)) {
+				continue
+			}
+			if pkg := singleChain(path); pkg != "" && !seen[pkg] {
+				seen[pkg] = true
+				pkgs = append(pkgs, pkg)
+			}
+			return filepath.SkipDir
+		}
+		return nil
+	})
+	sort.Strings(pkgs)
+	return pkgs, err
+}
+
+// singleChain descends from dir while it holds exactly one subdirectory and
+// no files, returning the dotted path walked.
+func singleChain(dir string) string {
+	var parts []string
+	for {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			break
+		}
+		var sub string
+		files, dirs := 0, 0
+		for _, e := range entries {
+			switch {
+			case strings.HasPrefix(e.Name(), "."):
+			case e.IsDir():
+				dirs++
+				sub = e.Name()
+			default:
+				files++
+			}
+		}
+		if dirs != 1 || files != 0 {
+			break
+		}
+		parts = append(parts, sub)
+		dir = filepath.Join(dir, sub)
+	}
+	return strings.Join(parts, ".")
+}
+
+func toSet(ss []string) map[string]bool {
+	m := make(map[string]bool, len(ss))
+	for _, s := range ss {
+		m[s] = true
+	}
+	return m
+}
+
+// ModulePath extracts the module path from the go.mod in root.
+func ModulePath(root string) (string, error) {
 	f, err := os.Open(filepath.Join(root, "go.mod"))
 	if err != nil {
 		return "", err
@@ -83,7 +325,7 @@ func readModulePath(root string) (string, error) {
 	return "", fmt.Errorf("module directive not found in go.mod")
 }
 
-// regexp_escape escapes dots and slashes for use in regex patterns.
+// regexpEscape escapes dots and slashes for use in regex patterns.
 func regexpEscape(s string) string {
 	s = strings.ReplaceAll(s, ".", `\.`)
 	s = strings.ReplaceAll(s, "/", `\/`)
@@ -0,0 +1,130 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"golang.org/x/mod/modfile"
+)
+
+// GoModule is one Go module of a project.
+type GoModule struct {
+	Path string // module path declared in its go.mod
+	Dir  string // directory holding the go.mod, relative to root; "." for root
+}
+
+// GoModules finds the Go modules a project is made of. With a go.work at
+// root they are the modules it uses that live under root; otherwise they are
+// root's go.mod and every go.mod nested below it, skipping vendor,
+// node_modules, testdata and hidden directories as the go command does. The
+// result is sorted by Dir. With no go.work or go.mod at all it returns an
+// error matching os.ErrNotExist.
+func GoModules(root string) ([]GoModule, error) {
+	dirs, err := goWorkDirs(root)
+	if errors.Is(err, os.ErrNotExist) {
+		dirs, err = goModDirs(root)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(dirs) == 0 {
+		return nil, fmt.Errorf("no go.mod under %s: %w", root, os.ErrNotExist)
+	}
+
+	mods := make([]GoModule, 0, len(dirs))
+	for _, dir := range dirs {
+		path, err := ModulePath(filepath.Join(root, dir))
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", filepath.Join(dir, "go.mod"), err)
+		}
+		mods = append(mods, GoModule{Path: path, Dir: dir})
+	}
+	sort.Slice(mods, func(i, j int) bool { return mods[i].Dir < mods[j].Dir })
+	return mods, nil
+}
+
+// goWorkDirs returns the use directives of root/go.work that stay inside
+// root, cleaned and relative to it.
+func goWorkDirs(root string) ([]string, error) {
+	data, err := os.ReadFile(filepath.Join(root, "go.work"))
+	if err != nil {
+		return nil, err
+	}
+	f, err := modfile.ParseWork("go.work", data, nil)
+	if err != nil {
+		return nil, fmt.Errorf("parsing go.work: %w", err)
+	}
+	var dirs []string
+	for _, u := range f.Use {
+		dir := filepath.Clean(filepath.FromSlash(u.Path))
+		if filepath.IsAbs(dir) || dir == ".." || strings.HasPrefix(dir, ".."+string(filepath.Separator)) {
+			continue
+		}
+		dirs = append(dirs, dir)
+	}
+	return dirs, nil
+}
+
+func goModDirs(root string) ([]string, error) {
+	var dirs []string
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			name := d.Name()
+			if path != root && (name == "vendor" || name == "node_modules" || name == "testdata" ||
+				strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_")) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.Name() == "go.mod" {
+			rel, _ := filepath.Rel(root, filepath.Dir(path))
+			dirs = append(dirs, rel)
+		}
+		return nil
+	})
+	return dirs, err
+}
+
+// ModuleOf returns the module a root-relative file or directory belongs to:
+// the one whose Dir is its closest ancestor.
+func ModuleOf(mods []GoModule, file string) (GoModule, bool) {
+	var best GoModule
+	depth := -1
+	for _, m := range mods {
+		d := 0
+		if m.Dir != "." {
+			if file != m.Dir && !strings.HasPrefix(file, m.Dir+string(filepath.Separator)) {
+				continue
+			}
+			d = len(m.Dir)
+		}
+		if d > depth {
+			best, depth = m, d
+		}
+	}
+	return best, depth >= 0
+}
+
+// ModuleForImport returns the module providing the Go import path spec:
+// the one with the longest Path that spec equals or extends.
+func ModuleForImport(mods []GoModule, spec string) (GoModule, bool) {
+	var best GoModule
+	found := false
+	for _, m := range mods {
+		if spec != m.Path && !strings.HasPrefix(spec, m.Path+"/") {
+			continue
+		}
+		if !found || len(m.Path) > len(best.Path) {
+			best, found = m, true
+		}
+	}
+	return best, found
+}
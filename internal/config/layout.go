@@ -0,0 +1,37 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+)
+
+// Layout is how a project splits into Go modules and JS/TS workspace
+// packages. Finding them walks the tree, so Load does it once per project
+// and everything that resolves imports shares the result.
+type Layout struct {
+	Modules  []GoModule
+	Packages []JSPackage
+}
+
+// LoadLayout finds the parts of root's layout that lang uses: its Go
+// modules for go and multi, its workspace packages for js and multi. A
+// project with no go.mod has no modules.
+func LoadLayout(root, lang string) (Layout, error) {
+	var l Layout
+	if lang == "go" || lang == "multi" {
+		mods, err := GoModules(root)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return Layout{}, fmt.Errorf("reading go.mod: %w", err)
+		}
+		l.Modules = mods
+	}
+	if lang == "js" || lang == "multi" {
+		pkgs, err := JSWorkspaces(root)
+		if err != nil {
+			return Layout{}, fmt.Errorf("reading workspaces: %w", err)
+		}
+		l.Packages = pkgs
+	}
+	return l, nil
+}
@@ -0,0 +1,256 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/jtoloui/depviz/internal/glob"
+	"gopkg.in/yaml.v3"
+)
+
+// JSPackage is one package of an npm, yarn or pnpm workspace.
+type JSPackage struct {
+	Name string // "name" from its package.json
+	Dir  string // directory holding the package.json, relative to root
+	// Entries are the files, relative to Dir, its package.json points
+	// importers at: exports["."], source, module and main, in that order.
+	Entries []string
+}
+
+// JSWorkspaces finds the packages of the workspace at root: the directories
+// matching the "workspaces" of root/package.json (a list, or yarn's
+// {"packages": [...]}) or the "packages" of root/pnpm-workspace.yaml that
+// hold a named package.json. Patterns starting with ! exclude directories;
+// node_modules, hidden directories and directories no pattern can reach are
+// never searched. The result is sorted by Dir, and nil when root isn't a
+// workspace.
+func JSWorkspaces(root string) ([]JSPackage, error) {
+	patterns, err := workspacePatterns(root)
+	if err != nil || len(patterns) == 0 {
+		return nil, err
+	}
+	var include, exclude []string
+	for _, p := range patterns {
+		p = strings.TrimSuffix(strings.TrimPrefix(p, "./"), "/")
+		if neg, ok := strings.CutPrefix(p, "!"); ok {
+			exclude = append(exclude, strings.TrimPrefix(neg, "./"))
+		} else {
+			include = append(include, p)
+		}
+	}
+	in, err := glob.CompileAll(include)
+	if err != nil {
+		return nil, fmt.Errorf("invalid workspace pattern: %w", err)
+	}
+	out, err := glob.CompileAll(exclude)
+	if err != nil {
+		return nil, fmt.Errorf("invalid workspace pattern: %w", err)
+	}
+
+	reach := reaches(include)
+
+	var pkgs []JSPackage
+	err = filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() || path == root {
+			return nil
+		}
+		if d.Name() == "node_modules" || strings.HasPrefix(d.Name(), ".") {
+			return filepath.SkipDir
+		}
+		rel, _ := filepath.Rel(root, path)
+		slash := filepath.ToSlash(rel)
+		here, below := reach.dir(strings.Split(slash, "/"))
+		if !here {
+			return filepath.SkipDir
+		}
+		if in.Match(slash) && !out.Match(slash) {
+			pkg, err := readJSPackage(path)
+			switch {
+			case errors.Is(err, os.ErrNotExist):
+			case err != nil:
+				return fmt.Errorf("%s: %w", filepath.Join(rel, "package.json"), err)
+			case pkg.Name != "":
+				pkg.Dir = rel
+				pkgs = append(pkgs, pkg)
+			}
+		}
+		if !below {
+			return filepath.SkipDir
+		}
+		return nil
+	})
+	sort.Slice(pkgs, func(i, j int) bool { return pkgs[i].Dir < pkgs[j].Dir })
+	return pkgs, err
+}
+
+// reach is how far into the tree a set of workspace patterns can match, so
+// the walk for packages skips everything else.
+type reach []patternReach
+
+type patternReach struct {
+	prefix []string // leading segments without wildcards
+	depth  int      // segments in the pattern; -1 when a ** makes it unbounded
+}
+
+func reaches(patterns []string) reach {
+	r := make(reach, 0, len(patterns))
+	for _, p := range patterns {
+		segs := strings.Split(p, "/")
+		depth := len(segs)
+		if strings.Contains(p, "**") {
+			depth = -1
+		}
+		lit := 0
+		for lit < len(segs) && !strings.ContainsAny(segs[lit], "*?") {
+			lit++
+		}
+		r = append(r, patternReach{prefix: segs[:lit], depth: depth})
+	}
+	return r
+}
+
+// dir reports whether some pattern can match the directory with the given
+// path segments (here), and whether one can match below it (below).
+func (r reach) dir(segs []string) (here, below bool) {
+	for _, p := range r {
+		n := min(len(segs), len(p.prefix))
+		if !slices.Equal(segs[:n], p.prefix[:n]) {
+			continue
+		}
+		if p.depth < 0 || len(segs) < p.depth {
+			return true, true
+		}
+		if len(segs) == p.depth {
+			here = true
+		}
+	}
+	return here, false
+}
+
+// workspacePatterns reads the workspace globs from pnpm-workspace.yaml, or
+// failing that from package.json.
+func workspacePatterns(root string) ([]string, error) {
+	data, err := os.ReadFile(filepath.Join(root, "pnpm-workspace.yaml"))
+	switch {
+	case err == nil:
+		var ws struct {
+			Packages []string `yaml:"packages"`
+		}
+		if err := yaml.Unmarshal(data, &ws); err != nil {
+			return nil, fmt.Errorf("parsing pnpm-workspace.yaml: %w", err)
+		}
+		return ws.Packages, nil
+	case !errors.Is(err, os.ErrNotExist):
+		return nil, err
+	}
+
+	data, err = os.ReadFile(filepath.Join(root, "package.json"))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var pkg struct {
+		Workspaces json.RawMessage `json:"workspaces"`
+	}
+	if err := json.Unmarshal(data, &pkg); err != nil {
+		return nil, fmt.Errorf("parsing package.json: %w", err)
+	}
+	if len(pkg.Workspaces) == 0 {
+		return nil, nil
+	}
+	var list []string
+	if err := json.Unmarshal(pkg.Workspaces, &list); err == nil {
+		return list, nil
+	}
+	var yarn struct {
+		Packages []string `json:"packages"`
+	}
+	if err := json.Unmarshal(pkg.Workspaces, &yarn); err != nil {
+		return nil, fmt.Errorf("parsing package.json workspaces: %w", err)
+	}
+	return yarn.Packages, nil
+}
+
+func readJSPackage(dir string) (JSPackage, error) {
+	data, err := os.ReadFile(filepath.Join(dir, "package.json"))
+	if err != nil {
+		return JSPackage{}, err
+	}
+	var pkg struct {
+		Name    string `json:"name"`
+		Exports any    `json:"exports"`
+		Source  string `json:"source"`
+		Module  string `json:"module"`
+		Main    string `json:"main"`
+	}
+	if err := json.Unmarshal(data, &pkg); err != nil {
+		return JSPackage{}, err
+	}
+	p := JSPackage{Name: pkg.Name}
+	for _, e := range []string{exportEntry(pkg.Exports), pkg.Source, pkg.Module, pkg.Main} {
+		if e = strings.TrimPrefix(e, "./"); e != "" {
+			p.Entries = appendMissing(p.Entries, e)
+		}
+	}
+	return p, nil
+}
+
+// exportEntry picks the file a package.json "exports" field maps the bare
+// package name to: the string itself, its "." entry, or the first of the
+// source, import, default and require conditions. Type declarations are
+// skipped since they hold no code.
+func exportEntry(v any) string {
+	switch e := v.(type) {
+	case string:
+		return e
+	case map[string]any:
+		if dot, ok := e["."]; ok {
+			return exportEntry(dot)
+		}
+		for _, cond := range []string{"source", "import", "default", "require"} {
+			if s := exportEntry(e[cond]); s != "" {
+				return s
+			}
+		}
+	}
+	return ""
+}
+
+// PackageOf returns the workspace package a root-relative file belongs to:
+// the one whose Dir is its closest ancestor.
+func PackageOf(pkgs []JSPackage, file string) (JSPackage, bool) {
+	var best JSPackage
+	found := false
+	for _, p := range pkgs {
+		if file != p.Dir && !strings.HasPrefix(file, p.Dir+string(filepath.Separator)) {
+			continue
+		}
+		if !found || len(p.Dir) > len(best.Dir) {
+			best, found = p, true
+		}
+	}
+	return best, found
+}
+
+// PackageForImport returns the workspace package a bare specifier such as
+// "@acme/ui" or "@acme/ui/button" imports.
+func PackageForImport(pkgs []JSPackage, spec string) (JSPackage, bool) {
+	for _, p := range pkgs {
+		if spec == p.Name || strings.HasPrefix(spec, p.Name+"/") {
+			return p, true
+		}
+	}
+	return JSPackage{}, false
+}
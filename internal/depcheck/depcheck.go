@@ -0,0 +1,185 @@
+// Package depcheck compares the external packages a project imports with
+// the dependencies its manifests declare.
+package depcheck
+
+import (
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/jtoloui/depviz/internal/classify"
+	"github.com/jtoloui/depviz/internal/config"
+	"github.com/jtoloui/depviz/internal/glob"
+	"github.com/jtoloui/depviz/internal/manifest"
+	"github.com/jtoloui/depviz/internal/scanner"
+)
+
+// DefaultDev selects the files that only run in development — tests,
+// stories, mocks and tool configs — whose imports may use dev dependencies.
+var DefaultDev = []string{
+	"**/*.test.*", "**/*.spec.*", "**/__tests__/**", "**/__mocks__/**",
+	"**/test/**", "**/tests/**", "**/e2e/**", "**/*.stories.*", "**/*.config.*",
+}
+
+// Use is one import of a dependency.
+type Use struct {
+	File   string `json:"file"`
+	Line   int    `json:"line,omitempty"`
+	Import string `json:"import"`
+}
+
+// Finding is a dependency that is imported without being declared,
+// declared without being imported, or declared for development but
+// imported by production code.
+type Finding struct {
+	// Name is the package root or Go module.
+	Name string `json:"name"`
+	Lang string `json:"lang"`
+	// Manifest is the file, relative to the project root, that declares
+	// the dependency or, when nothing does, that the importing files fall
+	// under.
+	Manifest string `json:"manifest"`
+	// Declared is the manifest entry, if there is one.
+	Declared *manifest.Dependency `json:"declared,omitempty"`
+	// Uses are the imports behind the finding, sorted by file and line.
+	Uses []Use `json:"uses,omitempty"`
+}
+
+// Report holds the findings of Check, each list sorted by language, name
+// and manifest.
+type Report struct {
+	// Missing are imported but not declared, or declared only as an
+	// indirect go.mod requirement.
+	Missing []Finding `json:"missing"`
+	// Unused are declared as dependencies (or direct go.mod requirements)
+	// but never imported. Dev and peer dependencies are left out: tools
+	// and packages provided by the consumer are seldom imported.
+	Unused []Finding `json:"unused"`
+	// DevInProd are dev dependencies imported by files outside dev.
+	DevInProd []Finding `json:"devInProd"`
+}
+
+// Len returns the total number of findings.
+func (r Report) Len() int {
+	return len(r.Missing) + len(r.Unused) + len(r.DevInProd)
+}
+
+// Check matches every external and private import in results to the
+// dependency providing it, as declared by the manifest the importing file
+// falls under. Only languages with a manifest are checked. dev selects the
+// files whose imports may use dev dependencies, on top of the files the
+// scanner flagged as tests.
+func Check(results []scanner.FileImports, cl *classify.Classifier, m *manifest.Manifests, dev glob.Set) Report {
+	type usage struct {
+		lang, name, manifest string
+		dep                  *manifest.Dependency
+		all, prod            []Use
+	}
+	// used is keyed by manifest, language and name; imported by language
+	// and name alone.
+	used := map[string]*usage{}
+	imported := map[string]bool{}
+	// Workspace packages and sibling Go modules are internal imports, but
+	// the manifest may still declare them.
+	internal := map[string]bool{}
+	for _, fi := range results {
+		if !m.Covers(fi.Lang) {
+			continue
+		}
+		isDev := fi.IsTest || dev.Match(filepath.ToSlash(fi.File))
+		for i, imp := range fi.Imports {
+			switch cl.ClassifyWithLang(imp, fi.Lang) {
+			case config.External, config.Private:
+			case config.Internal:
+				internal[fi.Lang+" "+m.Root(fi.Lang, imp)] = true
+				continue
+			default:
+				continue
+			}
+			name := m.Root(fi.Lang, imp)
+			u := &usage{lang: fi.Lang, name: name, manifest: m.Manifest(fi.File, fi.Lang)}
+			if dep, ok := m.Lookup(fi.File, fi.Lang, imp); ok {
+				u.dep, u.manifest = &dep, dep.Manifest
+			}
+			k := u.manifest + " " + fi.Lang + " " + name
+			if prev, ok := used[k]; ok {
+				u = prev
+			} else {
+				used[k] = u
+			}
+			imported[fi.Lang+" "+name] = true
+			use := Use{File: fi.File, Import: imp}
+			if i < len(fi.Details) {
+				use.Line = fi.Details[i].Line
+			}
+			u.all = append(u.all, use)
+			if !isDev {
+				u.prod = append(u.prod, use)
+			}
+		}
+	}
+
+	var r Report
+	for _, u := range used {
+		f := Finding{Name: u.name, Lang: u.lang, Manifest: u.manifest, Declared: u.dep}
+		switch {
+		case u.dep == nil || u.dep.Kind == manifest.Indirect:
+			f.Uses = sortUses(u.all)
+			r.Missing = append(r.Missing, f)
+		case u.dep.Kind == manifest.Dev && len(u.prod) > 0:
+			f.Uses = sortUses(u.prod)
+			r.DevInProd = append(r.DevInProd, f)
+		}
+	}
+	for _, dep := range m.Deps {
+		if dep.Kind != manifest.Direct || used[dep.Manifest+" "+dep.Lang+" "+dep.Name] != nil || internal[dep.Lang+" "+dep.Name] {
+			continue
+		}
+		// @types/node and friends are used by the compiler, not imported;
+		// @types/foo counts as used when foo is.
+		if pkg, ok := typesFor(dep); ok && (pkg == "" || imported[dep.Lang+" "+pkg]) {
+			continue
+		}
+		r.Unused = append(r.Unused, Finding{Name: dep.Name, Lang: dep.Lang, Manifest: dep.Manifest, Declared: &dep})
+	}
+
+	for _, list := range [][]Finding{r.Missing, r.Unused, r.DevInProd} {
+		sort.Slice(list, func(i, j int) bool {
+			if list[i].Lang != list[j].Lang {
+				return list[i].Lang < list[j].Lang
+			}
+			if list[i].Name != list[j].Name {
+				return list[i].Name < list[j].Name
+			}
+			return list[i].Manifest < list[j].Manifest
+		})
+	}
+	return r
+}
+
+// typesFor returns the package a DefinitelyTyped dependency describes —
+// "@types/babel__core" is "@babel/core" — or "" for ambient ones like
+// @types/node that describe no importable package.
+func typesFor(dep manifest.Dependency) (string, bool) {
+	name, ok := strings.CutPrefix(dep.Name, "@types/")
+	if dep.Lang != "js" || !ok {
+		return "", false
+	}
+	if name == "node" {
+		return "", true
+	}
+	if scope, pkg, ok := strings.Cut(name, "__"); ok {
+		return "@" + scope + "/" + pkg, true
+	}
+	return name, true
+}
+
+func sortUses(uses []Use) []Use {
+	sort.Slice(uses, func(i, j int) bool {
+		if uses[i].File != uses[j].File {
+			return uses[i].File < uses[j].File
+		}
+		return uses[i].Line < uses[j].Line
+	})
+	return uses
+}
@@ -0,0 +1,242 @@
+package depcheck_test
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/jtoloui/depviz/internal/classify"
+	"github.com/jtoloui/depviz/internal/config"
+	"github.com/jtoloui/depviz/internal/depcheck"
+	"github.com/jtoloui/depviz/internal/glob"
+	"github.com/jtoloui/depviz/internal/manifest"
+	"github.com/jtoloui/depviz/internal/scanner"
+)
+
+func load(t *testing.T, files map[string]string) *manifest.Manifests {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	layout, err := config.LoadLayout(dir, "multi")
+	if err != nil {
+		t.Fatalf("LoadLayout: %v", err)
+	}
+	m, err := manifest.Load(dir, layout)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	return m
+}
+
+// names renders findings as "name[file:line,...]" for comparison.
+func names(fs []depcheck.Finding) string {
+	var out []string
+	for _, f := range fs {
+		var uses []string
+		for _, u := range f.Uses {
+			uses = append(uses, u.File+":"+strconv.Itoa(u.Line))
+		}
+		out = append(out, f.Name+"["+strings.Join(uses, ",")+"]")
+	}
+	return strings.Join(out, " ")
+}
+
+func imports(file string, specs ...string) scanner.FileImports {
+	fi := scanner.FileImports{File: file, Lang: "js", Imports: specs}
+	for i, s := range specs {
+		fi.Details = append(fi.Details, scanner.ImportDetail{Path: s, Line: i + 1})
+	}
+	return fi
+}
+
+func TestCheck_JS(t *testing.T) {
+	t.Parallel()
+	m := load(t, map[string]string{"package.json": `{
+  "dependencies": {"react": "^18", "@scope/ui": "1.0.0", "left-pad": "1.0.0", "@types/react": "^18", "@types/lodash": "^4", "@types/express": "^4", "@types/node": "^20"},
+  "peerDependencies": {"react-dom": "^18"},
+  "devDependencies": {"vitest": "^1", "msw": "^2", "eslint": "^9"}
+}`})
+	cl, err := classify.New(&config.Config{
+		Language: "js",
+		Classify: config.ClassifyRules{Internal: []string{`^\.\.?/.*`}, Private: []string{`^@acme/`}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	dev, err := glob.CompileAll(depcheck.DefaultDev)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	results := []scanner.FileImports{
+		imports("src/app.tsx", "react", "react-dom/client", "@scope/ui/button", "./util", "fs", "lodash/fp"),
+		imports("src/util.ts", "@acme/logger", "msw"),
+		imports("src/app.test.tsx", "vitest", "msw", "testing-library"),
+		{File: "main.py", Lang: "python", Imports: []string{"requests"}},
+	}
+	r := depcheck.Check(results, cl, m, dev)
+
+	tests := []struct {
+		name string
+		got  []depcheck.Finding
+		want string
+	}{
+		{"missing", r.Missing, "@acme/logger[src/util.ts:1] lodash[src/app.tsx:6] testing-library[src/app.test.tsx:3]"},
+		{"unused", r.Unused, "@types/express[] left-pad[]"},
+		{"dev in prod", r.DevInProd, "msw[src/util.ts:2]"},
+	}
+	for _, tt := range tests {
+		if got := names(tt.got); got != tt.want {
+			t.Errorf("%s = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+	if r.Len() != 6 {
+		t.Errorf("Len = %d, want 6", r.Len())
+	}
+	if d := r.DevInProd[0].Declared; d == nil || d.Kind != manifest.Dev {
+		t.Errorf("msw declared = %+v, want a dev dependency", d)
+	}
+}
+
+func TestCheck_Go(t *testing.T) {
+	t.Parallel()
+	m := load(t, map[string]string{"go.mod": `module example.com/app
+
+go 1.25
+
+require (
+	github.com/spf13/cobra v1.8.0
+	github.com/spf13/pflag v1.0.5 // indirect
+	github.com/stretchr/testify v1.9.0
+	gopkg.in/yaml.v3 v3.0.1
+)
+`})
+	cl, err := classify.New(&config.Config{
+		Language: "go",
+		Classify: config.ClassifyRules{Internal: []string{`^example\.com/app(/.*)?$`}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	results := []scanner.FileImports{
+		{File: "cmd/root.go", Lang: "go", Imports: []string{"fmt", "github.com/spf13/cobra", "example.com/app/internal/x"}},
+		{File: "cmd/flags.go", Lang: "go", Imports: []string{"github.com/spf13/pflag", "github.com/google/go-cmp/cmp"}},
+		// A require only tests import is still used.
+		{File: "cmd/root_test.go", Lang: "go", IsTest: true, Imports: []string{"github.com/stretchr/testify/assert"}},
+	}
+	r := depcheck.Check(results, cl, m, nil)
+
+	if got, want := names(r.Missing), "github.com/google/go-cmp[cmd/flags.go:0] github.com/spf13/pflag[cmd/flags.go:0]"; got != want {
+		t.Errorf("missing = %q, want %q", got, want)
+	}
+	if got, want := names(r.Unused), "gopkg.in/yaml.v3[]"; got != want {
+		t.Errorf("unused = %q, want %q", got, want)
+	}
+	if len(r.DevInProd) != 0 {
+		t.Errorf("dev in prod = %v, want none", r.DevInProd)
+	}
+}
+
+func TestCheck_GoModules(t *testing.T) {
+	t.Parallel()
+	m := load(t, map[string]string{
+		"go.work":    "go 1.25\n\nuse (\n\t./api\n\t./lib\n)\n",
+		"api/go.mod": "module example.com/api\n\nrequire github.com/spf13/cobra v1.8.0\n",
+		"lib/go.mod": "module example.com/lib\n\nrequire gopkg.in/yaml.v3 v3.0.1\n",
+	})
+	cl, err := classify.New(&config.Config{
+		Language: "go",
+		Classify: config.ClassifyRules{Internal: []string{`^example\.com/(api|lib)(/|$)`}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	results := []scanner.FileImports{
+		{File: "api/cmd/main.go", Lang: "go", Imports: []string{"github.com/spf13/cobra", "gopkg.in/yaml.v3", "example.com/lib/util"}},
+		{File: "lib/util/util.go", Lang: "go", Imports: []string{"gopkg.in/yaml.v3"}},
+	}
+	r := depcheck.Check(results, cl, m, nil)
+
+	if got, want := names(r.Missing), "gopkg.in/yaml.v3[api/cmd/main.go:0]"; got != want {
+		t.Errorf("missing = %q, want %q", got, want)
+	}
+	if len(r.Missing) == 1 && r.Missing[0].Manifest != "api/go.mod" {
+		t.Errorf("missing from %q, want api/go.mod", r.Missing[0].Manifest)
+	}
+	if len(r.Unused) != 0 {
+		t.Errorf("unused = %q, want none", names(r.Unused))
+	}
+}
+
+func TestCheck_WorkspacePackages(t *testing.T) {
+	t.Parallel()
+	m := load(t, map[string]string{
+		"package.json":              `{"workspaces": ["packages/*"]}`,
+		"packages/ui/package.json":  `{"name": "@acme/ui", "dependencies": {"react": "^18"}}`,
+		"packages/app/package.json": `{"name": "app", "dependencies": {"lodash": "^4"}}`,
+	})
+	cl, err := classify.New(&config.Config{
+		Language: "js",
+		Classify: config.ClassifyRules{Internal: []string{`^\.\.?/.*`}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	results := []scanner.FileImports{
+		imports("packages/ui/src/button.tsx", "react"),
+		imports("packages/app/src/main.ts", "lodash", "react"),
+	}
+	r := depcheck.Check(results, cl, m, nil)
+
+	if got, want := names(r.Missing), "react[packages/app/src/main.ts:2]"; got != want {
+		t.Errorf("missing = %q, want %q", got, want)
+	}
+	if len(r.Missing) == 1 && r.Missing[0].Manifest != "packages/app/package.json" {
+		t.Errorf("missing from %q, want packages/app/package.json", r.Missing[0].Manifest)
+	}
+	if len(r.Unused) != 0 {
+		t.Errorf("unused = %q, want none", names(r.Unused))
+	}
+}
+
+func TestCheck_NoManifest(t *testing.T) {
+	t.Parallel()
+	cl, err := classify.New(&config.Config{Language: "js"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := depcheck.Check([]scanner.FileImports{imports("a.ts", "react")}, cl, load(t, nil), nil)
+	if r.Len() != 0 {
+		t.Errorf("got %d findings without a manifest, want 0", r.Len())
+	}
+}
+
+func TestCheck_Workspace(t *testing.T) {
+	t.Parallel()
+	m := load(t, map[string]string{"package.json": `{"dependencies": {"@acme/ui": "workspace:*", "@acme/unused": "workspace:*"}}`})
+	cl, err := classify.New(&config.Config{
+		Language: "js",
+		Classify: config.ClassifyRules{Internal: []string{`^\.\.?/.*`, `^@acme/ui(/|$)`, `^@acme/unused(/|$)`}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r := depcheck.Check([]scanner.FileImports{imports("apps/web/page.tsx", "@acme/ui/button")}, cl, m, nil)
+	if got := names(r.Unused); got != "@acme/unused[]" || len(r.Missing) != 0 {
+		t.Errorf("unused = %q, missing = %q", got, names(r.Missing))
+	}
+}
@@ -0,0 +1,238 @@
+// Package diff compares the scan results of two revisions of a project and
+// reports how its dependencies changed.
+package diff
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/jtoloui/depviz/internal/classify"
+	"github.com/jtoloui/depviz/internal/config"
+	"github.com/jtoloui/depviz/internal/graph"
+	"github.com/jtoloui/depviz/internal/manifest"
+	"github.com/jtoloui/depviz/internal/scanner"
+)
+
+// Snapshot is one side of a comparison: a scanned revision with the
+// classifier from its own config, its declared dependencies and its
+// internal import cycles.
+type Snapshot struct {
+	Rev        string
+	Results    []scanner.FileImports
+	Classifier *classify.Classifier
+	// Manifests maps imports to the package or Go module providing them;
+	// nil still maps JS subpaths to their package.
+	Manifests *manifest.Manifests
+	Cycles    []graph.Cycle
+}
+
+// Report is everything that changed between two snapshots. Slices are
+// sorted and never nil, so JSON output has stable shape.
+type Report struct {
+	Base string `json:"base"`
+	Head string `json:"head"`
+
+	AddedFiles   []string `json:"addedFiles"`
+	RemovedFiles []string `json:"removedFiles"`
+
+	AddedImports   []ImportChange `json:"addedImports"`
+	RemovedImports []ImportChange `json:"removedImports"`
+
+	// NewPackages are external or private packages (JS package roots, Go
+	// modules) that no file used at base; DroppedPackages are ones no file
+	// uses any more at head.
+	NewPackages     []string `json:"newPackages"`
+	DroppedPackages []string `json:"droppedPackages"`
+
+	// Export changes are only reported for files present in both
+	// revisions; a new file's exports are implied by AddedFiles.
+	AddedExports   []ExportChange `json:"addedExports"`
+	RemovedExports []ExportChange `json:"removedExports"`
+
+	NewCycles []graph.Cycle `json:"newCycles"`
+}
+
+// ImportChange is an import specifier gained or lost by one or more files.
+type ImportChange struct {
+	Import   string          `json:"import"`
+	Category config.Category `json:"category"`
+	Files    []string        `json:"files"`
+}
+
+// ExportChange is a public symbol gained or lost by a file.
+type ExportChange struct {
+	File string             `json:"file"`
+	Name string             `json:"name"`
+	Kind scanner.ExportKind `json:"kind"`
+}
+
+// Empty reports whether the two revisions have identical dependencies.
+func (r *Report) Empty() bool {
+	return len(r.AddedFiles)+len(r.RemovedFiles)+len(r.AddedImports)+len(r.RemovedImports)+
+		len(r.NewPackages)+len(r.DroppedPackages)+
+		len(r.AddedExports)+len(r.RemovedExports)+len(r.NewCycles) == 0
+}
+
+// Compute compares base with head. Imports are classified with the config of
+// the revision they appear in.
+func Compute(base, head Snapshot) *Report {
+	rep := &Report{
+		Base:            base.Rev,
+		Head:            head.Rev,
+		AddedFiles:      []string{},
+		RemovedFiles:    []string{},
+		AddedImports:    []ImportChange{},
+		RemovedImports:  []ImportChange{},
+		NewPackages:     []string{},
+		DroppedPackages: []string{},
+		AddedExports:    []ExportChange{},
+		RemovedExports:  []ExportChange{},
+		NewCycles:       []graph.Cycle{},
+	}
+
+	baseFiles := byFile(base.Results)
+	headFiles := byFile(head.Results)
+	for f := range headFiles {
+		if _, ok := baseFiles[f]; !ok {
+			rep.AddedFiles = append(rep.AddedFiles, f)
+		}
+	}
+	for f := range baseFiles {
+		if _, ok := headFiles[f]; !ok {
+			rep.RemovedFiles = append(rep.RemovedFiles, f)
+		}
+	}
+	sort.Strings(rep.AddedFiles)
+	sort.Strings(rep.RemovedFiles)
+
+	rep.AddedImports = importChanges(head, headFiles, baseFiles)
+	rep.RemovedImports = importChanges(base, baseFiles, headFiles)
+	rep.NewPackages = packageChanges(head, base)
+	rep.DroppedPackages = packageChanges(base, head)
+
+	for f, h := range headFiles {
+		b, ok := baseFiles[f]
+		if !ok {
+			continue
+		}
+		rep.AddedExports = append(rep.AddedExports, exportChanges(f, h, b)...)
+		rep.RemovedExports = append(rep.RemovedExports, exportChanges(f, b, h)...)
+	}
+	sortExports(rep.AddedExports)
+	sortExports(rep.RemovedExports)
+
+	known := map[string]bool{}
+	for _, c := range base.Cycles {
+		known[strings.Join(c.Files, "\x00")] = true
+	}
+	for _, c := range head.Cycles {
+		if !known[strings.Join(c.Files, "\x00")] {
+			rep.NewCycles = append(rep.NewCycles, c)
+		}
+	}
+	return rep
+}
+
+func byFile(results []scanner.FileImports) map[string]scanner.FileImports {
+	m := make(map[string]scanner.FileImports, len(results))
+	for _, fi := range results {
+		m[fi.File] = fi
+	}
+	return m
+}
+
+// importChanges lists imports of from's files that the same file in other
+// lacks. A file missing from other counts as importing nothing.
+func importChanges(from Snapshot, files, other map[string]scanner.FileImports) []ImportChange {
+	byImport := map[string]*ImportChange{}
+	for f, fi := range files {
+		have := toSet(other[f].Imports)
+		for _, imp := range fi.Imports {
+			if have[imp] {
+				continue
+			}
+			c, ok := byImport[imp]
+			if !ok {
+				c = &ImportChange{Import: imp, Category: from.Classifier.ClassifyWithLang(imp, fi.Lang)}
+				byImport[imp] = c
+			}
+			if n := len(c.Files); n == 0 || c.Files[n-1] != f {
+				c.Files = append(c.Files, f)
+			}
+		}
+	}
+
+	out := make([]ImportChange, 0, len(byImport))
+	for _, c := range byImport {
+		sort.Strings(c.Files)
+		out = append(out, *c)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Import < out[j].Import })
+	return out
+}
+
+// packageChanges lists the packages of external and private imports used
+// anywhere in from but nowhere in other, so "lodash/fp" or a new package of
+// a known Go module isn't a new dependency.
+func packageChanges(from, other Snapshot) []string {
+	seen := map[string]bool{}
+	for _, fi := range other.Results {
+		for _, imp := range fi.Imports {
+			seen[other.Manifests.Root(fi.Lang, imp)] = true
+		}
+	}
+	var out []string
+	for _, fi := range from.Results {
+		for _, imp := range fi.Imports {
+			pkg := from.Manifests.Root(fi.Lang, imp)
+			if seen[pkg] {
+				continue
+			}
+			switch from.Classifier.ClassifyWithLang(imp, fi.Lang) {
+			case config.External, config.Private:
+				seen[pkg] = true
+				out = append(out, pkg)
+			}
+		}
+	}
+	sort.Strings(out)
+	if out == nil {
+		return []string{}
+	}
+	return out
+}
+
+// exportChanges lists public exports of a that b lacks. Private symbols are
+// ignored: they aren't part of the file's surface.
+func exportChanges(file string, a, b scanner.FileImports) []ExportChange {
+	have := map[string]bool{}
+	for _, e := range b.Exports {
+		if !e.Private {
+			have[e.Name] = true
+		}
+	}
+	var out []ExportChange
+	for _, e := range a.Exports {
+		if !e.Private && !have[e.Name] {
+			out = append(out, ExportChange{File: file, Name: e.Name, Kind: e.Kind})
+		}
+	}
+	return out
+}
+
+func sortExports(es []ExportChange) {
+	sort.Slice(es, func(i, j int) bool {
+		if es[i].File != es[j].File {
+			return es[i].File < es[j].File
+		}
+		return es[i].Name < es[j].Name
+	})
+}
+
+func toSet(ss []string) map[string]bool {
+	m := make(map[string]bool, len(ss))
+	for _, s := range ss {
+		m[s] = true
+	}
+	return m
+}
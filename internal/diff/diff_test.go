@@ -0,0 +1,161 @@
+package diff_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jtoloui/depviz/internal/classify"
+	"github.com/jtoloui/depviz/internal/config"
+	"github.com/jtoloui/depviz/internal/diff"
+	"github.com/jtoloui/depviz/internal/graph"
+	"github.com/jtoloui/depviz/internal/manifest"
+	"github.com/jtoloui/depviz/internal/scanner"
+)
+
+func TestCompute(t *testing.T) {
+	t.Parallel()
+
+	cl, err := classify.New(&config.Config{
+		Language: "go",
+		Classify: config.ClassifyRules{Internal: []string{`^example\.com/app`}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cycle := graph.Cycle{Files: []string{"a/a.go", "b/b.go"}}
+
+	base := diff.Snapshot{
+		Rev:        "main",
+		Classifier: cl,
+		Results: []scanner.FileImports{
+			{File: "a/a.go", Lang: "go", Imports: []string{"fmt", "github.com/old/dep"},
+				Exports: []scanner.ExportDetail{{Name: "Old", Kind: "func"}, {Name: "Keep", Kind: "type"}}},
+			{File: "gone/gone.go", Lang: "go", Imports: []string{"os"}},
+		},
+	}
+	head := diff.Snapshot{
+		Rev:        "feature",
+		Classifier: cl,
+		Results: []scanner.FileImports{
+			{File: "a/a.go", Lang: "go", Imports: []string{"fmt", "example.com/app/b", "github.com/new/dep"},
+				Exports: []scanner.ExportDetail{{Name: "Keep", Kind: "type"}, {Name: "New", Kind: "func"}, {Name: "hidden", Kind: "func", Private: true}}},
+			{File: "b/b.go", Lang: "go", Imports: []string{"example.com/app/a", "github.com/new/dep"}},
+		},
+		Cycles: []graph.Cycle{cycle},
+	}
+
+	rep := diff.Compute(base, head)
+
+	if rep.Base != "main" || rep.Head != "feature" {
+		t.Errorf("revs = %s..%s", rep.Base, rep.Head)
+	}
+	assertStrings(t, "AddedFiles", rep.AddedFiles, "b/b.go")
+	assertStrings(t, "RemovedFiles", rep.RemovedFiles, "gone/gone.go")
+	assertStrings(t, "NewPackages", rep.NewPackages, "github.com/new/dep")
+	assertStrings(t, "DroppedPackages", rep.DroppedPackages, "github.com/old/dep")
+
+	if len(rep.AddedImports) != 3 {
+		t.Fatalf("AddedImports = %+v, want 3", rep.AddedImports)
+	}
+	newDep := rep.AddedImports[2]
+	if newDep.Import != "github.com/new/dep" || newDep.Category != config.External {
+		t.Errorf("AddedImports[2] = %+v", newDep)
+	}
+	assertStrings(t, "new dep files", newDep.Files, "a/a.go", "b/b.go")
+	if rep.AddedImports[0].Category != config.Internal {
+		t.Errorf("AddedImports[0] = %+v, want internal", rep.AddedImports[0])
+	}
+
+	if len(rep.RemovedImports) != 2 || rep.RemovedImports[0].Import != "github.com/old/dep" || rep.RemovedImports[1].Import != "os" {
+		t.Errorf("RemovedImports = %+v", rep.RemovedImports)
+	}
+
+	if len(rep.AddedExports) != 1 || rep.AddedExports[0].Name != "New" {
+		t.Errorf("AddedExports = %+v, want only New", rep.AddedExports)
+	}
+	if len(rep.RemovedExports) != 1 || rep.RemovedExports[0].Name != "Old" || rep.RemovedExports[0].Kind != scanner.ExportFunction {
+		t.Errorf("RemovedExports = %+v, want only Old", rep.RemovedExports)
+	}
+
+	if len(rep.NewCycles) != 1 {
+		t.Errorf("NewCycles = %+v, want 1", rep.NewCycles)
+	}
+}
+
+func TestCompute_NoChanges(t *testing.T) {
+	t.Parallel()
+
+	cl, err := classify.New(&config.Config{Language: "go"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cycles := []graph.Cycle{{Files: []string{"a.go", "b.go"}}}
+	results := []scanner.FileImports{{File: "a.go", Lang: "go", Imports: []string{"fmt"}}}
+
+	rep := diff.Compute(
+		diff.Snapshot{Rev: "a", Results: results, Classifier: cl, Cycles: cycles},
+		diff.Snapshot{Rev: "b", Results: results, Classifier: cl, Cycles: cycles},
+	)
+	if !rep.Empty() {
+		t.Errorf("expected empty report, got %+v", rep)
+	}
+	if rep.AddedImports == nil || rep.NewCycles == nil {
+		t.Error("empty slices should be non-nil for JSON")
+	}
+}
+
+func TestCompute_PackageRoots(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module example.com/app\n\nrequire golang.org/x/mod v0.20.0\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	m, err := manifest.Load(dir, config.Layout{})
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	cl, err := classify.New(&config.Config{Language: "multi"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	base := diff.Snapshot{Rev: "main", Classifier: cl, Manifests: m, Results: []scanner.FileImports{
+		{File: "a.ts", Lang: "js", Imports: []string{"lodash", "@scope/pkg", "left-pad"}},
+		{File: "a.go", Lang: "go", Imports: []string{"golang.org/x/mod/modfile"}},
+	}}
+	head := diff.Snapshot{Rev: "feature", Classifier: cl, Manifests: m, Results: []scanner.FileImports{
+		{File: "a.ts", Lang: "js", Imports: []string{"lodash/fp", "@scope/pkg/sub", "react/jsx-runtime"}},
+		{File: "a.go", Lang: "go", Imports: []string{"golang.org/x/mod/modfile", "golang.org/x/mod/semver"}},
+	}}
+
+	rep := diff.Compute(base, head)
+	assertStrings(t, "NewPackages", rep.NewPackages, "react")
+	assertStrings(t, "DroppedPackages", rep.DroppedPackages, "left-pad")
+}
+
+func TestReport_EmptyWithPackageChanges(t *testing.T) {
+	t.Parallel()
+
+	if (&diff.Report{NewPackages: []string{"react"}}).Empty() {
+		t.Error("a new package is a change")
+	}
+	if (&diff.Report{DroppedPackages: []string{"react"}}).Empty() {
+		t.Error("a dropped package is a change")
+	}
+}
+
+func assertStrings(t *testing.T, name string, got []string, want ...string) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Errorf("%s = %v, want %v", name, got, want)
+		return
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("%s = %v, want %v", name, got, want)
+			return
+		}
+	}
+}
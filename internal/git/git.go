@@ -0,0 +1,83 @@
+// Package git runs the handful of git commands depviz needs to look at other
+// revisions of a project. Everything is local; nothing touches a remote.
+package git
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// TopLevel returns the root of the work tree containing dir.
+func TopLevel(dir string) (string, error) {
+	out, err := run(dir, "rev-parse", "--show-toplevel")
+	if err != nil {
+		return "", err
+	}
+	return filepath.FromSlash(out), nil
+}
+
+// Checkout materialises rev of the repository containing dir in a temporary
+// detached worktree and returns its root. cleanup removes the worktree and
+// must be called once the caller is done with it.
+func Checkout(dir, rev string) (root string, cleanup func(), err error) {
+	top, err := TopLevel(dir)
+	if err != nil {
+		return "", nil, err
+	}
+	if _, err := run(top, "rev-parse", "--verify", "--quiet", rev+"^{commit}"); err != nil {
+		return "", nil, fmt.Errorf("unknown revision %q", rev)
+	}
+
+	tmp, err := os.MkdirTemp("", "depviz-rev-")
+	if err != nil {
+		return "", nil, err
+	}
+	wt := filepath.Join(tmp, "tree")
+	if _, err := run(top, "worktree", "add", "--detach", "--quiet", wt, rev); err != nil {
+		_ = os.RemoveAll(tmp)
+		return "", nil, err
+	}
+
+	cleanup = func() {
+		_, _ = run(top, "worktree", "remove", "--force", wt)
+		_ = os.RemoveAll(tmp)
+	}
+	return wt, cleanup, nil
+}
+
+// ChangedFiles lists the files under dir changed in a revision range
+// (anything `git diff --name-only` accepts, e.g. "main...HEAD"), relative to
+// dir.
+func ChangedFiles(dir, revRange string) ([]string, error) {
+	out, err := run(dir, "diff", "--name-only", "--relative", revRange)
+	if err != nil {
+		return nil, err
+	}
+	if out == "" {
+		return nil, nil
+	}
+	var files []string
+	for _, f := range strings.Split(out, "\n") {
+		files = append(files, filepath.FromSlash(f))
+	}
+	return files, nil
+}
+
+func run(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("git %s: %s", args[0], msg)
+		}
+		return "", fmt.Errorf("git %s: %w", args[0], err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
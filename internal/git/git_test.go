@@ -0,0 +1,86 @@
+package git_test
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/jtoloui/depviz/internal/git"
+)
+
+func TestCheckout(t *testing.T) {
+	t.Parallel()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+
+	dir := t.TempDir()
+	gitCmd(t, dir, "init", "-q")
+	writeFile(t, filepath.Join(dir, "a.txt"), "one")
+	gitCmd(t, dir, "add", "-A")
+	gitCmd(t, dir, "commit", "-q", "-m", "one")
+	writeFile(t, filepath.Join(dir, "a.txt"), "two")
+	writeFile(t, filepath.Join(dir, "b.txt"), "new")
+	if err := os.Mkdir(filepath.Join(dir, "sub"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	writeFile(t, filepath.Join(dir, "sub", "c.txt"), "new")
+	gitCmd(t, dir, "add", "-A")
+	gitCmd(t, dir, "commit", "-q", "-m", "two")
+
+	tree, cleanup, err := git.Checkout(dir, "HEAD~1")
+	if err != nil {
+		t.Fatalf("Checkout: %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(tree, "a.txt"))
+	if err != nil || string(data) != "one" {
+		t.Errorf("a.txt at HEAD~1 = %q, %v", data, err)
+	}
+	if _, err := os.Stat(filepath.Join(tree, "b.txt")); !os.IsNotExist(err) {
+		t.Error("b.txt should not exist at HEAD~1")
+	}
+	cleanup()
+	if _, err := os.Stat(tree); !os.IsNotExist(err) {
+		t.Error("worktree should be removed by cleanup")
+	}
+
+	changed, err := git.ChangedFiles(dir, "HEAD~1..HEAD")
+	if err != nil {
+		t.Fatalf("ChangedFiles: %v", err)
+	}
+	if len(changed) != 3 || changed[0] != "a.txt" || changed[1] != "b.txt" || changed[2] != filepath.Join("sub", "c.txt") {
+		t.Errorf("ChangedFiles = %v, want [a.txt b.txt sub/c.txt]", changed)
+	}
+	// From a subdirectory, only its files, relative to it.
+	changed, err = git.ChangedFiles(filepath.Join(dir, "sub"), "HEAD~1..HEAD")
+	if err != nil {
+		t.Fatalf("ChangedFiles(sub): %v", err)
+	}
+	if len(changed) != 1 || changed[0] != "c.txt" {
+		t.Errorf("ChangedFiles(sub) = %v, want [c.txt]", changed)
+	}
+
+	if _, _, err := git.Checkout(dir, "no-such-rev"); err == nil {
+		t.Error("expected error for unknown revision")
+	}
+}
+
+func gitCmd(t *testing.T, dir string, args ...string) {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(),
+		"GIT_AUTHOR_NAME=test", "GIT_AUTHOR_EMAIL=test@example.com",
+		"GIT_COMMITTER_NAME=test", "GIT_COMMITTER_EMAIL=test@example.com")
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git %v: %v\n%s", args, err, out)
+	}
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,76 @@
+// Package glob compiles path selectors used in .depviz.yml. A selector is
+// either a regex (when it starts with ^) or a path glob.
+package glob
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Compile turns a selector into a regexp. Selectors starting with ^ are used
+// as regexes verbatim. Anything else is a glob matched against the whole
+// string: ** matches across slashes, * and ? stay within one path segment,
+// and a Go-style trailing /... is treated as /**.
+func Compile(sel string) (*regexp.Regexp, error) {
+	if strings.HasPrefix(sel, "^") {
+		return regexp.Compile(sel)
+	}
+	return regexp.Compile(toRegexp(sel))
+}
+
+func toRegexp(glob string) string {
+	if strings.HasSuffix(glob, "/...") {
+		glob = strings.TrimSuffix(glob, "...") + "**"
+	}
+
+	var b strings.Builder
+	b.WriteString("^")
+	for i := 0; i < len(glob); i++ {
+		c := glob[i]
+		switch {
+		case c == '*' && i+1 < len(glob) && glob[i+1] == '*':
+			i++
+			// "dir/**/x" should also match "dir/x".
+			if i+1 < len(glob) && glob[i+1] == '/' {
+				i++
+				b.WriteString("(?:.*/)?")
+			} else {
+				b.WriteString(".*")
+			}
+		case c == '*':
+			b.WriteString("[^/]*")
+		case c == '?':
+			b.WriteString("[^/]")
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	b.WriteString("$")
+	return b.String()
+}
+
+// Set is a compiled list of selectors that matches if any member does.
+type Set []*regexp.Regexp
+
+// CompileAll compiles every selector in sels.
+func CompileAll(sels []string) (Set, error) {
+	set := make(Set, len(sels))
+	for i, s := range sels {
+		re, err := Compile(s)
+		if err != nil {
+			return nil, err
+		}
+		set[i] = re
+	}
+	return set, nil
+}
+
+// Match reports whether any selector in s matches str.
+func (s Set) Match(str string) bool {
+	for _, re := range s {
+		if re.MatchString(str) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,73 @@
+package glob_test
+
+import (
+	"testing"
+
+	"github.com/jtoloui/depviz/internal/glob"
+)
+
+func TestCompile(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		sel   string
+		input string
+		want  bool
+	}{
+		{"src/ui/**", "src/ui/Button.tsx", true},
+		{"src/ui/**", "src/ui/forms/Input.tsx", true},
+		{"src/ui/**", "src/db/query.ts", false},
+		{"src/*.ts", "src/app.ts", true},
+		{"src/*.ts", "src/lib/app.ts", false},
+		{"**/*.test.ts", "a/b/c.test.ts", true},
+		{"**/*.test.ts", "c.test.ts", true},
+		{"src/**/index.ts", "src/index.ts", true},
+		{"src/**/index.ts", "src/a/b/index.ts", true},
+		{"file?.go", "file1.go", true},
+		{"file?.go", "file12.go", false},
+		{"cmd/...", "cmd/scan.go", true},
+		{"github.com/acme/app/cmd/...", "github.com/acme/app/cmd/serve", true},
+		{"main.go", "main.go", true},
+		{"main.go", "cmd/main.go", false},
+		{"^lodash(/.*)?$", "lodash/fp", true},
+		{"^lodash(/.*)?$", "lodash-es", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.sel+"→"+tt.input, func(t *testing.T) {
+			t.Parallel()
+			re, err := glob.Compile(tt.sel)
+			if err != nil {
+				t.Fatalf("Compile(%q): %v", tt.sel, err)
+			}
+			if got := re.MatchString(tt.input); got != tt.want {
+				t.Errorf("%q matches %q = %v, want %v", tt.sel, tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompile_InvalidRegex(t *testing.T) {
+	t.Parallel()
+
+	if _, err := glob.Compile("^[bad"); err == nil {
+		t.Error("expected error for invalid regex selector")
+	}
+}
+
+func TestSet(t *testing.T) {
+	t.Parallel()
+
+	set, err := glob.CompileAll([]string{"cmd/**", "^fmt$"})
+	if err != nil {
+		t.Fatalf("CompileAll: %v", err)
+	}
+	for input, want := range map[string]bool{"cmd/root.go": true, "fmt": true, "os": false} {
+		if got := set.Match(input); got != want {
+			t.Errorf("Match(%q) = %v, want %v", input, got, want)
+		}
+	}
+	if glob.Set(nil).Match("anything") {
+		t.Error("empty set must not match")
+	}
+}
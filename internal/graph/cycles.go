@@ -0,0 +1,124 @@
+package graph
+
+import "sort"
+
+// Cycle is a group of files that transitively import each other, with one
+// concrete import chain through the group.
+type Cycle struct {
+	Files []string `json:"files"` // every file in the strongly connected component, sorted
+	Path  []Edge   `json:"path"`  // shortest chain from Files[0] back to itself
+}
+
+// Cycles returns every import cycle in g, one per strongly connected
+// component, ordered by their first file.
+func (g *Graph) Cycles() []Cycle {
+	var cycles []Cycle
+	for _, scc := range g.SCCs() {
+		if len(scc) < 2 {
+			continue
+		}
+		cycles = append(cycles, Cycle{Files: scc, Path: g.shortestLoop(scc)})
+	}
+	sort.Slice(cycles, func(i, j int) bool { return cycles[i].Files[0] < cycles[j].Files[0] })
+	return cycles
+}
+
+// SCCs returns the strongly connected components of g using Tarjan's
+// algorithm. Each component is sorted; single files are included.
+func (g *Graph) SCCs() [][]string {
+	index := make(map[string]int, len(g.files))
+	low := make(map[string]int, len(g.files))
+	onStack := make(map[string]bool)
+	var stack []string
+	var sccs [][]string
+	next := 0
+
+	var visit func(f string)
+	visit = func(f string) {
+		index[f] = next
+		low[f] = next
+		next++
+		stack = append(stack, f)
+		onStack[f] = true
+
+		for _, e := range g.nodes[f].Out {
+			if _, seen := index[e.To]; !seen {
+				visit(e.To)
+				low[f] = min(low[f], low[e.To])
+			} else if onStack[e.To] {
+				low[f] = min(low[f], index[e.To])
+			}
+		}
+
+		if low[f] != index[f] {
+			return
+		}
+		var scc []string
+		for {
+			top := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			onStack[top] = false
+			scc = append(scc, top)
+			if top == f {
+				break
+			}
+		}
+		sort.Strings(scc)
+		sccs = append(sccs, scc)
+	}
+
+	for _, f := range g.files {
+		if _, seen := index[f]; !seen {
+			visit(f)
+		}
+	}
+	return sccs
+}
+
+// shortestLoop finds the shortest edge chain from scc[0] back to itself,
+// staying inside the component. BFS keeps the reported chain minimal even
+// when the component is large.
+func (g *Graph) shortestLoop(scc []string) []Edge {
+	members := toSet(scc)
+	start := scc[0]
+	via := map[string]Edge{}
+	queue := []string{start}
+	for len(queue) > 0 {
+		f := queue[0]
+		queue = queue[1:]
+		for _, e := range g.nodes[f].Out {
+			if !members[e.To] {
+				continue
+			}
+			if e.To == start {
+				return unwind(via, start, e)
+			}
+			if _, seen := via[e.To]; seen {
+				continue
+			}
+			via[e.To] = e
+			queue = append(queue, e.To)
+		}
+	}
+	return nil
+}
+
+// unwind rebuilds the path ending in last by following via back to start.
+func unwind(via map[string]Edge, start string, last Edge) []Edge {
+	path := []Edge{last}
+	for f := last.From; f != start; f = via[f].From {
+		path = append(path, via[f])
+	}
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+	return path
+}
+
+func toSet(ss []string) map[string]bool {
+	m := make(map[string]bool, len(ss))
+	for _, s := range ss {
+		m[s] = true
+	}
+	return m
+}
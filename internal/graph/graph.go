@@ -0,0 +1,119 @@
+// Package graph turns scan results into a directed file-level dependency
+// graph by resolving import specifiers to the files they point at.
+package graph
+
+import (
+	"sort"
+
+	"github.com/jtoloui/depviz/internal/config"
+	"github.com/jtoloui/depviz/internal/scanner"
+)
+
+// Edge is an import from one scanned file that resolved to another.
+type Edge struct {
+	From   string               `json:"from"`
+	To     string               `json:"to"`
+	Import scanner.ImportDetail `json:"import"`
+}
+
+// Node is a scanned file with its resolved outgoing and incoming edges.
+type Node struct {
+	File string `json:"file"`
+	Lang string `json:"lang,omitempty"`
+	Out  []Edge `json:"-"`
+	In   []Edge `json:"-"`
+}
+
+// Graph is a directed graph of scanned files. Imports that don't resolve to a
+// scanned file (stdlib, external, missing) are not part of it.
+type Graph struct {
+	nodes map[string]*Node
+	files []string // sorted, for deterministic iteration
+}
+
+// Build resolves results against root and its layout and returns the
+// dependency graph.
+func Build(root string, layout config.Layout, results []scanner.FileImports) (*Graph, error) {
+	r, err := NewResolver(root, layout, results)
+	if err != nil {
+		return nil, err
+	}
+	return New(results, r), nil
+}
+
+// New builds a graph from results, resolving each import with r.
+func New(results []scanner.FileImports, r *Resolver) *Graph {
+	g := &Graph{nodes: make(map[string]*Node, len(results))}
+	byFile := make(map[string]scanner.FileImports, len(results))
+	for _, fi := range results {
+		if _, ok := g.nodes[fi.File]; ok {
+			continue
+		}
+		g.nodes[fi.File] = &Node{File: fi.File, Lang: fi.Lang}
+		g.files = append(g.files, fi.File)
+		byFile[fi.File] = fi
+	}
+	sort.Strings(g.files)
+
+	// Walk in sorted order so edge lists are deterministic regardless of
+	// the order the worker pool returned results in.
+	for _, f := range g.files {
+		fi := byFile[f]
+		for i, imp := range fi.Imports {
+			d := scanner.ImportDetail{Path: imp}
+			if i < len(fi.Details) {
+				d = fi.Details[i]
+			}
+			for _, to := range r.Resolve(fi.File, fi.Lang, imp) {
+				if to == fi.File {
+					continue
+				}
+				g.addEdge(Edge{From: fi.File, To: to, Import: d})
+			}
+		}
+	}
+	return g
+}
+
+func (g *Graph) addEdge(e Edge) {
+	g.nodes[e.From].Out = append(g.nodes[e.From].Out, e)
+	g.nodes[e.To].In = append(g.nodes[e.To].In, e)
+}
+
+// Node returns the node for file, or nil if it isn't in the graph.
+func (g *Graph) Node(file string) *Node {
+	return g.nodes[file]
+}
+
+// Nodes returns every node sorted by file path.
+func (g *Graph) Nodes() []*Node {
+	nodes := make([]*Node, len(g.files))
+	for i, f := range g.files {
+		nodes[i] = g.nodes[f]
+	}
+	return nodes
+}
+
+// Edges returns every edge, grouped by source file in sorted order.
+func (g *Graph) Edges() []Edge {
+	var edges []Edge
+	for _, f := range g.files {
+		edges = append(edges, g.nodes[f].Out...)
+	}
+	return edges
+}
+
+// Filter returns a copy of g containing every node but only the edges keep
+// accepts.
+func (g *Graph) Filter(keep func(Edge) bool) *Graph {
+	out := &Graph{nodes: make(map[string]*Node, len(g.nodes)), files: append([]string(nil), g.files...)}
+	for f, n := range g.nodes {
+		out.nodes[f] = &Node{File: n.File, Lang: n.Lang}
+	}
+	for _, e := range g.Edges() {
+		if keep(e) {
+			out.addEdge(e)
+		}
+	}
+	return out
+}
@@ -0,0 +1,632 @@
+package graph_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jtoloui/depviz/internal/config"
+	"github.com/jtoloui/depviz/internal/graph"
+	"github.com/jtoloui/depviz/internal/scanner"
+)
+
+func TestResolver_JS(t *testing.T) {
+	t.Parallel()
+
+	results := []scanner.FileImports{
+		{File: "src/app.ts", Lang: "js"},
+		{File: "src/utils.ts", Lang: "js"},
+		{File: "src/components/index.tsx", Lang: "js"},
+		{File: "src/legacy.js", Lang: "js"},
+		{File: "lib/esm.ts", Lang: "js"},
+	}
+	r, err := graph.NewResolver(t.TempDir(), config.Layout{}, results)
+	if err != nil {
+		t.Fatalf("NewResolver: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		from string
+		spec string
+		want string
+	}{
+		{"extension probe", "src/app.ts", "./utils", "src/utils.ts"},
+		{"explicit extension", "src/app.ts", "./legacy.js", "src/legacy.js"},
+		{"directory index", "src/app.ts", "./components", "src/components/index.tsx"},
+		{"parent dir", "src/components/index.tsx", "../utils", "src/utils.ts"},
+		{"esm js to ts", "src/app.ts", "../lib/esm.js", "lib/esm.ts"},
+		{"missing file", "src/app.ts", "./nope", ""},
+		{"bare package", "src/app.ts", "react", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got := r.Resolve(tt.from, "js", tt.spec)
+			if tt.want == "" {
+				if len(got) != 0 {
+					t.Errorf("Resolve(%q) = %v, want none", tt.spec, got)
+				}
+				return
+			}
+			if len(got) != 1 || got[0] != tt.want {
+				t.Errorf("Resolve(%q) = %v, want [%s]", tt.spec, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolver_TSAliases(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "tsconfig.json"), `{
+		"compilerOptions": { "baseUrl": ".", "paths": { "@/*": ["src/*"] } }
+	}`)
+
+	results := []scanner.FileImports{
+		{File: "src/app.ts", Lang: "js"},
+		{File: "src/components/Button/index.tsx", Lang: "js"},
+		{File: "lib/db.ts", Lang: "js"},
+	}
+	r, err := graph.NewResolver(dir, loadLayout(t, dir), results)
+	if err != nil {
+		t.Fatalf("NewResolver: %v", err)
+	}
+
+	tests := []struct {
+		spec string
+		want string
+	}{
+		{"@/components/Button", "src/components/Button/index.tsx"},
+		{"lib/db", "lib/db.ts"},
+		{"@/missing", ""},
+		{"react", ""},
+	}
+	for _, tt := range tests {
+		got := r.Resolve("src/app.ts", "js", tt.spec)
+		if tt.want == "" {
+			if len(got) != 0 {
+				t.Errorf("Resolve(%q) = %v, want none", tt.spec, got)
+			}
+			continue
+		}
+		if len(got) != 1 || got[0] != filepath.FromSlash(tt.want) {
+			t.Errorf("Resolve(%q) = %v, want [%s]", tt.spec, got, tt.want)
+		}
+	}
+}
+
+func TestResolver_Go(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "go.mod"), "module github.com/acme/app\n\ngo 1.25\n")
+
+	results := []scanner.FileImports{
+		{File: "main.go", Lang: "go"},
+		{File: filepath.Join("internal", "foo", "b.go"), Lang: "go"},
+		{File: filepath.Join("internal", "foo", "a.go"), Lang: "go"},
+		{File: filepath.Join("internal", "foo", "a_test.go"), Lang: "go", IsTest: true},
+	}
+	r, err := graph.NewResolver(dir, loadLayout(t, dir), results)
+	if err != nil {
+		t.Fatalf("NewResolver: %v", err)
+	}
+
+	got := r.Resolve("main.go", "go", "github.com/acme/app/internal/foo")
+	want := []string{filepath.Join("internal", "foo", "a.go"), filepath.Join("internal", "foo", "b.go")}
+	if !slicesEqual(got, want) {
+		t.Errorf("Resolve(package) = %v, want %v", got, want)
+	}
+
+	if got := r.Resolve("main.go", "go", "github.com/acme/app"); !slicesEqual(got, []string{"main.go"}) {
+		t.Errorf("Resolve(module root) = %v, want [main.go]", got)
+	}
+	if got := r.Resolve("main.go", "go", "github.com/acme/application"); len(got) != 0 {
+		t.Errorf("Resolve(prefix lookalike) = %v, want none", got)
+	}
+	if got := r.Resolve("main.go", "go", "fmt"); len(got) != 0 {
+		t.Errorf("Resolve(stdlib) = %v, want none", got)
+	}
+}
+
+func TestResolver_GoWorkspace(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "go.work"), "go 1.25\n\nuse (\n\t.\n\t./tools\n\t./libs/shared\n)\n")
+	writeFile(t, filepath.Join(dir, "go.mod"), "module example.com/app\n")
+	writeFile(t, filepath.Join(dir, "tools", "go.mod"), "module example.com/app/tools\n")
+	writeFile(t, filepath.Join(dir, "libs", "shared", "go.mod"), "module example.com/shared\n")
+
+	results := []scanner.FileImports{
+		{File: "main.go", Lang: "go"},
+		{File: filepath.Join("tools", "gen.go"), Lang: "go"},
+		{File: filepath.Join("libs", "shared", "log", "log.go"), Lang: "go"},
+	}
+	r, err := graph.NewResolver(dir, loadLayout(t, dir), results)
+	if err != nil {
+		t.Fatalf("NewResolver: %v", err)
+	}
+
+	tests := []struct {
+		from, spec string
+		want       []string
+		cross      string
+	}{
+		{"main.go", "example.com/shared/log", []string{filepath.Join("libs", "shared", "log", "log.go")}, "example.com/shared"},
+		{"main.go", "example.com/app/tools", []string{filepath.Join("tools", "gen.go")}, "example.com/app/tools"},
+		{filepath.Join("tools", "gen.go"), "example.com/app", []string{"main.go"}, "example.com/app"},
+		{filepath.Join("libs", "shared", "log", "log.go"), "example.com/shared/log", []string{filepath.Join("libs", "shared", "log", "log.go")}, ""},
+		{"main.go", "example.com/other", nil, ""},
+	}
+	for _, tt := range tests {
+		if got := r.Resolve(tt.from, "go", tt.spec); !slicesEqual(got, tt.want) {
+			t.Errorf("Resolve(%s) = %v, want %v", tt.spec, got, tt.want)
+		}
+		if got := r.CrossModule(tt.from, "go", tt.spec); got != tt.cross {
+			t.Errorf("CrossModule(%s, %s) = %q, want %q", tt.from, tt.spec, got, tt.cross)
+		}
+	}
+}
+
+func TestResolver_JSWorkspace(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "pnpm-workspace.yaml"), "packages:\n  - packages/*\n  - apps/*\n")
+	writeFile(t, filepath.Join(dir, "packages", "ui", "package.json"), `{"name": "@acme/ui", "main": "dist/index.js"}`)
+	writeFile(t, filepath.Join(dir, "packages", "utils", "package.json"), `{"name": "@acme/utils"}`)
+	writeFile(t, filepath.Join(dir, "apps", "web", "package.json"), `{"name": "web"}`)
+
+	web := filepath.Join("apps", "web", "src", "page.tsx")
+	ui := filepath.Join("packages", "ui", "src", "index.ts")
+	button := filepath.Join("packages", "ui", "src", "button.tsx")
+	utils := filepath.Join("packages", "utils", "index.js")
+	results := []scanner.FileImports{
+		{File: web, Lang: "js"}, {File: ui, Lang: "js"}, {File: button, Lang: "js"}, {File: utils, Lang: "js"},
+	}
+	r, err := graph.NewResolver(dir, loadLayout(t, dir), results)
+	if err != nil {
+		t.Fatalf("NewResolver: %v", err)
+	}
+
+	tests := []struct {
+		from, spec, want, cross string
+	}{
+		{web, "@acme/ui", ui, "@acme/ui"},
+		{web, "@acme/ui/button", button, "@acme/ui"},
+		{web, "@acme/utils", utils, "@acme/utils"},
+		{button, "@acme/ui", ui, ""},
+		{web, "@acme/uikit", "", ""},
+		{web, "react", "", ""},
+	}
+	for _, tt := range tests {
+		got := r.Resolve(tt.from, "js", tt.spec)
+		if (tt.want == "" && len(got) != 0) || (tt.want != "" && !slicesEqual(got, []string{tt.want})) {
+			t.Errorf("Resolve(%s) = %v, want %s", tt.spec, got, tt.want)
+		}
+		if got := r.CrossModule(tt.from, "js", tt.spec); got != tt.cross {
+			t.Errorf("CrossModule(%s, %s) = %q, want %q", tt.from, tt.spec, got, tt.cross)
+		}
+	}
+	if got := r.Module(web, "js"); got != "web" {
+		t.Errorf("Module(%s) = %q, want web", web, got)
+	}
+}
+
+func TestResolver_JVM(t *testing.T) {
+	t.Parallel()
+
+	results := []scanner.FileImports{
+		{File: "src/main/java/com/acme/App.java", Lang: "java", Package: "com.acme"},
+		{File: "src/main/java/com/acme/db/Store.java", Lang: "java", Package: "com.acme.db"},
+		{File: "src/main/kotlin/com/acme/db/Query.kt", Lang: "kotlin", Package: "com.acme.db"},
+	}
+	r, err := graph.NewResolver(t.TempDir(), config.Layout{}, results)
+	if err != nil {
+		t.Fatalf("NewResolver: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		spec string
+		want []string
+	}{
+		{"type", "com.acme.db.Store", []string{"src/main/java/com/acme/db/Store.java"}},
+		{"kotlin type", "com.acme.db.Query", []string{"src/main/kotlin/com/acme/db/Query.kt"}},
+		{"nested type", "com.acme.db.Store.Entry", []string{"src/main/java/com/acme/db/Store.java"}},
+		{"wildcard package", "com.acme.db", []string{"src/main/java/com/acme/db/Store.java", "src/main/kotlin/com/acme/db/Query.kt"}},
+		{"stdlib", "java.util.List", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := r.Resolve("src/main/java/com/acme/App.java", "java", tt.spec); !slicesEqual(got, tt.want) {
+				t.Errorf("Resolve(%q) = %v, want %v", tt.spec, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolver_Python(t *testing.T) {
+	t.Parallel()
+
+	from := func(file, spec string, names ...string) scanner.FileImports {
+		return scanner.FileImports{File: file, Lang: "python", Imports: []string{spec}, Details: []scanner.ImportDetail{{Path: spec, Kind: scanner.ImportNamed, Names: names}}}
+	}
+	results := []scanner.FileImports{
+		from("pkg/a.py", ".", "b", "helper as h"),
+		from("pkg/b.py", ".a", "run"),
+		from("pkg/sub/c.py", "..", "*"),
+		{File: "pkg/__init__.py", Lang: "python"},
+		{File: "pkg/sub/__init__.py", Lang: "python"},
+		{File: "src/app/main.py", Lang: "python"},
+		{File: "src/app/models.pyi", Lang: "python"},
+		{File: "cli.py", Lang: "python"},
+	}
+	r, err := graph.NewResolver(t.TempDir(), config.Layout{}, results)
+	if err != nil {
+		t.Fatalf("NewResolver: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		from string
+		spec string
+		want []string
+	}{
+		{"from . import module", "pkg/a.py", ".", []string{"pkg/__init__.py", "pkg/b.py"}},
+		{"relative module", "pkg/b.py", ".a", []string{"pkg/a.py"}},
+		{"parent package", "pkg/sub/c.py", "..", []string{"pkg/__init__.py"}},
+		{"above root", "cli.py", "..", nil},
+		{"absolute module", "cli.py", "pkg.sub.c", []string{"pkg/sub/c.py"}},
+		{"package", "cli.py", "pkg.sub", []string{"pkg/sub/__init__.py"}},
+		{"src layout", "cli.py", "app.main", []string{"src/app/main.py"}},
+		{"stub", "cli.py", "app.models", []string{"src/app/models.pyi"}},
+		{"external", "cli.py", "requests", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := r.Resolve(tt.from, "python", tt.spec); !slicesEqual(got, tt.want) {
+				t.Errorf("Resolve(%q) = %v, want %v", tt.spec, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolver_Rust(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "Cargo.toml"), []byte("[package]\nname = \"my-app\"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	results := []scanner.FileImports{
+		{File: "src/main.rs", Lang: "rust", Details: []scanner.ImportDetail{{Path: "crate", Names: []string{"db", "api::handler as h", "Config"}}}},
+		{File: "src/lib.rs", Lang: "rust"},
+		{File: "src/db.rs", Lang: "rust"},
+		{File: "src/db/pool.rs", Lang: "rust"},
+		{File: "src/api/mod.rs", Lang: "rust"},
+		{File: "src/api/handler.rs", Lang: "rust"},
+		{File: "src/bin/tool.rs", Lang: "rust"},
+		{File: "src/bin/cli/mod.rs", Lang: "rust"},
+		{File: "src/bin/cli/args.rs", Lang: "rust"},
+		{File: "tests/it.rs", Lang: "rust"},
+		{File: "tests/common/mod.rs", Lang: "rust"},
+	}
+	r, err := graph.NewResolver(dir, loadLayout(t, dir), results)
+	if err != nil {
+		t.Fatalf("NewResolver: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		from string
+		spec string
+		want []string
+	}{
+		{"mod in crate root", "src/lib.rs", "self::db", []string{"src/db.rs"}},
+		{"mod in non-mod file", "src/db.rs", "self::pool", []string{"src/db/pool.rs"}},
+		{"mod.rs child", "src/api/mod.rs", "self::handler", []string{"src/api/handler.rs"}},
+		{"crate path to item", "src/api/handler.rs", "crate::db::pool", []string{"src/db/pool.rs"}},
+		{"item in module", "src/api/handler.rs", "crate::db", []string{"src/db.rs"}},
+		{"super from file", "src/db/pool.rs", "super", []string{"src/db.rs"}},
+		{"super from mod.rs", "src/api/handler.rs", "super::super::db", []string{"src/db.rs"}},
+		{"use group modules", "src/main.rs", "crate", []string{"src/main.rs", "src/db.rs", "src/api/handler.rs"}},
+		{"crate from module", "src/api/mod.rs", "crate::db", []string{"src/db.rs"}},
+		{"binary's own crate", "src/bin/tool.rs", "crate::cli::args", []string{"src/bin/cli/args.rs"}},
+		{"crate name", "src/bin/tool.rs", "my_app::api", []string{"src/api/mod.rs"}},
+		{"test crate mod", "tests/it.rs", "self::common", []string{"tests/common/mod.rs"}},
+		{"inline module", "src/lib.rs", "self::inline::Thing", []string{"src/lib.rs"}},
+		{"external", "src/lib.rs", "serde::Deserialize", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := r.Resolve(tt.from, "rust", tt.spec); !slicesEqual(got, tt.want) {
+				t.Errorf("Resolve(%q) = %v, want %v", tt.spec, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolver_NoGoMod(t *testing.T) {
+	t.Parallel()
+
+	r, err := graph.NewResolver(t.TempDir(), config.Layout{}, []scanner.FileImports{{File: "main.go", Lang: "go"}})
+	if err != nil {
+		t.Fatalf("NewResolver: %v", err)
+	}
+	if got := r.Resolve("main.go", "go", "github.com/acme/app"); len(got) != 0 {
+		t.Errorf("Resolve without go.mod = %v, want none", got)
+	}
+}
+
+func TestBuild(t *testing.T) {
+	t.Parallel()
+
+	results := []scanner.FileImports{
+		{
+			File: "src/app.ts", Lang: "js",
+			Imports: []string{"react", "./utils", "./api"},
+			Details: []scanner.ImportDetail{
+				{Path: "react", Kind: scanner.ImportDefault, Line: 1},
+				{Path: "./utils", Kind: scanner.ImportNamed, Line: 2},
+				{Path: "./api", Kind: scanner.ImportNamed, Line: 3},
+			},
+		},
+		{File: "src/api.ts", Lang: "js", Imports: []string{"./utils"}},
+		{File: "src/utils.ts", Lang: "js"},
+	}
+
+	g, err := graph.Build(t.TempDir(), config.Layout{}, results)
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+
+	if n := len(g.Nodes()); n != 3 {
+		t.Fatalf("got %d nodes, want 3", n)
+	}
+	if n := len(g.Edges()); n != 3 {
+		t.Fatalf("got %d edges, want 3", n)
+	}
+
+	app := g.Node("src/app.ts")
+	if len(app.Out) != 2 {
+		t.Fatalf("app.ts out edges = %d, want 2", len(app.Out))
+	}
+	if e := app.Out[0]; e.To != "src/utils.ts" || e.Import.Line != 2 {
+		t.Errorf("first edge = %+v, want utils.ts at line 2", e)
+	}
+
+	utils := g.Node("src/utils.ts")
+	if len(utils.In) != 2 {
+		t.Fatalf("utils.ts in edges = %d, want 2", len(utils.In))
+	}
+	// In edges follow sorted source order.
+	if utils.In[0].From != "src/api.ts" || utils.In[1].From != "src/app.ts" {
+		t.Errorf("utils.ts in edges = %v, %v", utils.In[0].From, utils.In[1].From)
+	}
+
+	if g.Node("react") != nil {
+		t.Error("external imports must not become nodes")
+	}
+}
+
+func TestFilter(t *testing.T) {
+	t.Parallel()
+
+	results := []scanner.FileImports{
+		{File: "a.js", Lang: "js", Imports: []string{"./b", "./c"}},
+		{File: "b.js", Lang: "js"},
+		{File: "c.js", Lang: "js"},
+	}
+	g, err := graph.Build(t.TempDir(), config.Layout{}, results)
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+
+	f := g.Filter(func(e graph.Edge) bool { return e.To != "c.js" })
+	if n := len(f.Edges()); n != 1 {
+		t.Errorf("filtered edges = %d, want 1", n)
+	}
+	if n := len(f.Nodes()); n != 3 {
+		t.Errorf("filtered nodes = %d, want 3", n)
+	}
+	if n := len(g.Edges()); n != 2 {
+		t.Errorf("original edges = %d, want 2 (Filter must not mutate)", n)
+	}
+}
+
+func TestCycles(t *testing.T) {
+	t.Parallel()
+
+	line := func(path string, n int) scanner.ImportDetail {
+		return scanner.ImportDetail{Path: path, Line: n}
+	}
+	results := []scanner.FileImports{
+		// a → b → c → a, plus b → a shortcut
+		{File: "a.js", Lang: "js", Imports: []string{"./b"}, Details: []scanner.ImportDetail{line("./b", 1)}},
+		{File: "b.js", Lang: "js", Imports: []string{"./c", "./a"}, Details: []scanner.ImportDetail{line("./c", 2), line("./a", 3)}},
+		{File: "c.js", Lang: "js", Imports: []string{"./a"}, Details: []scanner.ImportDetail{line("./a", 4)}},
+		// x ↔ y
+		{File: "x.js", Lang: "js", Imports: []string{"./y"}},
+		{File: "y.js", Lang: "js", Imports: []string{"./x", "./leaf"}},
+		{File: "leaf.js", Lang: "js"},
+	}
+
+	g, err := graph.Build(t.TempDir(), config.Layout{}, results)
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+
+	cycles := g.Cycles()
+	if len(cycles) != 2 {
+		t.Fatalf("got %d cycles, want 2", len(cycles))
+	}
+
+	abc := cycles[0]
+	if !slicesEqual(abc.Files, []string{"a.js", "b.js", "c.js"}) {
+		t.Errorf("Files = %v, want [a.js b.js c.js]", abc.Files)
+	}
+	// Shortest loop from a.js is a → b → a.
+	if len(abc.Path) != 2 {
+		t.Fatalf("Path len = %d, want 2", len(abc.Path))
+	}
+	if abc.Path[0].From != "a.js" || abc.Path[0].Import.Line != 1 {
+		t.Errorf("Path[0] = %+v", abc.Path[0])
+	}
+	if abc.Path[1].From != "b.js" || abc.Path[1].To != "a.js" || abc.Path[1].Import.Line != 3 {
+		t.Errorf("Path[1] = %+v", abc.Path[1])
+	}
+
+	if !slicesEqual(cycles[1].Files, []string{"x.js", "y.js"}) {
+		t.Errorf("second cycle = %v, want [x.js y.js]", cycles[1].Files)
+	}
+}
+
+func TestCycles_None(t *testing.T) {
+	t.Parallel()
+
+	results := []scanner.FileImports{
+		{File: "a.js", Lang: "js", Imports: []string{"./b"}},
+		{File: "b.js", Lang: "js", Imports: []string{"./c"}},
+		{File: "c.js", Lang: "js"},
+	}
+	g, err := graph.Build(t.TempDir(), config.Layout{}, results)
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+	if c := g.Cycles(); len(c) != 0 {
+		t.Errorf("got %d cycles, want 0", len(c))
+	}
+	if n := len(g.SCCs()); n != 3 {
+		t.Errorf("got %d SCCs, want 3 singletons", n)
+	}
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func slicesEqual(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestReachable(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module example.com/app\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	results := []scanner.FileImports{
+		{File: "main.go", Lang: "go", Imports: []string{"example.com/app/store"}},
+		{File: "version.go", Lang: "go"},
+		{File: filepath.Join("store", "store.go"), Lang: "go"},
+		{File: filepath.Join("store", "util.go"), Lang: "go"},
+		{File: filepath.Join("old", "old.go"), Lang: "go"},
+		{File: "web/index.ts", Lang: "js", Imports: []string{"./app"}},
+		{File: "web/app.ts", Lang: "js", Imports: []string{"./index"}},
+		{File: "web/unused.ts", Lang: "js", Imports: []string{"./app"}},
+	}
+	g, err := graph.Build(dir, loadLayout(t, dir), results)
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+
+	got := g.Reachable([]string{"main.go", "web/index.ts", "missing.ts"})
+
+	want := []string{"main.go", "version.go", filepath.Join("store", "store.go"), filepath.Join("store", "util.go"), "web/index.ts", "web/app.ts"}
+	if len(got) != len(want) {
+		t.Errorf("reached %d files, want %d: %v", len(got), len(want), got)
+	}
+	for _, f := range want {
+		if !got[f] {
+			t.Errorf("%s not reached", f)
+		}
+	}
+	for _, f := range []string{filepath.Join("old", "old.go"), "web/unused.ts"} {
+		if got[f] {
+			t.Errorf("%s reached, want unreachable", f)
+		}
+	}
+}
+
+func TestDependents(t *testing.T) {
+	t.Parallel()
+
+	results := []scanner.FileImports{
+		{File: "app.ts", Lang: "js", Imports: []string{"./page", "./util"}},
+		{File: "page.ts", Lang: "js", Imports: []string{"./button"}},
+		{File: "button.ts", Lang: "js", Imports: []string{"./util"}},
+		{File: "util.ts", Lang: "js"},
+		{File: "other.ts", Lang: "js", Imports: []string{"./page"}},
+		{File: "loop.ts", Lang: "js", Imports: []string{"./util", "./loop2"}},
+		{File: "loop2.ts", Lang: "js", Imports: []string{"./loop"}},
+	}
+	g, err := graph.Build(t.TempDir(), config.Layout{}, results)
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		files []string
+		want  [][]string
+	}{
+		{"transitive", []string{"button.ts"}, [][]string{{"page.ts"}, {"app.ts", "other.ts"}}},
+		// app.ts imports util directly, so it's at depth 1 not 3.
+		{"shortest depth", []string{"util.ts"}, [][]string{{"app.ts", "button.ts", "loop.ts"}, {"loop2.ts", "page.ts"}, {"other.ts"}}},
+		{"several targets", []string{"page.ts", "button.ts"}, [][]string{{"app.ts", "other.ts"}}},
+		{"no dependents", []string{"app.ts"}, nil},
+		{"unknown file", []string{"missing.ts"}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got := g.Dependents(tt.files)
+			if len(got) != len(tt.want) {
+				t.Fatalf("Dependents = %v, want %v", got, tt.want)
+			}
+			for i := range tt.want {
+				if strings.Join(got[i], ",") != strings.Join(tt.want[i], ",") {
+					t.Errorf("level %d = %v, want %v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+// loadLayout finds the Go modules and workspace packages of the project
+// written to dir.
+func loadLayout(t *testing.T, dir string) config.Layout {
+	t.Helper()
+	l, err := config.LoadLayout(dir, "multi")
+	if err != nil {
+		t.Fatalf("LoadLayout: %v", err)
+	}
+	return l
+}
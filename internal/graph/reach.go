@@ -0,0 +1,81 @@
+package graph
+
+import (
+	"path/filepath"
+	"sort"
+)
+
+// Reachable returns every file reachable from roots by following edges,
+// roots included. A Go package is compiled as a unit, so reaching one Go
+// file reaches the rest of its directory too — main.go's siblings in
+// package main are never imported, but they're part of the binary.
+func (g *Graph) Reachable(roots []string) map[string]bool {
+	goPkgs := map[string][]string{}
+	for _, f := range g.files {
+		if g.nodes[f].Lang == "go" {
+			dir := filepath.Dir(f)
+			goPkgs[dir] = append(goPkgs[dir], f)
+		}
+	}
+
+	seen := make(map[string]bool, len(g.files))
+	var queue []string
+	push := func(f string) {
+		if !seen[f] && g.nodes[f] != nil {
+			seen[f] = true
+			queue = append(queue, f)
+		}
+	}
+	for _, r := range roots {
+		push(r)
+	}
+	for len(queue) > 0 {
+		f := queue[0]
+		queue = queue[1:]
+		n := g.nodes[f]
+		if n.Lang == "go" {
+			for _, sib := range goPkgs[filepath.Dir(f)] {
+				push(sib)
+			}
+		}
+		for _, e := range n.Out {
+			push(e.To)
+		}
+	}
+	return seen
+}
+
+// Dependents returns every file that transitively imports any of files,
+// grouped by distance: level 0 holds the direct importers, level 1 their
+// importers, and so on. Each file appears once, at its shortest distance;
+// files themselves are never included. Levels are sorted.
+func (g *Graph) Dependents(files []string) [][]string {
+	seen := map[string]bool{}
+	var frontier []string
+	for _, f := range files {
+		if g.nodes[f] != nil && !seen[f] {
+			seen[f] = true
+			frontier = append(frontier, f)
+		}
+	}
+
+	var levels [][]string
+	for len(frontier) > 0 {
+		var next []string
+		for _, f := range frontier {
+			for _, e := range g.nodes[f].In {
+				if !seen[e.From] {
+					seen[e.From] = true
+					next = append(next, e.From)
+				}
+			}
+		}
+		if len(next) == 0 {
+			break
+		}
+		sort.Strings(next)
+		levels = append(levels, next)
+		frontier = next
+	}
+	return levels
+}
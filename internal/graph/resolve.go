@@ -0,0 +1,456 @@
+package graph
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/jtoloui/depviz/internal/config"
+	"github.com/jtoloui/depviz/internal/scanner"
+	"github.com/jtoloui/depviz/internal/tsconfig"
+)
+
+// jsExts is the probe order for extensionless JS/TS specifiers — TS first so
+// a compiled .js sitting next to its .ts source doesn't win.
+var jsExts = []string{".ts", ".tsx", ".js", ".jsx", ".mjs"}
+
+// Resolver maps import specifiers to files present in a scan.
+type Resolver struct {
+	root     string
+	modules  []config.GoModule
+	jsPkgs   []config.JSPackage
+	ts       *tsconfig.Config // nil without a tsconfig.json/jsconfig.json
+	files    map[string]bool
+	goPkgs   map[string][]string // package dir → Go non-test files in it
+	jvmTypes map[string]string   // fully qualified top-level type → file
+	jvmPkgs  map[string][]string // declared package → Java/Kotlin files in it
+	crate    string              // package name from root/Cargo.toml, as an identifier
+	// names holds the names each Python or Rust file imports from each
+	// specifier, so `from . import b` and `use crate::{db, api}` can
+	// resolve to the modules they name.
+	names map[string]map[string][]string
+}
+
+// NewResolver indexes results for resolution. Go modules and JS/TS
+// workspace packages come from layout, and JS/TS aliases from
+// root/tsconfig.json when present; a missing file just disables that kind
+// of resolution.
+func NewResolver(root string, layout config.Layout, results []scanner.FileImports) (*Resolver, error) {
+	ts, err := tsconfig.Load(root)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("reading tsconfig: %w", err)
+	}
+	var crate string
+	cargo, err := config.ReadCargo(root)
+	switch {
+	case err == nil:
+		crate = cargo.Name
+	case !errors.Is(err, os.ErrNotExist):
+		return nil, fmt.Errorf("reading Cargo.toml: %w", err)
+	}
+
+	r := &Resolver{
+		root:     root,
+		modules:  layout.Modules,
+		jsPkgs:   layout.Packages,
+		ts:       ts,
+		crate:    crate,
+		files:    make(map[string]bool, len(results)),
+		goPkgs:   map[string][]string{},
+		jvmTypes: map[string]string{},
+		jvmPkgs:  map[string][]string{},
+		names:    map[string]map[string][]string{},
+	}
+	for _, fi := range results {
+		r.files[fi.File] = true
+		// Test files are compiled only into their own package's test
+		// binary, so no import resolves to them.
+		if fi.Lang == "go" && !fi.IsTest {
+			dir := filepath.Dir(fi.File)
+			r.goPkgs[dir] = append(r.goPkgs[dir], fi.File)
+		}
+		if fi.Lang == "java" || fi.Lang == "kotlin" {
+			// Java requires the public type to match the file name; Kotlin
+			// doesn't, but it's the overwhelming convention.
+			name := strings.TrimSuffix(filepath.Base(fi.File), filepath.Ext(fi.File))
+			r.jvmTypes[qualify(fi.Package, name)] = fi.File
+			r.jvmPkgs[fi.Package] = append(r.jvmPkgs[fi.Package], fi.File)
+		}
+		if fi.Lang == "python" || fi.Lang == "rust" {
+			r.indexNames(fi)
+		}
+	}
+	for _, files := range r.goPkgs {
+		sort.Strings(files)
+	}
+	for _, files := range r.jvmPkgs {
+		sort.Strings(files)
+	}
+	return r, nil
+}
+
+// Resolve returns the scanned files that spec, imported from the file from,
+// refers to. A Go import, like a Java/Kotlin wildcard import, resolves to
+// every file in the package, and a Python `from pkg import a, b` or Rust
+// `use crate::{a, b}` to the module and any of a and b that are modules. It returns nil for stdlib, external
+// and unresolvable specifiers.
+func (r *Resolver) Resolve(from, lang, spec string) []string {
+	switch lang {
+	case "go":
+		return r.resolveGo(spec)
+	case "js":
+		if f := r.resolveJS(from, spec); f != "" {
+			return []string{f}
+		}
+	case "java", "kotlin":
+		return r.resolveJVM(spec)
+	case "python":
+		return r.resolvePython(from, spec)
+	case "rust":
+		return r.resolveRust(from, spec)
+	}
+	return nil
+}
+
+// indexNames records the names fi imports from each specifier, without
+// their aliases.
+func (r *Resolver) indexNames(fi scanner.FileImports) {
+	specs := map[string][]string{}
+	for _, d := range fi.Details {
+		for _, n := range d.Names {
+			n, _, _ = strings.Cut(n, " as ")
+			if n = strings.TrimSpace(n); n != "*" && !slices.Contains(specs[d.Path], n) {
+				specs[d.Path] = append(specs[d.Path], n)
+			}
+		}
+	}
+	r.names[fi.File] = specs
+}
+
+// resolveRust maps a crate-relative path — crate::, self::, super:: or the
+// crate's own name — to the file of the deepest module it names, following
+// the x.rs or x/mod.rs layout. `mod x;` is scanned as self::x. Names taken
+// by a use group that are modules themselves (`use crate::{db, api}`)
+// resolve too; inline `mod x { }` blocks resolve to the file holding them.
+func (r *Resolver) resolveRust(from, spec string) []string {
+	segs := strings.Split(spec, "::")
+	var dir, file string
+	switch {
+	case segs[0] == "crate" && r.rustRoot(from):
+		// A binary, test or example is a crate of its own.
+		dir, file = filepath.Dir(from), from
+		segs = segs[1:]
+	case segs[0] == "crate" || r.crate != "" && segs[0] == r.crate:
+		dir = r.rustCrateDir(from)
+		file = r.rustModFile(dir)
+		segs = segs[1:]
+	case segs[0] == "self" || segs[0] == "super":
+		dir, file = r.rustModDir(from), from
+		if segs[0] == "self" {
+			segs = segs[1:]
+		}
+		for len(segs) > 0 && segs[0] == "super" {
+			dir = filepath.Dir(dir)
+			file = r.rustModFile(dir)
+			segs = segs[1:]
+		}
+	}
+	if dir == "" {
+		return nil
+	}
+
+	var out []string
+	dir, file = r.rustWalk(dir, file, segs)
+	if file != "" {
+		out = append(out, file)
+	}
+	for _, name := range r.names[from][spec] {
+		if sub, f := r.rustWalk(dir, file, strings.Split(name, "::")); sub != dir && !slices.Contains(out, f) {
+			out = append(out, f)
+		}
+	}
+	return out
+}
+
+// rustWalk follows segs down the module tree from the module in file, whose
+// children live in dir, stopping at the first segment that isn't a module
+// file. It returns the directory and file of the module it reached.
+func (r *Resolver) rustWalk(dir, file string, segs []string) (string, string) {
+	for _, seg := range segs {
+		next := filepath.Join(dir, seg)
+		switch {
+		case r.files[next+".rs"]:
+			file = next + ".rs"
+		case r.files[filepath.Join(next, "mod.rs")]:
+			file = filepath.Join(next, "mod.rs")
+		default:
+			return dir, file
+		}
+		dir = next
+	}
+	return dir, file
+}
+
+// rustModFile returns the file of the module whose children live in dir:
+// dir.rs, dir/mod.rs, or the crate root for a crate's src/.
+func (r *Resolver) rustModFile(dir string) string {
+	for _, f := range []string{dir + ".rs", filepath.Join(dir, "mod.rs"), filepath.Join(dir, "lib.rs"), filepath.Join(dir, "main.rs")} {
+		if r.files[f] {
+			return f
+		}
+	}
+	return ""
+}
+
+// rustModDir returns the directory the child modules of file live in: its
+// own directory for mod.rs and crate roots, otherwise a directory named
+// after it.
+func (r *Resolver) rustModDir(file string) string {
+	if filepath.Base(file) == "mod.rs" || r.rustRoot(file) {
+		return filepath.Dir(file)
+	}
+	return strings.TrimSuffix(file, ".rs")
+}
+
+// rustRoot reports whether file is the root of a crate: src/lib.rs,
+// src/main.rs, or a file directly in src/bin, tests, examples or benches.
+func (r *Resolver) rustRoot(file string) bool {
+	src := r.rustCrateDir(file)
+	if src == "" {
+		return false
+	}
+	pkg := filepath.Dir(src)
+	switch filepath.Dir(file) {
+	case src:
+		return filepath.Base(file) == "lib.rs" || filepath.Base(file) == "main.rs"
+	case filepath.Join(src, "bin"), filepath.Join(pkg, "tests"), filepath.Join(pkg, "examples"), filepath.Join(pkg, "benches"):
+		return true
+	}
+	return false
+}
+
+// rustCrateDir returns the src/ directory of the crate file belongs to: the
+// closest one, going up from file, that holds a scanned lib.rs or main.rs.
+func (r *Resolver) rustCrateDir(file string) string {
+	for dir := filepath.Dir(file); ; dir = filepath.Dir(dir) {
+		src := filepath.Join(dir, "src")
+		if r.files[filepath.Join(src, "lib.rs")] || r.files[filepath.Join(src, "main.rs")] {
+			return src
+		}
+		if dir == "." {
+			return ""
+		}
+	}
+}
+
+// pythonRoots are the directories absolute Python imports are looked up
+// from: the project root and a src/ layout.
+var pythonRoots = []string{".", "src"}
+
+// resolvePython maps a module to its .py or .pyi file, or its package's
+// __init__.py. Relative imports start from the importing file's package,
+// one level up per extra dot; absolute ones from each of pythonRoots in
+// turn. Names imported from a package that are submodules of it, as in
+// `from . import b`, resolve to those modules too.
+func (r *Resolver) resolvePython(from, spec string) []string {
+	rest := strings.TrimLeft(spec, ".")
+	bases := pythonRoots
+	if dots := len(spec) - len(rest); dots > 0 {
+		base := filepath.Dir(from)
+		for range dots - 1 {
+			if base == "." {
+				return nil
+			}
+			base = filepath.Dir(base)
+		}
+		bases = []string{base}
+	}
+	for _, base := range bases {
+		dir := filepath.Join(base, filepath.FromSlash(strings.ReplaceAll(rest, ".", "/")))
+		var out []string
+		if f := r.pythonModule(dir); f != "" {
+			out = append(out, f)
+		}
+		for _, name := range r.names[from][spec] {
+			if f := r.pythonModule(filepath.Join(dir, name)); f != "" && !slices.Contains(out, f) {
+				out = append(out, f)
+			}
+		}
+		if len(out) > 0 {
+			return out
+		}
+	}
+	return nil
+}
+
+// pythonModule returns the scanned file holding the module at path, which
+// has no extension.
+func (r *Resolver) pythonModule(path string) string {
+	for _, f := range []string{path + ".py", path + ".pyi", filepath.Join(path, "__init__.py")} {
+		if r.files[f] {
+			return f
+		}
+	}
+	return ""
+}
+
+// resolveGo maps spec into the directory of the project module providing
+// it, so imports between the modules of a workspace resolve too.
+func (r *Resolver) resolveGo(spec string) []string {
+	m, ok := config.ModuleForImport(r.modules, spec)
+	if !ok {
+		return nil
+	}
+	rest := strings.TrimPrefix(strings.TrimPrefix(spec, m.Path), "/")
+	return r.goPkgs[filepath.Join(m.Dir, filepath.FromSlash(rest))]
+}
+
+// Module names the unit of a multi-package project that file belongs to:
+// its Go module when the project has several, or its JS/TS workspace
+// package. It is "" otherwise.
+func (r *Resolver) Module(file, lang string) string {
+	switch lang {
+	case "go":
+		if m, ok := config.ModuleOf(r.modules, file); ok && len(r.modules) > 1 {
+			return m.Path
+		}
+	case "js":
+		if p, ok := config.PackageOf(r.jsPkgs, file); ok {
+			return p.Name
+		}
+	}
+	return ""
+}
+
+// CrossModule returns the other Go module or workspace package of the
+// project that spec, imported from file, reaches into — or "" when spec
+// stays within file's own, or isn't part of the project.
+func (r *Resolver) CrossModule(file, lang, spec string) string {
+	var to string
+	switch lang {
+	case "go":
+		if m, ok := config.ModuleForImport(r.modules, spec); ok && len(r.modules) > 1 {
+			to = m.Path
+		}
+	case "js":
+		if p, ok := config.PackageForImport(r.jsPkgs, spec); ok {
+			to = p.Name
+		}
+	}
+	if to == r.Module(file, lang) {
+		return ""
+	}
+	return to
+}
+
+// resolveJVM maps an import to the file declaring the type. Nested types
+// (a.b.Outer.Inner) and static member imports (a.b.Type.member) resolve to
+// the outermost type's file; a wildcard package import resolves to every
+// file in the package.
+func (r *Resolver) resolveJVM(spec string) []string {
+	for name := spec; name != ""; {
+		if f, ok := r.jvmTypes[name]; ok {
+			return []string{f}
+		}
+		i := strings.LastIndex(name, ".")
+		if i < 0 {
+			break
+		}
+		name = name[:i]
+	}
+	return r.jvmPkgs[spec]
+}
+
+func qualify(pkg, name string) string {
+	if pkg == "" {
+		return name
+	}
+	return pkg + "." + name
+}
+
+func (r *Resolver) resolveJS(from, spec string) string {
+	if isRelative(spec) {
+		return r.probe(filepath.Join(filepath.Dir(from), filepath.FromSlash(spec)))
+	}
+	if p, ok := config.PackageForImport(r.jsPkgs, spec); ok {
+		return r.resolveWorkspace(p, strings.TrimPrefix(strings.TrimPrefix(spec, p.Name), "/"))
+	}
+	if r.ts == nil {
+		return ""
+	}
+	// Aliased (@/components/Button) or baseUrl-rooted (lib/db) specifier.
+	for _, abs := range r.ts.Resolve(spec) {
+		rel, err := filepath.Rel(r.root, abs)
+		if err != nil || strings.HasPrefix(rel, "..") {
+			continue
+		}
+		if f := r.probe(rel); f != "" {
+			return f
+		}
+	}
+	return ""
+}
+
+// resolveWorkspace maps an import of workspace package p to its source.
+// The package name resolves to the first entry its package.json names that
+// was scanned — a build output under dist/ or build/ is looked for under
+// src/ instead — falling back to src/index and index; a subpath resolves
+// within the package directory or its src/.
+func (r *Resolver) resolveWorkspace(p config.JSPackage, sub string) string {
+	var candidates []string
+	if sub == "" {
+		for _, e := range p.Entries {
+			candidates = append(candidates, e)
+			for _, out := range []string{"dist/", "build/"} {
+				if rest, ok := strings.CutPrefix(e, out); ok {
+					candidates = append(candidates, "src/"+rest)
+				}
+			}
+		}
+		candidates = append(candidates, "src", ".")
+	} else {
+		candidates = append(candidates, sub, "src/"+sub)
+	}
+	for _, c := range candidates {
+		if f := r.probe(filepath.Join(p.Dir, filepath.FromSlash(c))); f != "" {
+			return f
+		}
+	}
+	return ""
+}
+
+// probe finds the scanned file base refers to, trying it as-is, with each
+// known extension, and as a directory index. TS projects written for ESM
+// import "./foo.js" while the source is foo.ts, so a JS extension is also
+// retried with the alternatives.
+func (r *Resolver) probe(base string) string {
+	if r.files[base] {
+		return base
+	}
+	candidates := []string{base}
+	if ext := filepath.Ext(base); ext == ".js" || ext == ".jsx" || ext == ".mjs" {
+		candidates = append(candidates, strings.TrimSuffix(base, ext))
+	}
+	for _, c := range candidates {
+		for _, ext := range jsExts {
+			if r.files[c+ext] {
+				return c + ext
+			}
+		}
+	}
+	for _, ext := range jsExts {
+		if idx := filepath.Join(base, "index"+ext); r.files[idx] {
+			return idx
+		}
+	}
+	return ""
+}
+
+func isRelative(spec string) bool {
+	return spec == "." || spec == ".." ||
+		strings.HasPrefix(spec, "./") || strings.HasPrefix(spec, "../")
+}
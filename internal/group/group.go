@@ -0,0 +1,190 @@
+// Package group merges per-file scan results into package or directory
+// nodes, so large projects can be read at a coarser grain.
+package group
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/jtoloui/depviz/internal/scanner"
+)
+
+// Mode is how files are grouped. The zero Mode leaves them as they are.
+type Mode struct {
+	by    string // "", "package", "dir" or "depth"
+	depth int
+}
+
+// ByPackage groups files into packages.
+var ByPackage = Mode{by: "package"}
+
+// Parse reads a --group-by value: "package", "dir" or "depth=N". An empty
+// string means no grouping.
+func Parse(s string) (Mode, error) {
+	switch s {
+	case "":
+		return Mode{}, nil
+	case "package", "dir":
+		return Mode{by: s}, nil
+	}
+	if n, ok := strings.CutPrefix(s, "depth="); ok {
+		depth, err := strconv.Atoi(n)
+		if err != nil || depth < 1 {
+			return Mode{}, fmt.Errorf("invalid group depth %q: want a positive number", n)
+		}
+		return Mode{by: "depth", depth: depth}, nil
+	}
+	return Mode{}, fmt.Errorf("unsupported grouping %q: want package, dir or depth=N", s)
+}
+
+// IsZero reports whether m leaves files ungrouped.
+func (m Mode) IsZero() bool { return m.by == "" }
+
+func (m Mode) String() string {
+	if m.by == "depth" {
+		return "depth=" + strconv.Itoa(m.depth)
+	}
+	return m.by
+}
+
+// name is the group fi belongs to, before any disambiguation by language.
+//
+// package groups Go files by directory (external _test packages included)
+// and Java/Kotlin files by declared package; other languages have no
+// package unit beyond the directory. depth=N groups by the first N
+// directory levels.
+func (m Mode) name(fi scanner.FileImports) string {
+	dir := filepath.ToSlash(filepath.Dir(fi.File))
+	switch m.by {
+	case "package":
+		if (fi.Lang == "java" || fi.Lang == "kotlin") && fi.Package != "" {
+			return fi.Package
+		}
+	case "depth":
+		if parts := strings.Split(dir, "/"); len(parts) > m.depth {
+			return strings.Join(parts[:m.depth], "/")
+		}
+	case "":
+		return fi.File
+	}
+	return dir
+}
+
+// Groups maps every file in results to the name of its group. A group never
+// mixes languages: when two languages share a name, each gets the language
+// appended, e.g. "src (go)".
+func Groups(results []scanner.FileImports, m Mode) map[string]string {
+	langs := map[string]map[string]bool{}
+	for _, fi := range results {
+		n := m.name(fi)
+		if langs[n] == nil {
+			langs[n] = map[string]bool{}
+		}
+		langs[n][fi.Lang] = true
+	}
+	out := make(map[string]string, len(results))
+	for _, fi := range results {
+		n := m.name(fi)
+		if len(langs[n]) > 1 {
+			n = fmt.Sprintf("%s (%s)", n, fi.Lang)
+		}
+		out[fi.File] = n
+	}
+	return out
+}
+
+// Merge returns one FileImports per group, sorted by name: imports are
+// deduplicated by specifier (their named bindings unioned), exports are
+// unioned by name and kind, and Lines are summed. Package and Build are
+// kept only when every file in the group agrees, and a group is a test only
+// if all its files are. Line numbers are dropped
+// since they no longer point into a single file. The zero Mode returns
+// results unchanged.
+func Merge(results []scanner.FileImports, m Mode) []scanner.FileImports {
+	if m.IsZero() {
+		return results
+	}
+	names := Groups(results, m)
+
+	byName := map[string]*merged{}
+	var order []string
+	for _, fi := range results {
+		n := names[fi.File]
+		g, ok := byName[n]
+		if !ok {
+			g = &merged{
+				fi:      scanner.FileImports{File: n, Lang: fi.Lang, Package: fi.Package, Build: fi.Build, IsTest: fi.IsTest},
+				imports: map[string]int{},
+				exports: map[string]bool{},
+			}
+			byName[n] = g
+			order = append(order, n)
+		}
+		g.add(fi)
+	}
+
+	sort.Strings(order)
+	out := make([]scanner.FileImports, len(order))
+	for i, n := range order {
+		out[i] = byName[n].fi
+	}
+	return out
+}
+
+type merged struct {
+	fi      scanner.FileImports
+	imports map[string]int  // specifier → index in fi.Imports
+	exports map[string]bool // kind + name
+}
+
+func (g *merged) add(fi scanner.FileImports) {
+	if g.fi.Package != fi.Package {
+		g.fi.Package = ""
+	}
+	if g.fi.Build != fi.Build {
+		g.fi.Build = ""
+	}
+	g.fi.IsTest = g.fi.IsTest && fi.IsTest
+	g.fi.Lines += fi.Lines
+
+	for i, imp := range fi.Imports {
+		d := scanner.ImportDetail{Path: imp}
+		if i < len(fi.Details) {
+			d = fi.Details[i]
+		}
+		d.Line = 0
+		if j, ok := g.imports[imp]; ok {
+			g.fi.Details[j].Names = union(g.fi.Details[j].Names, d.Names)
+			continue
+		}
+		g.imports[imp] = len(g.fi.Imports)
+		g.fi.Imports = append(g.fi.Imports, imp)
+		g.fi.Details = append(g.fi.Details, d)
+	}
+
+	for _, e := range fi.Exports {
+		k := string(e.Kind) + " " + e.Name
+		if g.exports[k] {
+			continue
+		}
+		g.exports[k] = true
+		e.Line = 0
+		g.fi.Exports = append(g.fi.Exports, e)
+	}
+}
+
+// union appends the names in b missing from a, without touching a's
+// backing array.
+func union(a, b []string) []string {
+	out := slices.Clip(a)
+	for _, n := range b {
+		if !slices.Contains(out, n) {
+			out = append(out, n)
+		}
+	}
+	return out
+}
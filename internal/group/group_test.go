@@ -0,0 +1,156 @@
+package group_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/jtoloui/depviz/internal/group"
+	"github.com/jtoloui/depviz/internal/scanner"
+)
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		in      string
+		want    string
+		wantErr bool
+	}{
+		{in: "", want: ""},
+		{in: "package", want: "package"},
+		{in: "dir", want: "dir"},
+		{in: "depth=2", want: "depth=2"},
+		{in: "depth=0", wantErr: true},
+		{in: "depth=x", wantErr: true},
+		{in: "file", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			t.Parallel()
+			m, err := group.Parse(tt.in)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Parse(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			}
+			if err == nil && m.String() != tt.want {
+				t.Errorf("Parse(%q) = %q, want %q", tt.in, m.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestGroups(t *testing.T) {
+	t.Parallel()
+
+	results := []scanner.FileImports{
+		{File: "main.go", Lang: "go"},
+		{File: "internal/store/db.go", Lang: "go"},
+		{File: "internal/store/db_test.go", Lang: "go"},
+		{File: "web/src/app.ts", Lang: "js"},
+		{File: "web/src/ui/button.ts", Lang: "js"},
+		{File: "tools/gen.go", Lang: "go"},
+		{File: "tools/gen.ts", Lang: "js"},
+		{File: "src/main/java/com/x/A.java", Lang: "java", Package: "com.x"},
+	}
+
+	tests := []struct {
+		mode string
+		want map[string]string
+	}{
+		{"package", map[string]string{
+			"main.go":                    ".",
+			"internal/store/db.go":       "internal/store",
+			"internal/store/db_test.go":  "internal/store",
+			"web/src/app.ts":             "web/src",
+			"web/src/ui/button.ts":       "web/src/ui",
+			"tools/gen.go":               "tools (go)",
+			"tools/gen.ts":               "tools (js)",
+			"src/main/java/com/x/A.java": "com.x",
+		}},
+		{"dir", map[string]string{
+			"main.go":                    ".",
+			"internal/store/db.go":       "internal/store",
+			"internal/store/db_test.go":  "internal/store",
+			"web/src/app.ts":             "web/src",
+			"web/src/ui/button.ts":       "web/src/ui",
+			"tools/gen.go":               "tools (go)",
+			"tools/gen.ts":               "tools (js)",
+			"src/main/java/com/x/A.java": "src/main/java/com/x",
+		}},
+		{"depth=1", map[string]string{
+			"main.go":                    ".",
+			"internal/store/db.go":       "internal",
+			"internal/store/db_test.go":  "internal",
+			"web/src/app.ts":             "web",
+			"web/src/ui/button.ts":       "web",
+			"tools/gen.go":               "tools (go)",
+			"tools/gen.ts":               "tools (js)",
+			"src/main/java/com/x/A.java": "src",
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.mode, func(t *testing.T) {
+			t.Parallel()
+			m, err := group.Parse(tt.mode)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := group.Groups(results, m); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Groups = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMerge(t *testing.T) {
+	t.Parallel()
+
+	results := []scanner.FileImports{
+		{
+			File: "src/ui/button.ts", Lang: "js", Lines: 10,
+			Imports: []string{"react", "./theme"},
+			Details: []scanner.ImportDetail{
+				{Path: "react", Kind: scanner.ImportNamed, Names: []string{"useState"}, Line: 1},
+				{Path: "./theme", Kind: scanner.ImportNamed, Names: []string{"dark"}, Line: 2},
+			},
+			Exports: []scanner.ExportDetail{{Name: "Button", Kind: scanner.ExportFunction, Line: 4}},
+		},
+		{
+			File: "src/ui/link.ts", Lang: "js", Lines: 5,
+			Imports: []string{"react"},
+			Details: []scanner.ImportDetail{{Path: "react", Kind: scanner.ImportNamed, Names: []string{"useState", "useMemo"}, Line: 1}},
+			Exports: []scanner.ExportDetail{{Name: "Link", Kind: scanner.ExportFunction, Line: 2}},
+		},
+		{File: "src/app.ts", Lang: "js", Lines: 3, Imports: []string{"./ui/button"}},
+	}
+
+	m, err := group.Parse("dir")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := group.Merge(results, m)
+
+	if len(got) != 2 || got[0].File != "src" || got[1].File != "src/ui" {
+		t.Fatalf("groups = %+v, want src and src/ui", got)
+	}
+	ui := got[1]
+	if ui.Lines != 15 {
+		t.Errorf("Lines = %d, want 15", ui.Lines)
+	}
+	if !reflect.DeepEqual(ui.Imports, []string{"react", "./theme"}) {
+		t.Errorf("Imports = %v, want deduplicated react, ./theme", ui.Imports)
+	}
+	if d := ui.Details[0]; !reflect.DeepEqual(d.Names, []string{"useState", "useMemo"}) || d.Line != 0 {
+		t.Errorf("react detail = %+v, want unioned names and no line", d)
+	}
+	if len(ui.Exports) != 2 || ui.Exports[1].Name != "Link" || ui.Exports[1].Line != 0 {
+		t.Errorf("Exports = %+v, want Button and Link without lines", ui.Exports)
+	}
+	// The input is left alone.
+	if results[0].Details[0].Line != 1 || len(results[0].Details[0].Names) != 1 {
+		t.Errorf("input mutated: %+v", results[0].Details[0])
+	}
+
+	if got := group.Merge(results, group.Mode{}); len(got) != len(results) {
+		t.Errorf("zero Mode merged %d files into %d", len(results), len(got))
+	}
+}
@@ -0,0 +1,100 @@
+package manifest
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"golang.org/x/mod/modfile"
+)
+
+// loadGo reads the requires, replaces and excludes of the go.mod in dir,
+// relative to root, and marks as locked the versions go.sum has checksums
+// for.
+func (m *Manifests) loadGo(root, dir string) error {
+	file := path.Join(dir, "go.mod")
+	data, err := os.ReadFile(filepath.Join(root, filepath.FromSlash(file)))
+	if missing(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	f, err := modfile.Parse(file, data, nil)
+	if err != nil {
+		return fmt.Errorf("parsing %s: %w", file, err)
+	}
+	m.langs["go"] = true
+	sums, err := goSum(filepath.Join(root, filepath.FromSlash(dir)))
+	if err != nil {
+		return err
+	}
+	for mod := range sums {
+		m.modules[strings.SplitN(mod, "@", 2)[0]] = true
+	}
+
+	replace := map[string]*modfile.Replace{}
+	for _, r := range f.Replace {
+		replace[r.Old.Path] = r
+	}
+	for _, r := range f.Require {
+		d := Dependency{Name: r.Mod.Path, Lang: "go", Kind: Direct, Version: r.Mod.Version, Manifest: file}
+		if r.Indirect {
+			d.Kind = Indirect
+		}
+		locked := r.Mod.Path + "@" + r.Mod.Version
+		// A replace without an old version applies to every version.
+		if rep, ok := replace[r.Mod.Path]; ok && (rep.Old.Version == "" || rep.Old.Version == r.Mod.Version) {
+			d.Replace = rep.New.Path
+			locked = ""
+			if rep.New.Version != "" {
+				d.Replace += "@" + rep.New.Version
+				locked = d.Replace
+			}
+		}
+		if sums[locked] {
+			d.Locked = locked[strings.LastIndex(locked, "@")+1:]
+		}
+		m.modules[r.Mod.Path] = true
+		m.add(d)
+	}
+	for _, x := range f.Exclude {
+		if ex := x.Mod.Path + "@" + x.Mod.Version; !slices.Contains(m.Excluded, ex) {
+			m.Excluded = append(m.Excluded, ex)
+		}
+	}
+	return nil
+}
+
+// goSum returns every module@version in go.sum, mapped to whether it holds
+// a checksum of the module itself rather than only of its go.mod.
+func goSum(root string) (map[string]bool, error) {
+	f, err := os.Open(filepath.Join(root, "go.sum"))
+	if missing(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+
+	sums := map[string]bool{}
+	s := bufio.NewScanner(f)
+	for s.Scan() {
+		fields := strings.Fields(s.Text())
+		if len(fields) != 3 {
+			continue
+		}
+		version, goModOnly := strings.CutSuffix(fields[1], "/go.mod")
+		k := fields[0] + "@" + version
+		sums[k] = sums[k] || !goModOnly
+	}
+	if err := s.Err(); err != nil {
+		return nil, fmt.Errorf("reading go.sum: %w", err)
+	}
+	return sums, nil
+}
@@ -0,0 +1,243 @@
+// Package manifest reads the dependencies a project declares — in go.mod and
+// package.json — and the versions its lockfiles pin, so external imports
+// can be matched to the dependency that provides them.
+package manifest
+
+import (
+	"errors"
+	"io/fs"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/jtoloui/depviz/internal/config"
+)
+
+// Kind is how a dependency is declared.
+type Kind string
+
+const (
+	// Direct is a go.mod require or a package.json dependency.
+	Direct Kind = "direct"
+	// Indirect is a go.mod require marked // indirect.
+	Indirect Kind = "indirect"
+	// Dev is a package.json devDependency.
+	Dev Kind = "dev"
+	// Peer is a package.json peerDependency.
+	Peer Kind = "peer"
+)
+
+// Dependency is one declared dependency.
+type Dependency struct {
+	Name string `json:"name"`
+	// Lang is "go" or "js", matching scanner.FileImports.Lang.
+	Lang string `json:"lang"`
+	Kind Kind   `json:"kind"`
+	// Version is as declared: a go.mod version or a package.json range.
+	Version string `json:"version,omitempty"`
+	// Locked is the version a lockfile pins. For Go it is the go.mod version
+	// (or its replacement's) once go.sum records a checksum for it.
+	Locked string `json:"locked,omitempty"`
+	// Replace is the go.mod replacement, as path or path@version.
+	Replace string `json:"replace,omitempty"`
+	// Manifest is the file declaring it, relative to the project root.
+	Manifest string `json:"manifest"`
+}
+
+// Manifests holds the dependencies declared by a project's manifests: the
+// go.mod of each of its Go modules, and the package.json at its root and in
+// each workspace package.
+type Manifests struct {
+	// Deps is sorted by language, name, then manifest.
+	Deps []Dependency
+	// Excluded lists go.mod exclude directives as path@version.
+	Excluded []string
+
+	layout  config.Layout
+	index   map[string]int  // manifest dir + " " + lang + " " + name → index in Deps
+	modules map[string]bool // Go modules known from go.mod and go.sum
+	langs   map[string]bool // languages with a manifest
+}
+
+// Load reads the go.mod and go.sum of every Go module in layout (or of root
+// when it has none), the package.json of root and of every workspace
+// package in layout, and whichever of package-lock.json, yarn.lock and
+// pnpm-lock.yaml is present in root. Missing files are skipped; a project
+// with none of them has no dependencies.
+func Load(root string, layout config.Layout) (*Manifests, error) {
+	m := &Manifests{layout: layout, index: map[string]int{}, modules: map[string]bool{}, langs: map[string]bool{}}
+	for _, dir := range m.goDirs() {
+		if err := m.loadGo(root, dir); err != nil {
+			return nil, err
+		}
+	}
+	locked, err := jsLocked(root)
+	if err != nil {
+		return nil, err
+	}
+	for _, dir := range m.jsDirs() {
+		if err := m.loadJS(root, dir, locked); err != nil {
+			return nil, err
+		}
+	}
+	sort.Slice(m.Deps, func(i, j int) bool {
+		a, b := m.Deps[i], m.Deps[j]
+		if a.Lang != b.Lang {
+			return a.Lang < b.Lang
+		}
+		if a.Name != b.Name {
+			return a.Name < b.Name
+		}
+		return a.Manifest < b.Manifest
+	})
+	for i, d := range m.Deps {
+		m.index[key(path.Dir(d.Manifest), d.Lang, d.Name)] = i
+	}
+	return m, nil
+}
+
+// goDirs returns the directories of the project's Go modules, or just the
+// root when the layout has none.
+func (m *Manifests) goDirs() []string {
+	if len(m.layout.Modules) == 0 {
+		return []string{"."}
+	}
+	dirs := make([]string, len(m.layout.Modules))
+	for i, mod := range m.layout.Modules {
+		dirs[i] = filepath.ToSlash(mod.Dir)
+	}
+	return dirs
+}
+
+// jsDirs returns the root followed by the directories of the project's
+// workspace packages.
+func (m *Manifests) jsDirs() []string {
+	dirs := []string{"."}
+	for _, p := range m.layout.Packages {
+		dirs = append(dirs, filepath.ToSlash(p.Dir))
+	}
+	return dirs
+}
+
+func key(dir, lang, name string) string {
+	return dir + " " + lang + " " + name
+}
+
+// add records d unless its manifest already declares a dependency of the
+// same name.
+func (m *Manifests) add(d Dependency) {
+	k := key(path.Dir(d.Manifest), d.Lang, d.Name)
+	if _, ok := m.index[k]; ok {
+		return
+	}
+	m.index[k] = len(m.Deps)
+	m.Deps = append(m.Deps, d)
+}
+
+// Covers reports whether the project has a manifest for lang, so its
+// imports can be checked against declarations at all.
+func (m *Manifests) Covers(lang string) bool {
+	return m.langs[lang]
+}
+
+// Lookup returns the declared dependency that provides the import spec of
+// the lang file, as declared by the manifest the file falls under: the
+// go.mod of its Go module, or the package.json of its workspace package
+// and then the root one, whose dependencies every package can import.
+func (m *Manifests) Lookup(file, lang, spec string) (Dependency, bool) {
+	name := m.Root(lang, spec)
+	dir := m.dir(file, lang)
+	i, ok := m.index[key(dir, lang, name)]
+	if !ok && lang == "js" {
+		i, ok = m.index[key(".", lang, name)]
+	}
+	if !ok {
+		return Dependency{}, false
+	}
+	return m.Deps[i], true
+}
+
+// Manifest returns the manifest, relative to the root, that declares the
+// dependencies of the lang file, or "" for a language without manifests.
+func (m *Manifests) Manifest(file, lang string) string {
+	switch lang {
+	case "go":
+		return path.Join(m.dir(file, lang), "go.mod")
+	case "js":
+		return path.Join(m.dir(file, lang), "package.json")
+	}
+	return ""
+}
+
+// dir returns the slash-separated directory of the manifest a lang file
+// falls under.
+func (m *Manifests) dir(file, lang string) string {
+	switch lang {
+	case "go":
+		if mod, ok := config.ModuleOf(m.layout.Modules, file); ok {
+			return filepath.ToSlash(mod.Dir)
+		}
+	case "js":
+		if pkg, ok := config.PackageOf(m.layout.Packages, file); ok {
+			return filepath.ToSlash(pkg.Dir)
+		}
+	}
+	return "."
+}
+
+// Root maps an import to the name it would be declared under: a JS
+// package root ("@scope/pkg/sub" → "@scope/pkg", "lodash/fp" → "lodash")
+// or the Go module providing the package. Go modules are matched by
+// longest prefix against go.mod and go.sum; an import from neither is
+// assumed to be the first three path elements on a known code host, or
+// the whole path otherwise. Other languages return spec unchanged. A nil
+// Manifests knows no modules.
+func (m *Manifests) Root(lang, spec string) string {
+	switch lang {
+	case "js":
+		return jsPackage(spec)
+	case "go":
+		best := ""
+		var modules map[string]bool
+		if m != nil {
+			modules = m.modules
+		}
+		for mod := range modules {
+			if (spec == mod || strings.HasPrefix(spec, mod+"/")) && len(mod) > len(best) {
+				best = mod
+			}
+		}
+		if best != "" {
+			return best
+		}
+		return goModuleGuess(spec)
+	}
+	return spec
+}
+
+// jsPackage strips the subpath from a bare JS specifier.
+func jsPackage(spec string) string {
+	parts := strings.SplitN(spec, "/", 3)
+	if strings.HasPrefix(spec, "@") && len(parts) > 1 {
+		return parts[0] + "/" + parts[1]
+	}
+	return parts[0]
+}
+
+// goModuleGuess guesses the module of an import path no manifest knows.
+func goModuleGuess(spec string) string {
+	parts := strings.Split(spec, "/")
+	switch parts[0] {
+	case "github.com", "gitlab.com", "bitbucket.org":
+		if len(parts) > 3 {
+			return strings.Join(parts[:3], "/")
+		}
+	}
+	return spec
+}
+
+// missing reports whether err is a manifest that isn't there.
+func missing(err error) bool {
+	return errors.Is(err, fs.ErrNotExist)
+}
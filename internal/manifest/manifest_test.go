@@ -0,0 +1,401 @@
+package manifest_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jtoloui/depviz/internal/config"
+	"github.com/jtoloui/depviz/internal/manifest"
+)
+
+func writeFiles(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestLoad_Go(t *testing.T) {
+	t.Parallel()
+	dir := writeFiles(t, map[string]string{
+		"go.mod": `module example.com/app
+
+go 1.25
+
+require (
+	github.com/spf13/cobra v1.8.0
+	golang.org/x/mod v0.40.0 // indirect
+	example.com/forked v1.0.0
+	example.com/local v0.1.0
+)
+
+replace example.com/forked => example.com/fork v1.0.1
+
+replace example.com/local => ../local
+
+exclude github.com/spf13/cobra v1.7.0
+`,
+		"go.sum": `github.com/spf13/cobra v1.8.0 h1:abc=
+github.com/spf13/cobra v1.8.0/go.mod h1:def=
+golang.org/x/mod v0.40.0/go.mod h1:ghi=
+example.com/fork v1.0.1 h1:jkl=
+github.com/inconshreveable/mousetrap v1.1.0/go.mod h1:mno=
+`,
+	})
+
+	m, err := manifest.Load(dir, config.Layout{})
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	want := []manifest.Dependency{
+		{Name: "example.com/forked", Lang: "go", Kind: manifest.Direct, Version: "v1.0.0", Locked: "v1.0.1", Replace: "example.com/fork@v1.0.1", Manifest: "go.mod"},
+		{Name: "example.com/local", Lang: "go", Kind: manifest.Direct, Version: "v0.1.0", Replace: "../local", Manifest: "go.mod"},
+		{Name: "github.com/spf13/cobra", Lang: "go", Kind: manifest.Direct, Version: "v1.8.0", Locked: "v1.8.0", Manifest: "go.mod"},
+		{Name: "golang.org/x/mod", Lang: "go", Kind: manifest.Indirect, Version: "v0.40.0", Manifest: "go.mod"},
+	}
+	if len(m.Deps) != len(want) {
+		t.Fatalf("got %d deps, want %d: %+v", len(m.Deps), len(want), m.Deps)
+	}
+	for i := range want {
+		if m.Deps[i] != want[i] {
+			t.Errorf("dep %d = %+v, want %+v", i, m.Deps[i], want[i])
+		}
+	}
+	if !m.Covers("go") || m.Covers("js") {
+		t.Errorf("Covers go/js = %v/%v, want true/false", m.Covers("go"), m.Covers("js"))
+	}
+	if len(m.Excluded) != 1 || m.Excluded[0] != "github.com/spf13/cobra@v1.7.0" {
+		t.Errorf("Excluded = %v", m.Excluded)
+	}
+
+	tests := []struct {
+		spec, root, dep string
+	}{
+		{"github.com/spf13/cobra/doc", "github.com/spf13/cobra", "github.com/spf13/cobra"},
+		{"golang.org/x/mod/modfile", "golang.org/x/mod", "golang.org/x/mod"},
+		{"github.com/inconshreveable/mousetrap", "github.com/inconshreveable/mousetrap", ""},
+		{"github.com/spf13/pflag/internal", "github.com/spf13/pflag", ""},
+		{"gopkg.in/yaml.v3", "gopkg.in/yaml.v3", ""},
+	}
+	for _, tt := range tests {
+		if got := m.Root("go", tt.spec); got != tt.root {
+			t.Errorf("Root(%q) = %q, want %q", tt.spec, got, tt.root)
+		}
+		d, _ := m.Lookup("main.go", "go", tt.spec)
+		if d.Name != tt.dep {
+			t.Errorf("Lookup(%q) = %q, want %q", tt.spec, d.Name, tt.dep)
+		}
+	}
+}
+
+func TestLoad_GoModules(t *testing.T) {
+	t.Parallel()
+	dir := writeFiles(t, map[string]string{
+		"go.work":    "go 1.25\n\nuse (\n\t./api\n\t./lib\n)\n",
+		"api/go.mod": "module example.com/api\n\nrequire github.com/spf13/cobra v1.8.0\n",
+		"api/go.sum": "github.com/spf13/cobra v1.8.0 h1:abc=\n",
+		"lib/go.mod": "module example.com/lib\n\nrequire (\n\tgithub.com/spf13/cobra v1.7.0\n\tgopkg.in/yaml.v3 v3.0.1\n)\n",
+	})
+	layout, err := config.LoadLayout(dir, "go")
+	if err != nil {
+		t.Fatalf("LoadLayout: %v", err)
+	}
+
+	m, err := manifest.Load(dir, layout)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	want := []manifest.Dependency{
+		{Name: "github.com/spf13/cobra", Lang: "go", Kind: manifest.Direct, Version: "v1.8.0", Locked: "v1.8.0", Manifest: "api/go.mod"},
+		{Name: "github.com/spf13/cobra", Lang: "go", Kind: manifest.Direct, Version: "v1.7.0", Manifest: "lib/go.mod"},
+		{Name: "gopkg.in/yaml.v3", Lang: "go", Kind: manifest.Direct, Version: "v3.0.1", Manifest: "lib/go.mod"},
+	}
+	if len(m.Deps) != len(want) {
+		t.Fatalf("got %d deps, want %d: %+v", len(m.Deps), len(want), m.Deps)
+	}
+	for i := range want {
+		if m.Deps[i] != want[i] {
+			t.Errorf("dep %d = %+v, want %+v", i, m.Deps[i], want[i])
+		}
+	}
+
+	tests := []struct {
+		file, spec, manifest string
+	}{
+		{"api/cmd/main.go", "github.com/spf13/cobra", "api/go.mod"},
+		{"lib/util/util.go", "github.com/spf13/cobra/doc", "lib/go.mod"},
+		{"lib/util/util.go", "gopkg.in/yaml.v3", "lib/go.mod"},
+		{"api/cmd/main.go", "gopkg.in/yaml.v3", ""},
+	}
+	for _, tt := range tests {
+		d, _ := m.Lookup(tt.file, "go", tt.spec)
+		if d.Manifest != tt.manifest {
+			t.Errorf("Lookup(%s, %q) from %q, want %q", tt.file, tt.spec, d.Manifest, tt.manifest)
+		}
+	}
+	if got := m.Manifest("api/cmd/main.go", "go"); got != "api/go.mod" {
+		t.Errorf("Manifest = %q, want api/go.mod", got)
+	}
+}
+
+func TestLoad_JS(t *testing.T) {
+	t.Parallel()
+	pkg := `{
+  "dependencies": {"react": "^18.2.0", "@scope/ui": "~1.0.0"},
+  "peerDependencies": {"react-dom": "^18.0.0"},
+  "devDependencies": {"react-dom": "^18.2.0", "vitest": "^1.0.0"}
+}`
+	tests := []struct {
+		name string
+		lock map[string]string
+	}{
+		{"package-lock v3", map[string]string{"package-lock.json": `{
+  "lockfileVersion": 3,
+  "packages": {
+    "": {"name": "app"},
+    "node_modules/react": {"version": "18.2.0"},
+    "node_modules/@scope/ui": {"version": "1.0.4"},
+    "node_modules/react-dom": {"version": "18.2.0"},
+    "node_modules/vitest": {"version": "1.6.0"},
+    "node_modules/vitest/node_modules/react": {"version": "17.0.0"}
+  }
+}`}},
+		{"package-lock v1", map[string]string{"package-lock.json": `{
+  "lockfileVersion": 1,
+  "dependencies": {
+    "react": {"version": "18.2.0"},
+    "@scope/ui": {"version": "1.0.4"},
+    "react-dom": {"version": "18.2.0"},
+    "vitest": {"version": "1.6.0"}
+  }
+}`}},
+		{"yarn classic", map[string]string{"yarn.lock": `# yarn lockfile v1
+
+react@^17.0.0:
+  version "17.0.2"
+
+react@^18.2.0:
+  version "18.2.0"
+
+"@scope/ui@~1.0.0":
+  version "1.0.4"
+
+react-dom@^18.0.0, react-dom@^18.2.0:
+  version "18.2.0"
+
+vitest@^1.0.0:
+  version "1.6.0"
+`}},
+		{"yarn berry", map[string]string{"yarn.lock": `__metadata:
+  version: 6
+
+"react@npm:^18.2.0":
+  version: 18.2.0
+  resolution: "react@npm:18.2.0"
+
+"@scope/ui@npm:~1.0.0":
+  version: 1.0.4
+
+"react-dom@npm:^18.0.0, react-dom@npm:^18.2.0":
+  version: 18.2.0
+
+"vitest@npm:^1.0.0":
+  version: 1.6.0
+`}},
+		{"pnpm v9", map[string]string{"pnpm-lock.yaml": `lockfileVersion: '9.0'
+importers:
+  .:
+    dependencies:
+      react:
+        specifier: ^18.2.0
+        version: 18.2.0
+      '@scope/ui':
+        specifier: ~1.0.0
+        version: 1.0.4(react@18.2.0)
+    devDependencies:
+      react-dom:
+        specifier: ^18.2.0
+        version: 18.2.0(react@18.2.0)
+      vitest:
+        specifier: ^1.0.0
+        version: 1.6.0
+`}},
+		{"pnpm v5", map[string]string{"pnpm-lock.yaml": `lockfileVersion: 5.4
+dependencies:
+  react: 18.2.0
+  '@scope/ui': 1.0.4_react@18.2.0
+devDependencies:
+  react-dom: 18.2.0_react@18.2.0
+  vitest: 1.6.0
+`}},
+	}
+	want := []manifest.Dependency{
+		{Name: "@scope/ui", Lang: "js", Kind: manifest.Direct, Version: "~1.0.0", Locked: "1.0.4", Manifest: "package.json"},
+		{Name: "react", Lang: "js", Kind: manifest.Direct, Version: "^18.2.0", Locked: "18.2.0", Manifest: "package.json"},
+		{Name: "react-dom", Lang: "js", Kind: manifest.Peer, Version: "^18.0.0", Locked: "18.2.0", Manifest: "package.json"},
+		{Name: "vitest", Lang: "js", Kind: manifest.Dev, Version: "^1.0.0", Locked: "1.6.0", Manifest: "package.json"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			tt.lock["package.json"] = pkg
+			m, err := manifest.Load(writeFiles(t, tt.lock), config.Layout{})
+			if err != nil {
+				t.Fatalf("Load: %v", err)
+			}
+			if len(m.Deps) != len(want) {
+				t.Fatalf("got %d deps, want %d: %+v", len(m.Deps), len(want), m.Deps)
+			}
+			for i := range want {
+				if m.Deps[i] != want[i] {
+					t.Errorf("dep %d = %+v, want %+v", i, m.Deps[i], want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestLoad_JSWorkspace(t *testing.T) {
+	t.Parallel()
+	pkgs := map[string]string{
+		"package.json":              `{"workspaces": ["packages/*"], "devDependencies": {"typescript": "^5"}}`,
+		"packages/ui/package.json":  `{"name": "@acme/ui", "dependencies": {"react": "^18"}}`,
+		"packages/app/package.json": `{"name": "app", "dependencies": {"lodash": "^4"}}`,
+	}
+	tests := []struct {
+		name string
+		lock map[string]string
+	}{
+		{"npm", map[string]string{"package-lock.json": `{"packages": {
+			"node_modules/typescript": {"version": "5.4.0"},
+			"node_modules/react": {"version": "18.3.1"},
+			"node_modules/lodash": {"version": "4.17.21"},
+			"packages/app/node_modules/lodash": {"version": "4.17.20"},
+			"node_modules/react/node_modules/loose-envify": {"version": "1.4.0"}
+		}}`}},
+		{"pnpm", map[string]string{"pnpm-lock.yaml": `lockfileVersion: '9.0'
+importers:
+  .:
+    devDependencies:
+      typescript: {specifier: ^5, version: 5.4.0}
+  packages/ui:
+    dependencies:
+      react: {specifier: ^18, version: 18.3.1}
+  packages/app:
+    dependencies:
+      lodash: {specifier: ^4, version: 4.17.20}
+`}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			files := map[string]string{}
+			for k, v := range pkgs {
+				files[k] = v
+			}
+			for k, v := range tt.lock {
+				files[k] = v
+			}
+			dir := writeFiles(t, files)
+			layout, err := config.LoadLayout(dir, "js")
+			if err != nil {
+				t.Fatalf("LoadLayout: %v", err)
+			}
+			m, err := manifest.Load(dir, layout)
+			if err != nil {
+				t.Fatalf("Load: %v", err)
+			}
+			want := []manifest.Dependency{
+				{Name: "lodash", Lang: "js", Kind: manifest.Direct, Version: "^4", Locked: "4.17.20", Manifest: "packages/app/package.json"},
+				{Name: "react", Lang: "js", Kind: manifest.Direct, Version: "^18", Locked: "18.3.1", Manifest: "packages/ui/package.json"},
+				{Name: "typescript", Lang: "js", Kind: manifest.Dev, Version: "^5", Locked: "5.4.0", Manifest: "package.json"},
+			}
+			if len(m.Deps) != len(want) {
+				t.Fatalf("got %d deps, want %d: %+v", len(m.Deps), len(want), m.Deps)
+			}
+			for i := range want {
+				if m.Deps[i] != want[i] {
+					t.Errorf("dep %d = %+v, want %+v", i, m.Deps[i], want[i])
+				}
+			}
+
+			lookups := []struct {
+				file, spec, manifest string
+			}{
+				{"packages/ui/src/button.tsx", "react", "packages/ui/package.json"},
+				{"packages/app/src/main.ts", "lodash/fp", "packages/app/package.json"},
+				{"packages/app/src/main.ts", "react", ""},
+				{"packages/app/src/main.ts", "typescript", "package.json"},
+				{"scripts/build.ts", "lodash", ""},
+			}
+			for _, l := range lookups {
+				d, _ := m.Lookup(l.file, "js", l.spec)
+				if d.Manifest != l.manifest {
+					t.Errorf("Lookup(%s, %q) from %q, want %q", l.file, l.spec, d.Manifest, l.manifest)
+				}
+			}
+		})
+	}
+}
+
+func TestLookup_JS(t *testing.T) {
+	t.Parallel()
+	m, err := manifest.Load(writeFiles(t, map[string]string{
+		"package.json": `{"dependencies": {"lodash": "^4.0.0", "@scope/ui": "1.0.0"}}`,
+	}), config.Layout{})
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	tests := []struct {
+		spec, want string
+	}{
+		{"lodash", "lodash"},
+		{"lodash/fp", "lodash"},
+		{"@scope/ui/button", "@scope/ui"},
+		{"@scope/other", ""},
+		{"react", ""},
+	}
+	for _, tt := range tests {
+		d, _ := m.Lookup("index.ts", "js", tt.spec)
+		if d.Name != tt.want {
+			t.Errorf("Lookup(%q) = %q, want %q", tt.spec, d.Name, tt.want)
+		}
+	}
+	if _, ok := m.Lookup("main.go", "go", "lodash"); ok {
+		t.Error("Lookup matched across languages")
+	}
+}
+
+func TestLoad_Errors(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name  string
+		files map[string]string
+	}{
+		{"bad go.mod", map[string]string{"go.mod": "module\n"}},
+		{"bad package.json", map[string]string{"package.json": "{"}},
+		{"bad lockfile", map[string]string{"package.json": "{}", "package-lock.json": "["}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if _, err := manifest.Load(writeFiles(t, tt.files), config.Layout{}); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+
+	m, err := manifest.Load(t.TempDir(), config.Layout{})
+	if err != nil || len(m.Deps) != 0 || m.Covers("go") {
+		t.Errorf("empty dir: deps %v, err %v", m, err)
+	}
+}
@@ -0,0 +1,246 @@
+package manifest
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+type packageJSON struct {
+	Dependencies     map[string]string `json:"dependencies"`
+	DevDependencies  map[string]string `json:"devDependencies"`
+	PeerDependencies map[string]string `json:"peerDependencies"`
+}
+
+// loadJS reads the package.json in dir, relative to root, and pins each
+// dependency to the version the lockfile resolved for that package. A
+// package declared twice keeps the first of dependencies, peerDependencies,
+// devDependencies: a peer that is also a dev dependency is installed for
+// development but provided by the consumer.
+func (m *Manifests) loadJS(root, dir string, locked lockVersions) error {
+	file := path.Join(dir, "package.json")
+	data, err := os.ReadFile(filepath.Join(root, filepath.FromSlash(file)))
+	if missing(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	var pkg packageJSON
+	if err := json.Unmarshal(data, &pkg); err != nil {
+		return fmt.Errorf("parsing %s: %w", file, err)
+	}
+	m.langs["js"] = true
+
+	for _, deps := range []struct {
+		kind Kind
+		list map[string]string
+	}{{Direct, pkg.Dependencies}, {Peer, pkg.PeerDependencies}, {Dev, pkg.DevDependencies}} {
+		for name, version := range deps.list {
+			m.add(Dependency{Name: name, Lang: "js", Kind: deps.kind, Version: version, Locked: locked.lookup(dir, name, version), Manifest: file})
+		}
+	}
+	return nil
+}
+
+// lockVersions maps a package directory ("." for the root, or a workspace
+// package) to the name and name@range specifiers the lockfile resolved for
+// it.
+type lockVersions map[string]map[string]string
+
+// set records the version spec resolved to in dir.
+func (l lockVersions) set(dir, spec, version string) {
+	if l[dir] == nil {
+		l[dir] = map[string]string{}
+	}
+	l[dir][spec] = version
+}
+
+// lookup returns the version a dependency declared in dir resolved to,
+// falling back to what the root resolved: workspace packages share the
+// dependencies hoisted there.
+func (l lockVersions) lookup(dir, name, version string) string {
+	for _, d := range []string{dir, "."} {
+		if v, ok := l[d][name+"@"+version]; ok {
+			return v
+		}
+		if v, ok := l[d][name]; ok {
+			return v
+		}
+	}
+	return ""
+}
+
+// jsLocked reads the first lockfile found in root.
+func jsLocked(root string) (lockVersions, error) {
+	for _, lock := range []struct {
+		file  string
+		parse func([]byte) (lockVersions, error)
+	}{
+		{"package-lock.json", parseNPMLock},
+		{"pnpm-lock.yaml", parsePNPMLock},
+		{"yarn.lock", parseYarnLock},
+	} {
+		data, err := os.ReadFile(filepath.Join(root, lock.file))
+		if missing(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		versions, err := lock.parse(data)
+		if err != nil {
+			return nil, fmt.Errorf("parsing %s: %w", lock.file, err)
+		}
+		return versions, nil
+	}
+	return lockVersions{}, nil
+}
+
+// parseNPMLock reads package-lock.json: the "packages" map of lockfile v2
+// and v3, or the "dependencies" map of v1.
+func parseNPMLock(data []byte) (lockVersions, error) {
+	var lock struct {
+		Packages map[string]struct {
+			Version string `json:"version"`
+		} `json:"packages"`
+		Dependencies map[string]struct {
+			Version string `json:"version"`
+		} `json:"dependencies"`
+	}
+	if err := json.Unmarshal(data, &lock); err != nil {
+		return nil, err
+	}
+	versions := lockVersions{}
+	for p, pkg := range lock.Packages {
+		// node_modules/x is installed for the root (and hoisted for every
+		// workspace package), packages/ui/node_modules/x for packages/ui
+		// alone. Nested node_modules are transitive copies; only these
+		// satisfy the project's own imports.
+		i := strings.Index("/"+p, "/node_modules/")
+		if i < 0 {
+			continue
+		}
+		dir, name := ".", p[i+len("node_modules/"):]
+		if i > 0 {
+			dir = p[:i-1]
+		}
+		if !strings.Contains(name, "/node_modules/") {
+			versions.set(dir, name, pkg.Version)
+		}
+	}
+	for name, d := range lock.Dependencies {
+		if _, ok := versions["."][name]; !ok {
+			versions.set(".", name, d.Version)
+		}
+	}
+	return versions, nil
+}
+
+type pnpmImporter struct {
+	Dependencies         map[string]any `yaml:"dependencies"`
+	DevDependencies      map[string]any `yaml:"devDependencies"`
+	OptionalDependencies map[string]any `yaml:"optionalDependencies"`
+}
+
+// parsePNPMLock reads pnpm-lock.yaml: every importer of a workspace
+// lockfile, or the top-level dependencies of a single-package one. Entries
+// are a version (v5) or {specifier, version} (v6 and later).
+func parsePNPMLock(data []byte) (lockVersions, error) {
+	var lock struct {
+		Importers    map[string]pnpmImporter `yaml:"importers"`
+		pnpmImporter `yaml:",inline"`
+	}
+	if err := yaml.Unmarshal(data, &lock); err != nil {
+		return nil, err
+	}
+	importers := lock.Importers
+	if len(importers) == 0 {
+		importers = map[string]pnpmImporter{".": lock.pnpmImporter}
+	}
+	versions := lockVersions{}
+	for dir, imp := range importers {
+		for _, deps := range []map[string]any{imp.Dependencies, imp.DevDependencies, imp.OptionalDependencies} {
+			for name, entry := range deps {
+				var v string
+				switch e := entry.(type) {
+				case string:
+					v = e
+				case map[string]any:
+					v, _ = e["version"].(string)
+				default:
+					v = fmt.Sprint(e)
+				}
+				versions.set(path.Clean(dir), name, pnpmVersion(v))
+			}
+		}
+	}
+	return versions, nil
+}
+
+// pnpmVersion drops the peer-dependency suffix pnpm appends to a resolved
+// version: "18.2.0(react@18.2.0)" in v6+, "18.2.0_react@18.2.0" in v5.
+func pnpmVersion(v string) string {
+	if i := strings.IndexAny(v, "(_"); i > 0 {
+		return v[:i]
+	}
+	return v
+}
+
+// parseYarnLock reads yarn.lock, classic (v1) or Berry. Every block header
+// lists the name@range specifiers it resolves, e.g.
+//
+//	"@babel/core@^7.0.0", "@babel/core@npm:^7.1.0":
+//	  version "7.2.0"
+//
+// Berry writes `version: 7.2.0` and prefixes ranges with a protocol. The
+// lockfile doesn't say which workspace package asked for what, so every
+// specifier is recorded for the root.
+func parseYarnLock(data []byte) (lockVersions, error) {
+	versions := map[string]string{}
+	var specs []string
+	s := bufio.NewScanner(strings.NewReader(string(data)))
+	for s.Scan() {
+		line := s.Text()
+		switch {
+		case line == "" || strings.HasPrefix(line, "#"):
+		case !strings.HasPrefix(line, " "):
+			specs = strings.Split(strings.TrimSuffix(line, ":"), ",")
+		case strings.HasPrefix(line, "  version"):
+			v := strings.TrimPrefix(line, "  version")
+			v = strings.Trim(strings.TrimPrefix(v, ":"), ` "`)
+			for _, spec := range specs {
+				name, rng := splitYarnSpec(strings.Trim(spec, ` "`))
+				if name == "" {
+					continue
+				}
+				versions[name+"@"+rng] = v
+				versions[name+"@"+strings.TrimPrefix(rng, "npm:")] = v
+				if _, ok := versions[name]; !ok {
+					versions[name] = v
+				}
+			}
+			specs = nil
+		}
+	}
+	return lockVersions{".": versions}, s.Err()
+}
+
+// splitYarnSpec splits "@scope/pkg@^1.0.0" into name and range. The range
+// may itself contain "@", as in "pkg@patch:pkg@npm%3A1.0.0".
+func splitYarnSpec(spec string) (name, rng string) {
+	if len(spec) < 2 {
+		return "", ""
+	}
+	i := strings.Index(spec[1:], "@") + 1
+	if i == 0 {
+		return "", ""
+	}
+	return spec[:i], spec[i+1:]
+}
@@ -0,0 +1,137 @@
+// Package metrics computes Robert C. Martin's package coupling metrics from
+// the resolved dependency graph.
+package metrics
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/jtoloui/depviz/internal/graph"
+	"github.com/jtoloui/depviz/internal/group"
+	"github.com/jtoloui/depviz/internal/scanner"
+)
+
+// Package holds the metrics for one package.
+type Package struct {
+	Name  string `json:"name"`
+	Files int    `json:"files"`
+	// Ca (afferent coupling) counts the other packages that import this one.
+	Ca int `json:"ca"`
+	// Ce (efferent coupling) counts the other packages this one imports.
+	Ce int `json:"ce"`
+	// Exports and Abstract count its public exports, and how many of them
+	// are interfaces or type-only declarations.
+	Exports  int `json:"exports"`
+	Abstract int `json:"abstract"`
+	// Instability is Ce / (Ca + Ce): 0 is maximally stable, 1 maximally
+	// unstable.
+	Instability float64 `json:"instability"`
+	// Abstractness is Abstract / Exports, 0 when there are no exports.
+	Abstractness float64 `json:"abstractness"`
+	// Distance is |A + I - 1|, how far the package sits from the main
+	// sequence where abstractness balances stability.
+	Distance float64 `json:"distance"`
+}
+
+// Compute returns metrics for every package in results that imports or is
+// imported by another package, sorted by name. Packages are grouped as by
+// `--group-by package`; only the edges of g count as coupling, so g should
+// hold just the imports that are internal to the project.
+func Compute(results []scanner.FileImports, g *graph.Graph) []Package {
+	names := group.Groups(results, group.ByPackage)
+
+	pkgs := map[string]*Package{}
+	for _, fi := range results {
+		n := names[fi.File]
+		p, ok := pkgs[n]
+		if !ok {
+			p = &Package{Name: n}
+			pkgs[n] = p
+		}
+		p.Files++
+		for _, e := range fi.Exports {
+			if e.Private || e.Kind == scanner.ExportReExport {
+				continue
+			}
+			p.Exports++
+			if abstract(e, fi.Lang) {
+				p.Abstract++
+			}
+		}
+	}
+
+	out := map[string]map[string]bool{}
+	in := map[string]map[string]bool{}
+	for _, e := range g.Edges() {
+		from, to := names[e.From], names[e.To]
+		if from == to || from == "" || to == "" {
+			continue
+		}
+		if out[from] == nil {
+			out[from] = map[string]bool{}
+		}
+		if in[to] == nil {
+			in[to] = map[string]bool{}
+		}
+		out[from][to] = true
+		in[to][from] = true
+	}
+
+	var list []Package
+	for n, p := range pkgs {
+		p.Ca, p.Ce = len(in[n]), len(out[n])
+		if p.Ca+p.Ce == 0 {
+			continue
+		}
+		p.Instability = float64(p.Ce) / float64(p.Ca+p.Ce)
+		if p.Exports > 0 {
+			p.Abstractness = float64(p.Abstract) / float64(p.Exports)
+		}
+		p.Distance = math.Abs(p.Abstractness + p.Instability - 1)
+		list = append(list, *p)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list
+}
+
+// abstract reports whether e declares no implementation: an interface
+// (Go, TS, Java/Kotlin, Rust trait) or a TS type alias.
+func abstract(e scanner.ExportDetail, lang string) bool {
+	return e.Kind == scanner.ExportInterface || (e.Kind == scanner.ExportType && lang == "js")
+}
+
+// SortKeys lists the columns Sort accepts.
+var SortKeys = []string{"name", "files", "ca", "ce", "instability", "abstractness", "distance"}
+
+// Sort orders pkgs by key: name ascending, anything else descending, with
+// ties broken by name.
+func Sort(pkgs []Package, key string) error {
+	var val func(p Package) float64
+	switch key {
+	case "name":
+		sort.SliceStable(pkgs, func(i, j int) bool { return pkgs[i].Name < pkgs[j].Name })
+		return nil
+	case "files":
+		val = func(p Package) float64 { return float64(p.Files) }
+	case "ca":
+		val = func(p Package) float64 { return float64(p.Ca) }
+	case "ce":
+		val = func(p Package) float64 { return float64(p.Ce) }
+	case "instability":
+		val = func(p Package) float64 { return p.Instability }
+	case "abstractness":
+		val = func(p Package) float64 { return p.Abstractness }
+	case "distance":
+		val = func(p Package) float64 { return p.Distance }
+	default:
+		return fmt.Errorf("unsupported sort key %q: want one of %v", key, SortKeys)
+	}
+	sort.SliceStable(pkgs, func(i, j int) bool {
+		if a, b := val(pkgs[i]), val(pkgs[j]); a != b {
+			return a > b
+		}
+		return pkgs[i].Name < pkgs[j].Name
+	})
+	return nil
+}
@@ -0,0 +1,101 @@
+package metrics_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/jtoloui/depviz/internal/config"
+	"github.com/jtoloui/depviz/internal/graph"
+	"github.com/jtoloui/depviz/internal/metrics"
+	"github.com/jtoloui/depviz/internal/scanner"
+)
+
+func compute(t *testing.T) []metrics.Package {
+	t.Helper()
+	results := []scanner.FileImports{
+		{File: "app/main.ts", Lang: "js", Imports: []string{"../ui/button", "../api/client", "react"}},
+		{File: "ui/button.ts", Lang: "js", Imports: []string{"../api/types"}, Exports: []scanner.ExportDetail{
+			{Name: "Button", Kind: scanner.ExportFunction},
+			{Name: "Props", Kind: scanner.ExportInterface},
+		}},
+		{File: "api/client.ts", Lang: "js", Imports: []string{"./types"}, Exports: []scanner.ExportDetail{
+			{Name: "get", Kind: scanner.ExportFunction},
+			{Name: "* from './types'", Kind: scanner.ExportReExport},
+		}},
+		{File: "api/types.ts", Lang: "js", Exports: []scanner.ExportDetail{
+			{Name: "Client", Kind: scanner.ExportInterface},
+			{Name: "ID", Kind: scanner.ExportType},
+		}},
+		{File: "scripts/seed.ts", Lang: "js", Imports: []string{"fs"}},
+	}
+	g, err := graph.Build(t.TempDir(), config.Layout{}, results)
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+	return metrics.Compute(results, g)
+}
+
+func TestCompute(t *testing.T) {
+	t.Parallel()
+
+	got := compute(t)
+	want := []metrics.Package{
+		// 2 of 3 public exports are abstract; the re-export doesn't count.
+		{Name: "api", Files: 2, Ca: 2, Ce: 0, Exports: 3, Abstract: 2, Instability: 0, Abstractness: 2.0 / 3, Distance: 1.0 / 3},
+		{Name: "app", Files: 1, Ca: 0, Ce: 2, Instability: 1, Distance: 0},
+		{Name: "ui", Files: 1, Ca: 1, Ce: 1, Exports: 2, Abstract: 1, Instability: 0.5, Abstractness: 0.5, Distance: 0},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %d packages, want %d (scripts has no coupling): %+v", len(got), len(want), got)
+	}
+	for i, w := range want {
+		g := got[i]
+		if g.Name != w.Name || g.Files != w.Files || g.Ca != w.Ca || g.Ce != w.Ce || g.Exports != w.Exports || g.Abstract != w.Abstract {
+			t.Errorf("[%d] = %+v, want %+v", i, g, w)
+		}
+		for _, f := range []struct {
+			name      string
+			got, want float64
+		}{
+			{"instability", g.Instability, w.Instability},
+			{"abstractness", g.Abstractness, w.Abstractness},
+			{"distance", g.Distance, w.Distance},
+		} {
+			if math.Abs(f.got-f.want) > 1e-9 {
+				t.Errorf("%s %s = %v, want %v", w.Name, f.name, f.got, f.want)
+			}
+		}
+	}
+}
+
+func TestSort(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		key  string
+		want []string
+	}{
+		{"name", []string{"api", "app", "ui"}},
+		{"ca", []string{"api", "ui", "app"}},
+		{"instability", []string{"app", "ui", "api"}},
+		{"distance", []string{"api", "app", "ui"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			t.Parallel()
+			pkgs := compute(t)
+			if err := metrics.Sort(pkgs, tt.key); err != nil {
+				t.Fatal(err)
+			}
+			for i, name := range tt.want {
+				if pkgs[i].Name != name {
+					t.Errorf("sort %s: [%d] = %s, want %s", tt.key, i, pkgs[i].Name, name)
+				}
+			}
+		})
+	}
+
+	if err := metrics.Sort(nil, "size"); err == nil {
+		t.Error("expected error for unknown sort key")
+	}
+}
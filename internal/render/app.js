@@ -1,84 +1,188 @@
-// Build reverse index: import name → [files]
-const reverseIndex = {};
-data.forEach(f => f.imports.forEach(i => {
-  (reverseIndex[i.name] ??= []).push(f.file);
-}));
-
-// Build depended-on count per file (how many other files import it)
-const dependedOn = {};
-const fileSet = new Set(data.map(f => f.file));
-data.forEach(f => f.imports.forEach(i => {
-  if (i.category !== 'internal') return;
-  const norm = i.name.replace(/^\.\.?\//, '');
-  for (const file of fileSet) {
-    if (file.includes(norm)) { dependedOn[file] = (dependedOn[file] || 0) + 1; break; }
-  }
-}));
-
-// Category counts
-const catCounts = { stdlib: 0, internal: 0, private: 0, external: 0 };
-data.forEach(f => f.imports.forEach(i => catCounts[i.category]++));
-Object.keys(catCounts).forEach(c => {
-  const el = document.getElementById('count-' + c);
-  if (el) el.textContent = catCounts[c];
-});
+// Derived indexes, rebuilt whenever data changes (see refresh)
+let reverseIndex, importedBy, dependedOn, snippetIndex;
+function indexData() {
+  // Reverse index: import name → [files]
+  reverseIndex = {};
+  data.forEach(f => f.imports.forEach(i => {
+    (reverseIndex[i.name] ??= []).push(f.file);
+  }));
+
+  // Importers per file, from the files each internal import resolves to
+  importedBy = {};
+  data.forEach(f => f.imports.forEach(i => {
+    if (i.category !== 'internal') return;
+    (i.resolved || []).forEach(t => { if (t !== f.file) (importedBy[t] ??= new Set()).add(f.file); });
+  }));
+
+  // Depended-on count per file (how many other files import it)
+  dependedOn = {};
+  Object.keys(importedBy).forEach(t => { dependedOn[t] = importedBy[t].size; });
+
+  // Snippet lookup: "file::importName" → {snippet, kind, line}
+  snippetIndex = {};
+  data.forEach(f => f.imports.forEach(i => {
+    if (i.snippet) snippetIndex[f.file + '::' + i.name] = { snippet: i.snippet, kind: i.kind, line: i.line };
+  }));
+}
 
-// Stats
-const allNames = data.flatMap(f => f.imports.map(i => i.name));
-const totalExports = data.reduce((n, f) => n + (f.exports || []).length, 0);
-const avg = data.length ? (allNames.length / data.length).toFixed(1) : 0;
-document.getElementById('stat-files').textContent = data.length + ' files';
-document.getElementById('stat-imports').textContent = new Set(allNames).size + ' unique imports';
-document.getElementById('stat-exports').textContent = totalExports + ' exports';
-document.getElementById('stat-avg').textContent = avg + ' avg imports/file';
-// Line count & language breakdown
-const totalLines = data.reduce((n, f) => n + (f.lines || 0), 0);
-document.getElementById('stat-lines').textContent = totalLines.toLocaleString() + ' total lines';
-const langMap = {};
-data.forEach(f => {
-  const ext = f.file.substring(f.file.lastIndexOf('.'));
-  langMap[ext] = (langMap[ext] || 0) + (f.lines || 0);
-});
-const langs = Object.entries(langMap).sort((a, b) => b[1] - a[1]);
-const langColors = { '.ts': '#3178c6', '.tsx': '#61dafb', '.js': '#f7df1e', '.jsx': '#61dafb', '.mjs': '#f7df1e', '.go': '#00add8', '.css': '#563d7c', '.scss': '#c6538c', '.html': '#e34c26', '.json': '#a8a8a8', '.md': '#555', '.yml': '#cb171e', '.yaml': '#cb171e' };
-const langNames = { '.ts': 'TypeScript', '.tsx': 'TSX', '.js': 'JavaScript', '.jsx': 'JSX', '.mjs': 'JavaScript', '.go': 'Go', '.css': 'CSS', '.scss': 'SCSS', '.html': 'HTML', '.json': 'JSON', '.md': 'Markdown', '.yml': 'YAML', '.yaml': 'YAML' };
-document.getElementById('lang-bar').innerHTML = langs.map(([ext, lines]) => {
-  const pct = (lines / totalLines * 100).toFixed(1);
-  const col = langColors[ext] || '#8b949e';
-  return '<span style="width:' + pct + '%;background:' + col + '" title="' + (langNames[ext] || ext) + ' ' + pct + '%"></span>';
-}).join('');
-document.getElementById('lang-legend').innerHTML = langs.map(([ext, lines]) => {
-  const pct = (lines / totalLines * 100).toFixed(0);
-  const col = langColors[ext] || '#8b949e';
-  return '<span style="color:' + col + '">' + (langNames[ext] || ext) + ' ' + pct + '%</span>';
-}).join('');
-// Top 5 most imported
-const freq = {};
-allNames.forEach(n => freq[n] = (freq[n] || 0) + 1);
-const top5 = Object.entries(freq).sort((a, b) => b[1] - a[1]).slice(0, 5);
-document.getElementById('top-imports').innerHTML = top5.map(([name, count]) =>
-  '<li title="' + name + '"><span>' + name + '</span><span class="ti-count">' + count + '</span></li>'
-).join('');
-// Category breakdown bar
-const total = allNames.length || 1;
+const langColors = { '.ts': '#3178c6', '.tsx': '#61dafb', '.js': '#f7df1e', '.jsx': '#61dafb', '.mjs': '#f7df1e', '.vue': '#41b883', '.svelte': '#ff3e00', '.astro': '#ff5d01', '.go': '#00add8', '.py': '#3572a5', '.pyi': '#3572a5', '.rs': '#dea584', '.java': '#b07219', '.kt': '#a97bff', '.kts': '#a97bff', '.css': '#563d7c', '.scss': '#c6538c', '.html': '#e34c26', '.json': '#a8a8a8', '.md': '#555', '.yml': '#cb171e', '.yaml': '#cb171e' };
+const langNames = { '.ts': 'TypeScript', '.tsx': 'TSX', '.js': 'JavaScript', '.jsx': 'JSX', '.mjs': 'JavaScript', '.vue': 'Vue', '.svelte': 'Svelte', '.astro': 'Astro', '.go': 'Go', '.py': 'Python', '.pyi': 'Python', '.rs': 'Rust', '.java': 'Java', '.kt': 'Kotlin', '.kts': 'Kotlin', '.css': 'CSS', '.scss': 'SCSS', '.html': 'HTML', '.json': 'JSON', '.md': 'Markdown', '.yml': 'YAML', '.yaml': 'YAML' };
 const catColors = { stdlib: 'var(--green)', internal: 'var(--purple)', private: 'var(--blue)', external: 'var(--orange)' };
-document.getElementById('cat-bar').innerHTML = ['stdlib','internal','private','external'].map(c => {
-  const pct = (catCounts[c] / total * 100).toFixed(1);
-  return '<span style="width:' + pct + '%;background:' + catColors[c] + '"></span>';
-}).join('');
-document.getElementById('cat-bar-legend').innerHTML = ['stdlib','internal','private','external'].map(c => {
-  const pct = (catCounts[c] / total * 100).toFixed(0);
-  return '<span style="color:' + catColors[c] + '">' + c + ' ' + pct + '%</span>';
-}).join('');
-// God files (10+ imports)
-const godFiles = data.filter(f => f.imports.length >= 10).sort((a, b) => b.imports.length - a.imports.length).slice(0, 5);
-const gfEl = document.getElementById('god-files');
-const gfSection = document.getElementById('god-files-section');
-if (godFiles.length) {
+
+function renderStats() {
+  // Category counts
+  const catCounts = { stdlib: 0, internal: 0, private: 0, external: 0 };
+  data.forEach(f => f.imports.forEach(i => catCounts[i.category]++));
+  Object.keys(catCounts).forEach(c => {
+    const el = document.getElementById('count-' + c);
+    if (el) el.textContent = catCounts[c];
+  });
+
+  // Stats
+  const allNames = data.flatMap(f => f.imports.map(i => i.name));
+  const totalExports = data.reduce((n, f) => n + (f.exports || []).length, 0);
+  const avg = data.length ? (allNames.length / data.length).toFixed(1) : 0;
+  document.getElementById('stat-files').textContent = data.length + ' files';
+  document.getElementById('stat-imports').textContent = new Set(allNames).size + ' unique imports';
+  document.getElementById('stat-exports').textContent = totalExports + ' exports';
+  document.getElementById('stat-avg').textContent = avg + ' avg imports/file';
+  // Line count & language breakdown
+  const totalLines = data.reduce((n, f) => n + (f.lines || 0), 0);
+  document.getElementById('stat-lines').textContent = totalLines.toLocaleString() + ' total lines';
+  const langMap = {};
+  data.forEach(f => {
+    const ext = f.file.substring(f.file.lastIndexOf('.'));
+    langMap[ext] = (langMap[ext] || 0) + (f.lines || 0);
+  });
+  const langs = Object.entries(langMap).sort((a, b) => b[1] - a[1]);
+  document.getElementById('lang-bar').innerHTML = langs.map(([ext, lines]) => {
+    const pct = (lines / totalLines * 100).toFixed(1);
+    const col = langColors[ext] || '#8b949e';
+    return '<span style="width:' + pct + '%;background:' + col + '" title="' + (langNames[ext] || ext) + ' ' + pct + '%"></span>';
+  }).join('');
+  document.getElementById('lang-legend').innerHTML = langs.map(([ext, lines]) => {
+    const pct = (lines / totalLines * 100).toFixed(0);
+    const col = langColors[ext] || '#8b949e';
+    return '<span style="color:' + col + '">' + (langNames[ext] || ext) + ' ' + pct + '%</span>';
+  }).join('');
+  // Top 5 most imported
+  const freq = {};
+  allNames.forEach(n => freq[n] = (freq[n] || 0) + 1);
+  const top5 = Object.entries(freq).sort((a, b) => b[1] - a[1]).slice(0, 5);
+  document.getElementById('top-imports').innerHTML = top5.map(([name, count]) =>
+    '<li title="' + name + '"><span>' + name + '</span><span class="ti-count">' + count + '</span></li>'
+  ).join('');
+  // Category breakdown bar
+  const total = allNames.length || 1;
+  document.getElementById('cat-bar').innerHTML = ['stdlib','internal','private','external'].map(c => {
+    const pct = (catCounts[c] / total * 100).toFixed(1);
+    return '<span style="width:' + pct + '%;background:' + catColors[c] + '"></span>';
+  }).join('');
+  document.getElementById('cat-bar-legend').innerHTML = ['stdlib','internal','private','external'].map(c => {
+    const pct = (catCounts[c] / total * 100).toFixed(0);
+    return '<span style="color:' + catColors[c] + '">' + c + ' ' + pct + '%</span>';
+  }).join('');
+  // God files (10+ imports)
+  const godFiles = data.filter(f => f.imports.length >= 10).sort((a, b) => b.imports.length - a.imports.length).slice(0, 5);
+  const gfEl = document.getElementById('god-files');
+  const gfSection = document.getElementById('god-files-section');
+  const hasGod = godFiles.length > 0;
+  gfSection.style.display = hasGod ? '' : 'none';
+  gfEl.style.display = hasGod ? '' : 'none';
   gfEl.innerHTML = godFiles.map(f =>
     '<li title="' + f.file + '"><span>' + f.file + '</span><span class="ti-count">' + f.imports.length + '</span></li>'
   ).join('');
-} else { gfSection.style.display = 'none'; gfEl.style.display = 'none'; }
+}
+
+// Abstractness (y) vs instability (x) per package, with the main sequence
+// A + I = 1 as the diagonal. Packages far from it are orange.
+function renderMetrics() {
+  const plot = document.getElementById('metrics-plot');
+  const show = metrics.length > 0;
+  document.getElementById('metrics-section').style.display = show ? '' : 'none';
+  plot.style.display = show ? '' : 'none';
+  if (!show) return;
+  const pad = 24, size = 200 - 2 * pad;
+  const x = i => pad + i * size, y = a => pad + (1 - a) * size;
+  plot.innerHTML =
+    '<rect class="mp-frame" x="' + pad + '" y="' + pad + '" width="' + size + '" height="' + size + '"></rect>' +
+    '<line class="mp-sequence" x1="' + x(0) + '" y1="' + y(1) + '" x2="' + x(1) + '" y2="' + y(0) + '"></line>' +
+    '<text class="mp-axis" x="' + (pad + size / 2) + '" y="' + (200 - 6) + '" text-anchor="middle">Instability →</text>' +
+    '<text class="mp-axis" x="10" y="' + (pad + size / 2) + '" text-anchor="middle" transform="rotate(-90 10 ' + (pad + size / 2) + ')">Abstractness →</text>' +
+    '<text class="mp-zone" x="' + (pad + 4) + '" y="' + (pad + size - 4) + '">pain</text>' +
+    '<text class="mp-zone" x="' + (pad + size - 4) + '" y="' + (pad + 10) + '" text-anchor="end">useless</text>' +
+    metrics.map(p =>
+      '<circle class="mp-dot' + (p.distance >= 0.5 ? ' far' : '') + '" data-pkg="' + escHtml(p.name) + '" cx="' + x(p.instability).toFixed(1) + '" cy="' + y(p.abstractness).toFixed(1) + '" r="' + Math.min(3 + p.files, 8) + '">' +
+      '<title>' + escHtml(p.name) + '\nCa ' + p.ca + ' · Ce ' + p.ce + '\nI ' + p.instability.toFixed(2) + ' · A ' + p.abstractness.toFixed(2) + ' · D ' + p.distance.toFixed(2) + '</title></circle>'
+    ).join('');
+}
+document.getElementById('metrics-plot').addEventListener('click', e => {
+  const dot = e.target.closest('.mp-dot');
+  if (!dot) return;
+  const name = dot.dataset.pkg;
+  searchInput.value = name.includes('/') ? name + '/' : name;
+  selectedImport = null;
+  selectedImpact = null;
+  render();
+});
+
+// Platform filter: files scanned with --all-platforms carry their Go build
+// constraint, evaluated here against the chosen GOOS/GOARCH.
+const platformSelect = document.getElementById('platform');
+const knownOS = new Set(['aix', 'android', 'darwin', 'dragonfly', 'freebsd', 'hurd', 'illumos', 'ios', 'js', 'linux', 'nacl', 'netbsd', 'openbsd', 'plan9', 'solaris', 'wasip1', 'windows', 'zos']);
+const knownArch = new Set(['386', 'amd64', 'amd64p32', 'arm', 'armbe', 'arm64', 'arm64be', 'loong64', 'mips', 'mipsle', 'mips64', 'mips64le', 'mips64p32', 'mips64p32le', 'ppc', 'ppc64', 'ppc64le', 'riscv', 'riscv64', 's390', 's390x', 'sparc', 'sparc64', 'wasm']);
+const unixOS = new Set(['aix', 'android', 'darwin', 'dragonfly', 'freebsd', 'hurd', 'illumos', 'ios', 'linux', 'netbsd', 'openbsd', 'solaris']);
+
+// Offers every OS × arch the constraints mention, plus the common ones.
+function renderPlatforms() {
+  const tags = new Set();
+  data.forEach(f => (f.build || '').match(/[\w.]+/g)?.forEach(t => tags.add(t)));
+  document.getElementById('platform-group').style.display = tags.size ? '' : 'none';
+  const oses = new Set(['linux', 'darwin', 'windows']), arches = new Set(['amd64', 'arm64']);
+  tags.forEach(t => { if (knownOS.has(t)) oses.add(t); if (knownArch.has(t)) arches.add(t); });
+  const current = platformSelect.value;
+  let opts = '<option value="">All platforms</option>';
+  [...oses].sort().forEach(os => [...arches].sort().forEach(arch => {
+    opts += '<option value="' + os + '/' + arch + '">' + os + '/' + arch + '</option>';
+  }));
+  platformSelect.innerHTML = opts;
+  if ([...platformSelect.options].some(o => o.value === current)) platformSelect.value = current;
+}
+
+// buildHolds evaluates a constraint such as "linux && (amd64 || arm64)" for
+// platform "os/arch" the way the Go scanner does for --goos/--goarch; custom
+// tags don't hold.
+function buildHolds(expr, platform) {
+  const [os, arch] = platform.split('/');
+  const holds = t => t === os || t === arch || t === 'gc' || /^go1\.\d+$/.test(t) ||
+    (t === 'unix' && unixOS.has(os)) || (t === 'linux' && os === 'android') ||
+    (t === 'solaris' && os === 'illumos') || (t === 'darwin' && os === 'ios');
+  const toks = expr.match(/&&|\|\||[!()]|[\w.]+/g) || [];
+  let i = 0;
+  const or = () => { let v = and(); while (toks[i] === '||') { i++; const r = and(); v = v || r; } return v; };
+  const and = () => { let v = not(); while (toks[i] === '&&') { i++; const r = not(); v = v && r; } return v; };
+  const not = () => {
+    const t = toks[i++];
+    if (t === '!') return !not();
+    if (t === '(') { const v = or(); i++; return v; }
+    return holds(t);
+  };
+  return or();
+}
+platformSelect.addEventListener('change', render);
+
+// Test filter: shown when the scan flagged any test files.
+const testsSelect = document.getElementById('tests');
+function renderTestsToggle() {
+  document.getElementById('tests-group').style.display = data.some(f => f.test) ? '' : 'none';
+}
+testsSelect.addEventListener('change', render);
+
+indexData();
+renderStats();
+renderMetrics();
+renderPlatforms();
+renderTestsToggle();
 const rootEl = document.getElementById('root-path');
 rootEl.textContent = root;
 const vsBtn = document.createElement('a');
@@ -91,6 +195,7 @@ rootEl.parentElement.querySelector('div').prepend(vsBtn);
 // State
 const active = new Set(['stdlib', 'internal', 'private', 'external']);
 let selectedImport = null;
+let selectedImpact = null; // files whose transitive dependents are shown
 const collapsedFiles = new Set();
 
 // URL hash state
@@ -101,6 +206,8 @@ function readHash() {
     document.querySelectorAll('.view-btn').forEach(b => b.classList.toggle('active', b.dataset.view === p.get('view')));
   }
   if (p.has('sort')) document.getElementById('sort').value = p.get('sort');
+  if (p.has('platform')) platformSelect.value = p.get('platform');
+  if (p.has('tests')) testsSelect.value = p.get('tests');
   if (p.has('cats')) {
     const cats = new Set(p.get('cats').split(','));
     active.clear();
@@ -111,6 +218,10 @@ function readHash() {
     const rev = p.get('rev');
     if (reverseIndex[rev]) showReverse(rev);
   }
+  if (p.has('impact')) {
+    const files = p.getAll('impact').filter(f => data.some(d => d.file === f));
+    if (files.length) showImpact(files);
+  }
 }
 function writeHash() {
   const p = new URLSearchParams();
@@ -120,9 +231,12 @@ function writeHash() {
   if (view !== 'both') p.set('view', view);
   const sort = document.getElementById('sort').value;
   if (sort !== 'name-asc') p.set('sort', sort);
+  if (platformSelect.value) p.set('platform', platformSelect.value);
+  if (testsSelect.value) p.set('tests', testsSelect.value);
   const cats = [...active].sort().join(',');
   if (cats !== 'external,internal,private,stdlib') p.set('cats', cats);
   if (selectedImport) p.set('rev', selectedImport);
+  if (selectedImpact) selectedImpact.forEach(f => p.append('impact', f));
   const h = p.toString();
   history.replaceState(null, '', h ? '#' + h : location.pathname);
 }
@@ -152,18 +266,20 @@ const searchInput = document.getElementById('search');
 let debounceTimer;
 searchInput.addEventListener('input', () => {
   clearTimeout(debounceTimer);
-  debounceTimer = setTimeout(() => { selectedImport = null; render(); }, 150);
+  debounceTimer = setTimeout(() => { selectedImport = null; selectedImpact = null; render(); }, 150);
 });
 
 // Reverse panel close
 document.getElementById('reverse-close').addEventListener('click', () => {
   selectedImport = null;
+  selectedImpact = null;
   document.getElementById('code-panel').classList.remove('visible');
   render();
 });
 
 function showReverse(importName) {
   selectedImport = importName;
+  selectedImpact = null;
   const panel = document.getElementById('reverse-panel');
   const files = reverseIndex[importName] || [];
   document.getElementById('reverse-title').textContent = importName;
@@ -172,10 +288,58 @@ function showReverse(importName) {
   list.innerHTML = files.map(f =>
     '<li><a href="vscode://file/' + root + '/' + f + '">' + f + '</a></li>'
   ).join('');
+
+  // An import of project files can be followed to everything depending on them.
+  const targets = new Set();
+  data.forEach(f => f.imports.forEach(i => {
+    if (i.name === importName && i.category === 'internal') (i.resolved || []).forEach(t => targets.add(t));
+  }));
+  const impact = document.getElementById('reverse-impact');
+  if (targets.size) {
+    impact.textContent = 'Show transitive impact →';
+    impact.onclick = () => showImpact([...targets].sort(), importName);
+    impact.style.display = '';
+  } else {
+    impact.style.display = 'none';
+  }
   panel.classList.add('visible');
   render();
 }
 
+// Transitive dependents of files, grouped by distance (depth 1 = direct
+// importers). Each file appears once, at its shortest distance.
+function dependents(files) {
+  const seen = new Set(files);
+  const levels = [];
+  let frontier = files;
+  while (frontier.length) {
+    const next = [];
+    frontier.forEach(f => (importedBy[f] || []).forEach(d => {
+      if (!seen.has(d)) { seen.add(d); next.push(d); }
+    }));
+    if (!next.length) break;
+    levels.push(next.sort());
+    frontier = next;
+  }
+  return levels;
+}
+
+function showImpact(files, title) {
+  selectedImpact = files;
+  selectedImport = null;
+  const levels = dependents(files);
+  const total = levels.reduce((n, l) => n + l.length, 0);
+  document.getElementById('reverse-title').textContent = title || files.join(', ');
+  document.getElementById('reverse-count').textContent = total + ' file' + (total !== 1 ? 's' : '') + ' depend on this transitively';
+  document.getElementById('reverse-impact').style.display = 'none';
+  document.getElementById('reverse-list').innerHTML = levels.map((l, d) =>
+    '<li class="reverse-depth">depth ' + (d + 1) + ' · ' + l.length + '</li>' +
+    l.map(f => '<li><a href="vscode://file/' + root + '/' + f + '">' + f + '</a></li>').join('')
+  ).join('');
+  document.getElementById('reverse-panel').classList.add('visible');
+  render();
+}
+
 function escHtml(s) {
   return s.replace(/&/g,'&amp;').replace(/</g,'&lt;').replace(/>/g,'&gt;');
 }
@@ -193,12 +357,6 @@ function highlightSyntax(code) {
   });
 }
 
-// Build snippet lookup: "file::importName" → {snippet, kind, line}
-const snippetIndex = {};
-data.forEach(f => f.imports.forEach(i => {
-  if (i.snippet) snippetIndex[f.file + '::' + i.name] = { snippet: i.snippet, kind: i.kind, line: i.line };
-}));
-
 function showCode(file, importName, clickEvent) {
   const panel = document.getElementById('code-panel');
   const entry = snippetIndex[file + '::' + importName];
@@ -249,6 +407,7 @@ document.addEventListener('keydown', e => {
     document.getElementById('code-panel').classList.remove('visible');
     document.getElementById('reverse-panel').classList.remove('visible');
     selectedImport = null;
+    selectedImpact = null;
     render();
   }
   if (e.key === '/' && document.activeElement !== searchInput) {
@@ -274,7 +433,11 @@ const nameIcons = [
 const extIcons = {
   '.tsx': 'devicon-react-original', '.jsx': 'devicon-react-original',
   '.ts': 'devicon-typescript-plain', '.js': 'devicon-javascript-plain', '.mjs': 'devicon-javascript-plain',
+  '.vue': 'devicon-vuejs-plain', '.svelte': 'devicon-svelte-plain', '.astro': 'devicon-astro-plain',
   '.go': 'devicon-go-original-wordmark',
+  '.py': 'devicon-python-plain', '.pyi': 'devicon-python-plain',
+  '.rs': 'devicon-rust-original',
+  '.java': 'devicon-java-plain', '.kt': 'devicon-kotlin-plain', '.kts': 'devicon-kotlin-plain',
   '.css': 'devicon-css3-plain', '.scss': 'devicon-sass-original',
   '.json': 'devicon-json-plain', '.md': 'devicon-markdown-original',
   '.html': 'devicon-html5-plain', '.yml': 'devicon-yaml-plain', '.yaml': 'devicon-yaml-plain',
@@ -288,7 +451,8 @@ function fileIcon(path) {
 }
 
 // File tree
-(function() {
+const fileTreeEl = document.getElementById('file-tree');
+function renderFileTree() {
   const tree = {};
   data.forEach(f => {
     const parts = f.file.split('/');
@@ -298,16 +462,16 @@ function fileIcon(path) {
       else { node[p] ??= {}; node = node[p]; }
     });
   });
-  const el = document.getElementById('file-tree');
-  function renderDir(obj, depth) {
+  function renderDir(obj, depth, prefix) {
     let html = '';
     const dirs = Object.keys(obj).filter(k => k !== '__files').sort();
     const files = (obj.__files || []).sort();
     dirs.forEach(d => {
       const count = countFiles(obj[d]);
-      html += '<div class="ft-dir" data-depth="' + depth + '" style="padding-left:' + (0.5 + depth * 0.75) + 'rem">' +
+      const path = prefix + d + '/';
+      html += '<div class="ft-dir" data-depth="' + depth + '" data-path="' + path + '" style="padding-left:' + (0.5 + depth * 0.75) + 'rem">' +
         '<span class="ft-chevron">▾</span><span class="ft-label">📁 ' + d + '</span><span class="ft-count">' + count + '</span></div>' +
-        '<div class="ft-children">' + renderDir(obj[d], depth + 1) + '</div>';
+        '<div class="ft-children">' + renderDir(obj[d], depth + 1, path) + '</div>';
     });
     files.forEach(f => {
       const name = f.substring(f.lastIndexOf('/') + 1);
@@ -321,26 +485,34 @@ function fileIcon(path) {
     Object.keys(obj).filter(k => k !== '__files').forEach(k => n += countFiles(obj[k]));
     return n;
   }
-  el.innerHTML = renderDir(tree, 0);
-  el.addEventListener('click', e => {
-    const dir = e.target.closest('.ft-dir');
-    if (dir) {
-      const children = dir.nextElementSibling;
-      if (children && children.classList.contains('ft-children')) {
-        children.classList.toggle('collapsed');
-        dir.querySelector('.ft-chevron').classList.toggle('collapsed');
-      }
-      return;
-    }
-    const file = e.target.closest('.ft-file');
-    if (file) {
-      const card = document.querySelector('.card[data-file="' + file.dataset.file + '"]');
-      if (card) { card.scrollIntoView({ behavior: 'smooth', block: 'center' }); card.classList.add('highlighted'); setTimeout(() => card.classList.remove('highlighted'), 1500); }
-      el.querySelectorAll('.ft-file').forEach(f => f.classList.remove('active'));
-      file.classList.add('active');
-    }
+  // Keep collapsed directories collapsed across live updates.
+  const collapsed = new Set([...fileTreeEl.querySelectorAll('.ft-children.collapsed')].map(c => c.previousElementSibling.dataset.path));
+  fileTreeEl.innerHTML = renderDir(tree, 0, '');
+  fileTreeEl.querySelectorAll('.ft-dir').forEach(dir => {
+    if (!collapsed.has(dir.dataset.path)) return;
+    dir.nextElementSibling.classList.add('collapsed');
+    dir.querySelector('.ft-chevron').classList.add('collapsed');
   });
-})();
+}
+renderFileTree();
+fileTreeEl.addEventListener('click', e => {
+  const dir = e.target.closest('.ft-dir');
+  if (dir) {
+    const children = dir.nextElementSibling;
+    if (children && children.classList.contains('ft-children')) {
+      children.classList.toggle('collapsed');
+      dir.querySelector('.ft-chevron').classList.toggle('collapsed');
+    }
+    return;
+  }
+  const file = e.target.closest('.ft-file');
+  if (file) {
+    const card = document.querySelector('.card[data-file="' + file.dataset.file + '"]');
+    if (card) { card.scrollIntoView({ behavior: 'smooth', block: 'center' }); card.classList.add('highlighted'); setTimeout(() => card.classList.remove('highlighted'), 1500); }
+    fileTreeEl.querySelectorAll('.ft-file').forEach(f => f.classList.remove('active'));
+    file.classList.add('active');
+  }
+});
 
 function sortData(items) {
   const mode = document.getElementById('sort').value;
@@ -362,8 +534,10 @@ function render() {
   grid.innerHTML = '';
   let shown = 0;
 
-  // If reverse lookup active and no search, filter to files using that import
-  const reverseFiles = selectedImport ? new Set(reverseIndex[selectedImport] || []) : null;
+  // If reverse lookup active and no search, filter to files using that import,
+  // or in impact mode to the selected files and everything depending on them
+  let reverseFiles = selectedImport ? new Set(reverseIndex[selectedImport] || []) : null;
+  if (selectedImpact) reverseFiles = new Set([...selectedImpact, ...dependents(selectedImpact).flat()]);
 
   const sorted = sortData(data);
 
@@ -377,6 +551,8 @@ function render() {
     if (!hasContent) return;
 
     if (reverseFiles && !reverseFiles.has(f.file)) return;
+    if (platformSelect.value && f.build && !buildHolds(f.build, platformSelect.value)) return;
+    if ((testsSelect.value === 'hide' && f.test) || (testsSelect.value === 'only' && !f.test)) return;
 
     const fileMatch = f.file.toLowerCase().includes(q);
     const importMatch = visibleImports.some(i => i.name.toLowerCase().includes(q));
@@ -393,15 +569,21 @@ function render() {
       const highlight = q && i.name.toLowerCase().includes(q);
       let cls = 'tag tag-' + i.category;
       if (isSelected) cls += ' selected';
+      if (i.module) cls += ' cross-module';
       let style = highlight ? ' style="outline:1px solid var(--accent)"' : '';
 
-      let detail = '';
+      let lines = '';
       if (i.kind) {
-        let lines = '<span class="detail-kind">' + i.kind + '</span>';
+        lines += '<span class="detail-kind">' + i.kind + '</span>';
         if (i.alias) lines += '<div class="detail-names">as ' + i.alias + '</div>';
         if (i.names && i.names.length) lines += '<div class="detail-names">{ ' + i.names.join(', ') + ' }</div>';
-        detail = '<span class="tag-detail">' + lines + '</span>';
       }
+      if (i.version) {
+        const locked = i.locked && i.locked !== i.version ? ' → ' + i.locked : '';
+        lines += '<div class="detail-version">' + i.version + locked + '</div>';
+      }
+      if (i.module) lines += '<div class="detail-module">→ ' + escHtml(i.module) + '</div>';
+      const detail = lines ? '<span class="tag-detail">' + lines + '</span>' : '';
 
       return '<span class="' + cls + '"' + style + ' data-import="' + i.name + '" data-kind="' + (i.kind || '') + '">' + i.name + detail + '</span>';
     }).join('');
@@ -435,6 +617,10 @@ function render() {
           '<a href="vscode://file/' + root + '/' + f.file + '">' + f.file + '</a>' +
         '</div>' +
         '<div class="header-right">' +
+          (f.build ? '<span class="build-badge" title="Go build constraint">' + escHtml(f.build) + '</span>' : '') +
+          (f.module ? '<span class="module-badge" title="Go module or workspace package">' + escHtml(f.module) + '</span>' : '') +
+          (f.test ? '<span class="test-badge" title="Test file">test</span>' : '') +
+          (dependedOn[f.file] ? '<button class="impact-btn" title="' + dependedOn[f.file] + ' direct importer(s) — show transitive impact">⇡ ' + dependedOn[f.file] + '</button>' : '') +
           '<span class="import-count">' + count + '</span>' +
           '<button class="collapse-btn" title="Collapse">▾</button>' +
         '</div>' +
@@ -448,7 +634,7 @@ function render() {
 
   // Update reverse panel visibility
   const panel = document.getElementById('reverse-panel');
-  if (!selectedImport) panel.classList.remove('visible');
+  if (!selectedImport && !selectedImpact) panel.classList.remove('visible');
 
   document.getElementById('result-count').textContent = shown + ' of ' + data.length + ' files';
   document.getElementById('no-results').style.display = shown === 0 ? 'block' : 'none';
@@ -469,6 +655,11 @@ grid.addEventListener('click', e => {
     window.open('vscode://file/' + root + '/' + etag.dataset.file + lineRef, '_self');
     return;
   }
+  const impactBtn = e.target.closest('.impact-btn');
+  if (impactBtn) {
+    showImpact([impactBtn.closest('.card').dataset.file]);
+    return;
+  }
   const header = e.target.closest('.card-header');
   if (header && !e.target.closest('a')) {
     const card = header.closest('.card');
@@ -481,6 +672,32 @@ grid.addEventListener('click', e => {
 readHash();
 render();
 
+// Live reload (serve --watch): swap in new data and re-render in place,
+// keeping filters, search, the selected import and collapsed cards.
+function refresh() {
+  indexData();
+  renderStats();
+  renderMetrics();
+  renderPlatforms();
+  renderTestsToggle();
+  renderFileTree();
+  if (selectedImport && !reverseIndex[selectedImport]) selectedImport = null;
+  if (selectedImpact) selectedImpact = selectedImpact.filter(f => data.some(d => d.file === f));
+  if (selectedImpact && !selectedImpact.length) selectedImpact = null;
+  if (selectedImport) showReverse(selectedImport);
+  else if (selectedImpact) showImpact(selectedImpact, document.getElementById('reverse-title').textContent);
+  else render();
+}
+if (live) {
+  const events = new EventSource('/events');
+  events.addEventListener('update', e => {
+    const page = JSON.parse(e.data);
+    data.splice(0, data.length, ...page.files);
+    metrics = page.metrics;
+    refresh();
+  });
+}
+
 // Mobile sidebar toggle
 const menuToggle = document.getElementById('menu-toggle');
 const sidebar = document.getElementById('sidebar');
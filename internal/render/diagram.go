@@ -0,0 +1,216 @@
+package render
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/jtoloui/depviz/internal/classify"
+	"github.com/jtoloui/depviz/internal/config"
+	"github.com/jtoloui/depviz/internal/graph"
+	"github.com/jtoloui/depviz/internal/group"
+	"github.com/jtoloui/depviz/internal/scanner"
+)
+
+// categoryColours matches the default HTML theme so diagrams read the same
+// as the interactive map.
+var categoryColours = map[config.Category]string{
+	config.Stdlib:   "#56d364",
+	config.Internal: "#bc8cff",
+	config.Private:  "#58a6ff",
+	config.External: "#f0883e",
+}
+
+// crossModuleColour marks edges between a project's Go modules or
+// workspace packages.
+const crossModuleColour = "#d29922"
+
+var categoryOrder = []config.Category{config.Stdlib, config.Internal, config.Private, config.External}
+
+// DiagramOptions controls DOT and Mermaid output.
+type DiagramOptions struct {
+	// Collapse merges files into one node per directory, which for Go is
+	// one node per package.
+	Collapse bool
+	// Group merges files into package or directory nodes; it takes
+	// precedence over Collapse.
+	Group group.Mode
+	// Layout is the project's Go modules and workspace packages, for
+	// resolving imports.
+	Layout config.Layout
+}
+
+type diagramNode struct {
+	label    string
+	category config.Category
+}
+
+type diagramEdge struct {
+	from, to string
+}
+
+type diagram struct {
+	nodes map[string]diagramNode // keyed by label
+	edges map[diagramEdge]bool
+	cross map[diagramEdge]bool // edges between Go modules of the project
+}
+
+// buildDiagram turns results into a deduplicated node/edge model. Scanned
+// files are internal nodes; imports that resolve to a scanned file point at
+// that file's node, everything else becomes a node named by its specifier.
+// Edges from one Go module or workspace package of the project to another
+// are marked cross.
+func buildDiagram(root string, results []scanner.FileImports, cl *classify.Classifier, opts DiagramOptions) (*diagram, error) {
+	res, err := graph.NewResolver(root, opts.Layout, results)
+	if err != nil {
+		return nil, err
+	}
+
+	d := &diagram{nodes: map[string]diagramNode{}, edges: map[diagramEdge]bool{}, cross: map[diagramEdge]bool{}}
+	var groups map[string]string
+	if !opts.Group.IsZero() {
+		groups = group.Groups(results, opts.Group)
+	}
+	nodeFor := func(file string) string {
+		if groups != nil {
+			return groups[file]
+		}
+		if opts.Collapse {
+			return filepath.ToSlash(filepath.Dir(file))
+		}
+		return filepath.ToSlash(file)
+	}
+
+	for _, r := range results {
+		from := nodeFor(r.File)
+		d.nodes[from] = diagramNode{label: from, category: config.Internal}
+	}
+
+	for _, r := range results {
+		from := nodeFor(r.File)
+		for _, imp := range r.Imports {
+			var targets []string
+			for _, f := range res.Resolve(r.File, r.Lang, imp) {
+				targets = append(targets, nodeFor(f))
+			}
+			if len(targets) == 0 {
+				if _, ok := d.nodes[imp]; !ok {
+					d.nodes[imp] = diagramNode{label: imp, category: cl.ClassifyWithLang(imp, r.Lang)}
+				}
+				targets = []string{imp}
+			}
+			cross := res.CrossModule(r.File, r.Lang, imp) != ""
+			for _, to := range targets {
+				if to != from {
+					e := diagramEdge{from, to}
+					d.edges[e] = true
+					if cross {
+						d.cross[e] = true
+					}
+				}
+			}
+		}
+	}
+	return d, nil
+}
+
+func (d *diagram) sortedNodes() []diagramNode {
+	nodes := make([]diagramNode, 0, len(d.nodes))
+	for _, n := range d.nodes {
+		nodes = append(nodes, n)
+	}
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].label < nodes[j].label })
+	return nodes
+}
+
+func (d *diagram) sortedEdges() []diagramEdge {
+	edges := make([]diagramEdge, 0, len(d.edges))
+	for e := range d.edges {
+		edges = append(edges, e)
+	}
+	sort.Slice(edges, func(i, j int) bool {
+		if edges[i].from != edges[j].from {
+			return edges[i].from < edges[j].from
+		}
+		return edges[i].to < edges[j].to
+	})
+	return edges
+}
+
+// DOT writes the dependency graph in Graphviz DOT format.
+func DOT(w io.Writer, root string, results []scanner.FileImports, cl *classify.Classifier, opts DiagramOptions) error {
+	d, err := buildDiagram(root, results, cl, opts)
+	if err != nil {
+		return err
+	}
+
+	var b strings.Builder
+	b.WriteString("digraph depviz {\n")
+	b.WriteString("  rankdir=LR;\n")
+	b.WriteString("  node [shape=box, style=\"rounded,filled\", fontname=\"Helvetica\", fontsize=10];\n")
+	b.WriteString("  edge [color=\"#8b949e\"];\n")
+	for _, n := range d.sortedNodes() {
+		fmt.Fprintf(&b, "  %s [fillcolor=%q, tooltip=%q];\n", dotQuote(n.label), categoryColours[n.category], string(n.category))
+	}
+	for _, e := range d.sortedEdges() {
+		if d.cross[e] {
+			fmt.Fprintf(&b, "  %s -> %s [style=dashed, color=%q, penwidth=1.5];\n", dotQuote(e.from), dotQuote(e.to), crossModuleColour)
+			continue
+		}
+		fmt.Fprintf(&b, "  %s -> %s;\n", dotQuote(e.from), dotQuote(e.to))
+	}
+	b.WriteString("}\n")
+
+	_, err = io.WriteString(w, b.String())
+	return err
+}
+
+func dotQuote(s string) string {
+	return `"` + strings.ReplaceAll(strings.ReplaceAll(s, `\`, `\\`), `"`, `\"`) + `"`
+}
+
+// Mermaid writes the dependency graph as a Mermaid flowchart, ready to
+// paste into Markdown.
+func Mermaid(w io.Writer, root string, results []scanner.FileImports, cl *classify.Classifier, opts DiagramOptions) error {
+	d, err := buildDiagram(root, results, cl, opts)
+	if err != nil {
+		return err
+	}
+
+	var b strings.Builder
+	b.WriteString("flowchart LR\n")
+
+	// Mermaid IDs must be plain identifiers, so labels get numbered IDs.
+	ids := map[string]string{}
+	byCategory := map[config.Category][]string{}
+	for i, n := range d.sortedNodes() {
+		id := fmt.Sprintf("n%d", i)
+		ids[n.label] = id
+		byCategory[n.category] = append(byCategory[n.category], id)
+		fmt.Fprintf(&b, "  %s[\"%s\"]\n", id, mermaidEscape(n.label))
+	}
+	for _, e := range d.sortedEdges() {
+		arrow := "-->"
+		if d.cross[e] {
+			arrow = "-.->"
+		}
+		fmt.Fprintf(&b, "  %s %s %s\n", ids[e.from], arrow, ids[e.to])
+	}
+	for _, c := range categoryOrder {
+		fmt.Fprintf(&b, "  classDef %s fill:%s,stroke:%s,color:#0d1117\n", c, categoryColours[c], categoryColours[c])
+	}
+	for _, c := range categoryOrder {
+		if len(byCategory[c]) > 0 {
+			fmt.Fprintf(&b, "  class %s %s\n", strings.Join(byCategory[c], ","), c)
+		}
+	}
+
+	_, err = io.WriteString(w, b.String())
+	return err
+}
+
+func mermaidEscape(s string) string {
+	return strings.ReplaceAll(s, `"`, "#quot;")
+}
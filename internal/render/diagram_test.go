@@ -0,0 +1,162 @@
+package render_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jtoloui/depviz/internal/group"
+	"github.com/jtoloui/depviz/internal/render"
+	"github.com/jtoloui/depviz/internal/scanner"
+)
+
+func diagramFixture() []scanner.FileImports {
+	return []scanner.FileImports{
+		{File: "src/app.ts", Lang: "js", Imports: []string{"react", "./lib/util", "fs"}},
+		{File: "src/lib/util.ts", Lang: "js", Imports: []string{"lodash", "./helper"}},
+		{File: "src/lib/helper.ts", Lang: "js", Imports: []string{"lodash"}},
+	}
+}
+
+func TestDOT(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	if err := render.DOT(&buf, t.TempDir(), diagramFixture(), newClassifier(t, "js"), render.DiagramOptions{}); err != nil {
+		t.Fatalf("DOT: %v", err)
+	}
+	out := buf.String()
+
+	checks := []struct {
+		name string
+		want string
+	}{
+		{"header", "digraph depviz {"},
+		{"file node internal", `"src/app.ts" [fillcolor="#bc8cff"`},
+		{"external node", `"react" [fillcolor="#f0883e"`},
+		{"stdlib node", `"fs" [fillcolor="#56d364"`},
+		{"resolved edge", `"src/app.ts" -> "src/lib/util.ts";`},
+		{"external edge", `"src/lib/util.ts" -> "lodash";`},
+	}
+	for _, c := range checks {
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+			if !strings.Contains(out, c.want) {
+				t.Errorf("output missing %q\n%s", c.want, out)
+			}
+		})
+	}
+
+	if strings.Contains(out, `"./lib/util"`) {
+		t.Error("resolved import should not appear as its own node")
+	}
+	if n := strings.Count(out, `"lodash" [`); n != 1 {
+		t.Errorf("lodash node declared %d times, want 1", n)
+	}
+}
+
+func TestDOT_Collapse(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	if err := render.DOT(&buf, t.TempDir(), diagramFixture(), newClassifier(t, "js"), render.DiagramOptions{Collapse: true}); err != nil {
+		t.Fatalf("DOT: %v", err)
+	}
+	out := buf.String()
+
+	if !strings.Contains(out, `"src" -> "src/lib";`) {
+		t.Errorf("missing collapsed edge:\n%s", out)
+	}
+	if strings.Contains(out, `"src/lib" -> "src/lib"`) {
+		t.Error("collapsed self-edge should be dropped")
+	}
+	if strings.Contains(out, "util.ts") {
+		t.Error("file names should not appear when collapsed")
+	}
+}
+
+func TestDOT_GroupByDepth(t *testing.T) {
+	t.Parallel()
+
+	mode, err := group.Parse("depth=1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := render.DOT(&buf, t.TempDir(), diagramFixture(), newClassifier(t, "js"), render.DiagramOptions{Group: mode}); err != nil {
+		t.Fatalf("DOT: %v", err)
+	}
+	out := buf.String()
+
+	if !strings.Contains(out, `"src" [`) || strings.Contains(out, `"src/lib"`) {
+		t.Errorf("want every file under src/ in one node:\n%s", out)
+	}
+}
+
+func TestDiagram_CrossModule(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	for name, content := range map[string]string{
+		"go.mod":     "module example.com/app\n",
+		"lib/go.mod": "module example.com/lib\n",
+	} {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	results := []scanner.FileImports{
+		{File: "main.go", Lang: "go", Imports: []string{"example.com/app/svc", "example.com/lib"}},
+		{File: "svc/svc.go", Lang: "go"},
+		{File: "lib/lib.go", Lang: "go"},
+	}
+	cl := newClassifier(t, "go")
+	opts := render.DiagramOptions{Layout: loadLayout(t, root)}
+
+	var dot bytes.Buffer
+	if err := render.DOT(&dot, root, results, cl, opts); err != nil {
+		t.Fatalf("DOT: %v", err)
+	}
+	if !strings.Contains(dot.String(), `"main.go" -> "lib/lib.go" [style=dashed`) {
+		t.Errorf("cross-module edge not dashed:\n%s", dot.String())
+	}
+	if !strings.Contains(dot.String(), `"main.go" -> "svc/svc.go";`) {
+		t.Errorf("same-module edge should be plain:\n%s", dot.String())
+	}
+
+	var mmd bytes.Buffer
+	if err := render.Mermaid(&mmd, root, results, cl, opts); err != nil {
+		t.Fatalf("Mermaid: %v", err)
+	}
+	if strings.Count(mmd.String(), "-.->") != 1 || strings.Count(mmd.String(), "-->") != 1 {
+		t.Errorf("want one dotted and one solid edge:\n%s", mmd.String())
+	}
+}
+
+func TestMermaid(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	if err := render.Mermaid(&buf, t.TempDir(), diagramFixture(), newClassifier(t, "js"), render.DiagramOptions{}); err != nil {
+		t.Fatalf("Mermaid: %v", err)
+	}
+	out := buf.String()
+
+	if !strings.HasPrefix(out, "flowchart LR\n") {
+		t.Errorf("missing flowchart header:\n%s", out)
+	}
+	for _, want := range []string{`["src/app.ts"]`, `["react"]`, "-->", "classDef external fill:#f0883e", "classDef internal fill:#bc8cff"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q", want)
+		}
+	}
+	if !strings.Contains(out, "class ") || !strings.Contains(out, " internal\n") {
+		t.Errorf("nodes not assigned to classes:\n%s", out)
+	}
+}
@@ -5,10 +5,15 @@ import (
 	"encoding/json"
 	"html/template"
 	"io"
+	"slices"
 	"sort"
 
 	"github.com/jtoloui/depviz/internal/classify"
 	"github.com/jtoloui/depviz/internal/config"
+	"github.com/jtoloui/depviz/internal/graph"
+	"github.com/jtoloui/depviz/internal/group"
+	"github.com/jtoloui/depviz/internal/manifest"
+	"github.com/jtoloui/depviz/internal/metrics"
 	"github.com/jtoloui/depviz/internal/scanner"
 )
 
@@ -31,6 +36,16 @@ type classifiedImport struct {
 	Alias    string             `json:"alias,omitempty"`
 	Snippet  string             `json:"snippet,omitempty"`
 	Line     int                `json:"line,omitempty"`
+	// Resolved lists the scanned files the import points at, so the page
+	// can walk file-level dependents.
+	Resolved []string `json:"resolved,omitempty"`
+	// Version is the range or version the project declares for an external
+	// import, and Locked the one its lockfile pins.
+	Version string `json:"version,omitempty"`
+	Locked  string `json:"locked,omitempty"`
+	// Module is the other Go module or JS/TS workspace package of the
+	// project an import crosses into.
+	Module string `json:"module,omitempty"`
 }
 
 type exportData struct {
@@ -42,26 +57,124 @@ type exportData struct {
 
 type fileData struct {
 	File    string             `json:"file"`
+	Lang    string             `json:"lang,omitempty"`
+	Build   string             `json:"build,omitempty"`
+	IsTest  bool               `json:"test,omitempty"`
+	Module  string             `json:"module,omitempty"` // owning Go module or workspace package, when the project has several
 	Imports []classifiedImport `json:"imports"`
 	Exports []exportData       `json:"exports,omitempty"`
 	Lines   int                `json:"lines,omitempty"`
 }
 
+// Options controls HTML and JSON output.
+type Options struct {
+	// Group merges files into package or directory nodes.
+	Group group.Mode
+	// Layout is the project's Go modules and workspace packages, for
+	// resolving imports.
+	Layout config.Layout
+}
+
 type templateData struct {
-	DataJSON template.JS
-	Root     string
-	CSS      template.CSS
-	JS       template.JS
+	DataJSON    template.JS
+	MetricsJSON template.JS
+	Root        string
+	Live        bool
+	CSS         template.CSS
+	JS          template.JS
 }
 
 // HTML writes a dependency visualisation to w.
-func HTML(w io.Writer, root string, results []scanner.FileImports, cl *classify.Classifier) error {
+func HTML(w io.Writer, root string, results []scanner.FileImports, cl *classify.Classifier, opts Options) error {
+	return html(w, root, results, cl, opts, false)
+}
+
+// LiveHTML is HTML for a page served by `serve --watch`: it subscribes to
+// /events and re-renders in place on every update.
+func LiveHTML(w io.Writer, root string, results []scanner.FileImports, cl *classify.Classifier, opts Options) error {
+	return html(w, root, results, cl, opts, true)
+}
+
+// pageData is everything the page renders from.
+type pageData struct {
+	Files []fileData `json:"files"`
+	// Metrics are per package, whatever opts.Group says.
+	Metrics []metrics.Package `json:"metrics"`
+}
+
+// Data returns the page's data model as compact JSON — the payload of a live
+// update.
+func Data(root string, results []scanner.FileImports, cl *classify.Classifier, opts Options) ([]byte, error) {
+	page, err := buildPage(root, results, cl, opts)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(page)
+}
+
+func buildPage(root string, results []scanner.FileImports, cl *classify.Classifier, opts Options) (pageData, error) {
+	res, err := graph.NewResolver(root, opts.Layout, results)
+	if err != nil {
+		return pageData{}, err
+	}
+	files, err := buildFiles(root, results, cl, res, opts)
+	if err != nil {
+		return pageData{}, err
+	}
+	g := graph.New(results, res)
+	internal := g.Filter(func(e graph.Edge) bool {
+		return cl.ClassifyWithLang(e.Import.Path, g.Node(e.From).Lang) == config.Internal
+	})
+	pkgs := metrics.Compute(results, internal)
+	if pkgs == nil {
+		pkgs = []metrics.Package{}
+	}
+	return pageData{Files: files, Metrics: pkgs}, nil
+}
+
+func html(w io.Writer, root string, results []scanner.FileImports, cl *classify.Classifier, opts Options, live bool) error {
+	page, err := buildPage(root, results, cl, opts)
+	if err != nil {
+		return err
+	}
+	files, err := json.Marshal(page.Files)
+	if err != nil {
+		return err
+	}
+	pkgs, err := json.Marshal(page.Metrics)
+	if err != nil {
+		return err
+	}
+
+	return tmpl.Execute(w, templateData{
+		DataJSON:    template.JS(files),
+		MetricsJSON: template.JS(pkgs),
+		Root:        root,
+		Live:        live,
+		CSS:         template.CSS(cssContent),
+		JS:          template.JS(jsContent),
+	})
+}
+
+// buildFiles turns scan results into the classified per-file model shared
+// by the HTML and JSON outputs, sorted by file path, resolving imports with
+// res. With opts.Group, each entry is a group of files instead.
+func buildFiles(root string, results []scanner.FileImports, cl *classify.Classifier, res *graph.Resolver, opts Options) ([]fileData, error) {
+	deps, err := manifest.Load(root, opts.Layout)
+	if err != nil {
+		return nil, err
+	}
+	resolve := func(r scanner.FileImports, spec string) []string { return res.Resolve(r.File, r.Lang, spec) }
+	if !opts.Group.IsZero() {
+		resolve = groupResolver(results, opts.Group, res)
+		results = group.Merge(results, opts.Group)
+	}
 	sort.Slice(results, func(i, j int) bool { return results[i].File < results[j].File })
 	files := make([]fileData, len(results))
 	for i, r := range results {
 		imps := make([]classifiedImport, len(r.Imports))
 		for j, imp := range r.Imports {
-			ci := classifiedImport{Name: imp, Category: cl.ClassifyWithLang(imp, r.Lang)}
+			ci := classifiedImport{Name: imp, Category: cl.ClassifyWithLang(imp, r.Lang), Resolved: resolve(r, imp)}
 			if j < len(r.Details) {
 				d := r.Details[j]
 				ci.Kind = d.Kind
@@ -70,9 +183,15 @@ func HTML(w io.Writer, root string, results []scanner.FileImports, cl *classify.
 				ci.Snippet = d.Snippet
 				ci.Line = d.Line
 			}
+			if ci.Category == config.External || ci.Category == config.Private {
+				if dep, ok := deps.Lookup(r.File, r.Lang, imp); ok {
+					ci.Version, ci.Locked = dep.Version, dep.Locked
+				}
+			}
+			ci.Module = res.CrossModule(r.File, r.Lang, imp)
 			imps[j] = ci
 		}
-		files[i] = fileData{File: r.File, Imports: imps, Lines: r.Lines}
+		files[i] = fileData{File: r.File, Lang: r.Lang, Build: r.Build, IsTest: r.IsTest, Module: res.Module(r.File, r.Lang), Imports: imps, Lines: r.Lines}
 		if len(r.Exports) > 0 {
 			exports := make([]exportData, len(r.Exports))
 			for k, e := range r.Exports {
@@ -81,16 +200,34 @@ func HTML(w io.Writer, root string, results []scanner.FileImports, cl *classify.
 			files[i].Exports = exports
 		}
 	}
+	return files, nil
+}
 
-	data, err := json.Marshal(files)
-	if err != nil {
-		return err
+// groupResolver resolves imports against the file-level results — grouped
+// specifiers no longer resolve on their own — and maps the files they point
+// at to their groups. Imports within a group are dropped.
+func groupResolver(results []scanner.FileImports, m group.Mode, res *graph.Resolver) func(scanner.FileImports, string) []string {
+	names := group.Groups(results, m)
+	targets := map[string]map[string][]string{} // group → specifier → groups
+	for _, r := range results {
+		from := names[r.File]
+		for _, imp := range r.Imports {
+			for _, f := range res.Resolve(r.File, r.Lang, imp) {
+				to := names[f]
+				if to == from || slices.Contains(targets[from][imp], to) {
+					continue
+				}
+				if targets[from] == nil {
+					targets[from] = map[string][]string{}
+				}
+				targets[from][imp] = append(targets[from][imp], to)
+			}
+		}
 	}
-
-	return tmpl.Execute(w, templateData{
-		DataJSON: template.JS(data),
-		Root:     root,
-		CSS:      template.CSS(cssContent),
-		JS:       template.JS(jsContent),
-	})
+	for _, specs := range targets {
+		for _, groups := range specs {
+			sort.Strings(groups)
+		}
+	}
+	return func(r scanner.FileImports, spec string) []string { return targets[r.File][spec] }
 }
@@ -27,6 +27,17 @@ func newClassifier(t *testing.T, lang string) *classify.Classifier {
 	return cl
 }
 
+// loadLayout finds the Go modules and workspace packages of the project
+// written to root.
+func loadLayout(t *testing.T, root string) config.Layout {
+	t.Helper()
+	l, err := config.LoadLayout(root, "multi")
+	if err != nil {
+		t.Fatalf("LoadLayout: %v", err)
+	}
+	return l
+}
+
 func TestHTML_ContainsStructure(t *testing.T) {
 	t.Parallel()
 
@@ -47,7 +58,7 @@ func TestHTML_ContainsStructure(t *testing.T) {
 	}
 
 	var buf bytes.Buffer
-	if err := render.HTML(&buf, "/project", results, newClassifier(t, "js")); err != nil {
+	if err := render.HTML(&buf, "/project", results, newClassifier(t, "js"), render.Options{}); err != nil {
 		t.Fatalf("HTML: %v", err)
 	}
 	html := buf.String()
@@ -96,7 +107,7 @@ func TestHTML_JSONDataIntegrity(t *testing.T) {
 	}
 
 	var buf bytes.Buffer
-	if err := render.HTML(&buf, "/go-project", results, newClassifier(t, "go")); err != nil {
+	if err := render.HTML(&buf, "/go-project", results, newClassifier(t, "go"), render.Options{}); err != nil {
 		t.Fatalf("HTML: %v", err)
 	}
 
@@ -182,7 +193,7 @@ func TestHTML_DuplicateImportPaths(t *testing.T) {
 	}
 
 	var buf bytes.Buffer
-	if err := render.HTML(&buf, "/project", results, newClassifier(t, "js")); err != nil {
+	if err := render.HTML(&buf, "/project", results, newClassifier(t, "js"), render.Options{}); err != nil {
 		t.Fatalf("HTML: %v", err)
 	}
 
@@ -214,7 +225,7 @@ func TestHTML_EmptyResults(t *testing.T) {
 	t.Parallel()
 
 	var buf bytes.Buffer
-	if err := render.HTML(&buf, "/empty", nil, newClassifier(t, "js")); err != nil {
+	if err := render.HTML(&buf, "/empty", nil, newClassifier(t, "js"), render.Options{}); err != nil {
 		t.Fatalf("HTML: %v", err)
 	}
 
@@ -241,11 +252,11 @@ func TestHTML_DeterministicOrder(t *testing.T) {
 	}
 
 	var buf1, buf2 bytes.Buffer
-	if err := render.HTML(&buf1, "/tmp", results, cl); err != nil {
+	if err := render.HTML(&buf1, "/tmp", results, cl, render.Options{}); err != nil {
 		t.Fatal(err)
 	}
 	results[0], results[2] = results[2], results[0]
-	if err := render.HTML(&buf2, "/tmp", results, cl); err != nil {
+	if err := render.HTML(&buf2, "/tmp", results, cl, render.Options{}); err != nil {
 		t.Fatal(err)
 	}
 
@@ -261,3 +272,46 @@ func TestHTML_DeterministicOrder(t *testing.T) {
 		t.Errorf("files not sorted: a=%d m=%d z=%d", aIdx, mIdx, zIdx)
 	}
 }
+
+func TestLiveHTML(t *testing.T) {
+	t.Parallel()
+
+	cl := newClassifier(t, "go")
+	results := []scanner.FileImports{{File: "main.go", Lang: "go", Imports: []string{"fmt"}}}
+
+	var static, live bytes.Buffer
+	if err := render.HTML(&static, "/tmp", results, cl, render.Options{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := render.LiveHTML(&live, "/tmp", results, cl, render.Options{}); err != nil {
+		t.Fatal(err)
+	}
+	liveFlag := regexp.MustCompile(`const live =\s*(\w+)\s*;`)
+	if m := liveFlag.FindStringSubmatch(static.String()); m == nil || m[1] != "false" {
+		t.Error("static page should not subscribe to updates")
+	}
+	if m := liveFlag.FindStringSubmatch(live.String()); m == nil || m[1] != "true" {
+		t.Error("live page should subscribe to updates")
+	}
+
+	data, err := render.Data("/tmp", results, cl, render.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.ContainsRune(data, '\n') {
+		t.Error("update payload must be a single line for SSE")
+	}
+	var payload struct {
+		Files   json.RawMessage `json:"files"`
+		Metrics json.RawMessage `json:"metrics"`
+	}
+	if err := json.Unmarshal(data, &payload); err != nil {
+		t.Fatalf("update payload: %v", err)
+	}
+	if !strings.Contains(live.String(), "const data = "+string(payload.Files)+";") {
+		t.Error("page data should match the update payload")
+	}
+	if !strings.Contains(live.String(), "let metrics = "+string(payload.Metrics)+";") {
+		t.Error("page metrics should match the update payload")
+	}
+}
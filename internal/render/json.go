@@ -0,0 +1,31 @@
+package render
+
+import (
+	"encoding/json"
+	"io"
+
+	"github.com/jtoloui/depviz/internal/classify"
+	"github.com/jtoloui/depviz/internal/graph"
+	"github.com/jtoloui/depviz/internal/scanner"
+)
+
+type jsonDocument struct {
+	Root  string     `json:"root"`
+	Files []fileData `json:"files"`
+}
+
+// JSON writes the classified scan results — the same model the HTML page
+// embeds — as indented JSON for scripts and dashboards.
+func JSON(w io.Writer, root string, results []scanner.FileImports, cl *classify.Classifier, opts Options) error {
+	res, err := graph.NewResolver(root, opts.Layout, results)
+	if err != nil {
+		return err
+	}
+	files, err := buildFiles(root, results, cl, res, opts)
+	if err != nil {
+		return err
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(jsonDocument{Root: root, Files: files})
+}
@@ -0,0 +1,241 @@
+package render_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jtoloui/depviz/internal/group"
+	"github.com/jtoloui/depviz/internal/render"
+	"github.com/jtoloui/depviz/internal/scanner"
+)
+
+func TestJSON(t *testing.T) {
+	t.Parallel()
+
+	results := []scanner.FileImports{
+		{
+			File:    "src/b.ts",
+			Lang:    "js",
+			Imports: []string{"fs", "./a"},
+			Details: []scanner.ImportDetail{
+				{Path: "fs", Kind: scanner.ImportNamespace, Alias: "fs", Line: 1},
+				{Path: "./a", Kind: scanner.ImportNamed, Names: []string{"x"}, Line: 2},
+			},
+			Exports: []scanner.ExportDetail{{Name: "B", Kind: scanner.ExportClass, Line: 4}},
+			Lines:   9,
+		},
+		{File: "src/a.ts", Lang: "js", Imports: []string{"react"}},
+	}
+
+	var buf bytes.Buffer
+	if err := render.JSON(&buf, "/project", results, newClassifier(t, "js"), render.Options{}); err != nil {
+		t.Fatalf("JSON: %v", err)
+	}
+
+	var doc struct {
+		Root  string `json:"root"`
+		Files []struct {
+			File    string `json:"file"`
+			Lang    string `json:"lang"`
+			Lines   int    `json:"lines"`
+			Imports []struct {
+				Name     string   `json:"name"`
+				Category string   `json:"category"`
+				Names    []string `json:"names"`
+				Line     int      `json:"line"`
+				Resolved []string `json:"resolved"`
+			} `json:"imports"`
+			Exports []struct {
+				Name string `json:"name"`
+			} `json:"exports"`
+		} `json:"files"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatalf("unmarshal: %v\n%s", err, buf.String())
+	}
+
+	if doc.Root != "/project" {
+		t.Errorf("root = %q, want /project", doc.Root)
+	}
+	if len(doc.Files) != 2 || doc.Files[0].File != "src/a.ts" {
+		t.Fatalf("files not sorted: %+v", doc.Files)
+	}
+
+	b := doc.Files[1]
+	if b.Lang != "js" || b.Lines != 9 {
+		t.Errorf("b.ts lang/lines = %q/%d", b.Lang, b.Lines)
+	}
+	if b.Imports[0].Category != "stdlib" || b.Imports[1].Category != "internal" {
+		t.Errorf("categories = %q, %q", b.Imports[0].Category, b.Imports[1].Category)
+	}
+	if len(b.Imports[1].Names) != 1 || b.Imports[1].Line != 2 {
+		t.Errorf("detail not preserved: %+v", b.Imports[1])
+	}
+	if r := b.Imports[1].Resolved; len(r) != 1 || r[0] != "src/a.ts" {
+		t.Errorf("./a resolved = %v, want [src/a.ts]", r)
+	}
+	if r := b.Imports[0].Resolved; r != nil {
+		t.Errorf("fs resolved = %v, want none", r)
+	}
+	if len(b.Exports) != 1 || b.Exports[0].Name != "B" {
+		t.Errorf("exports = %+v", b.Exports)
+	}
+}
+
+func TestJSON_Grouped(t *testing.T) {
+	t.Parallel()
+
+	results := []scanner.FileImports{
+		{File: "src/app.ts", Lang: "js", Imports: []string{"./ui/button", "./util"}, Lines: 4},
+		{File: "src/util.ts", Lang: "js", Lines: 2},
+		{File: "src/ui/button.ts", Lang: "js", Imports: []string{"react", "../util"}, Lines: 6},
+		{File: "src/ui/link.ts", Lang: "js", Imports: []string{"react", "./button"}, Lines: 3},
+	}
+
+	mode, err := group.Parse("dir")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := render.JSON(&buf, "/project", results, newClassifier(t, "js"), render.Options{Group: mode}); err != nil {
+		t.Fatalf("JSON: %v", err)
+	}
+
+	var doc struct {
+		Files []struct {
+			File    string `json:"file"`
+			Lines   int    `json:"lines"`
+			Imports []struct {
+				Name     string   `json:"name"`
+				Resolved []string `json:"resolved"`
+			} `json:"imports"`
+		} `json:"files"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if len(doc.Files) != 2 || doc.Files[0].File != "src" || doc.Files[1].File != "src/ui" {
+		t.Fatalf("files = %+v, want src and src/ui", doc.Files)
+	}
+	ui := doc.Files[1]
+	if ui.Lines != 9 || len(ui.Imports) != 3 {
+		t.Fatalf("src/ui = %+v, want 9 lines and react, ../util, ./button", ui)
+	}
+	resolved := map[string][]string{}
+	for _, imp := range ui.Imports {
+		resolved[imp.Name] = imp.Resolved
+	}
+	// Imports resolve to groups, and ones inside the group to nothing.
+	if got := resolved["../util"]; len(got) != 1 || got[0] != "src" {
+		t.Errorf("../util resolved = %v, want [src]", got)
+	}
+	if got := resolved["./button"]; len(got) != 0 {
+		t.Errorf("./button resolved = %v, want none", got)
+	}
+}
+
+func TestJSON_Versions(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	for name, content := range map[string]string{
+		"package.json":      `{"dependencies": {"react": "^18.2.0"}}`,
+		"package-lock.json": `{"packages": {"node_modules/react": {"version": "18.3.1"}}}`,
+	} {
+		if err := os.WriteFile(filepath.Join(root, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	results := []scanner.FileImports{
+		{File: "src/app.ts", Lang: "js", Imports: []string{"react/jsx-runtime", "lodash", "./util", "fs"}},
+	}
+
+	var buf bytes.Buffer
+	if err := render.JSON(&buf, root, results, newClassifier(t, "js"), render.Options{}); err != nil {
+		t.Fatalf("JSON: %v", err)
+	}
+	var doc struct {
+		Files []struct {
+			Imports []struct {
+				Name    string `json:"name"`
+				Version string `json:"version"`
+				Locked  string `json:"locked"`
+			} `json:"imports"`
+		} `json:"files"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	for _, imp := range doc.Files[0].Imports {
+		want := ""
+		if imp.Name == "react/jsx-runtime" {
+			want = "^18.2.0 18.3.1"
+		}
+		if got := strings.TrimSpace(imp.Version + " " + imp.Locked); got != want {
+			t.Errorf("%s version = %q, want %q", imp.Name, got, want)
+		}
+	}
+}
+
+func TestJSON_Modules(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	for name, content := range map[string]string{
+		"go.work":          "go 1.25\n\nuse (\n\t./api\n\t./lib\n)\n",
+		"api/go.mod":       "module example.com/api\n",
+		"lib/go.mod":       "module example.com/lib\n",
+		"lib/util/util.go": "package util\n",
+		"api/cmd/main.go":  "package main\n",
+		"api/handler/h.go": "package handler\n",
+	} {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	results := []scanner.FileImports{
+		{File: "api/cmd/main.go", Lang: "go", Imports: []string{"example.com/api/handler", "example.com/lib/util", "fmt"}},
+		{File: "api/handler/h.go", Lang: "go"},
+		{File: "lib/util/util.go", Lang: "go"},
+	}
+
+	var buf bytes.Buffer
+	if err := render.JSON(&buf, root, results, newClassifier(t, "go"), render.Options{Layout: loadLayout(t, root)}); err != nil {
+		t.Fatalf("JSON: %v", err)
+	}
+	var doc struct {
+		Files []struct {
+			File    string `json:"file"`
+			Module  string `json:"module"`
+			Imports []struct {
+				Name     string   `json:"name"`
+				Module   string   `json:"module"`
+				Resolved []string `json:"resolved"`
+			} `json:"imports"`
+		} `json:"files"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	main := doc.Files[0]
+	if main.File != "api/cmd/main.go" || main.Module != "example.com/api" {
+		t.Fatalf("file = %s in %q", main.File, main.Module)
+	}
+	want := map[string]string{"example.com/api/handler": "", "example.com/lib/util": "example.com/lib", "fmt": ""}
+	for _, imp := range main.Imports {
+		if imp.Module != want[imp.Name] {
+			t.Errorf("%s module = %q, want %q", imp.Name, imp.Module, want[imp.Name])
+		}
+	}
+	if r := main.Imports[1].Resolved; len(r) != 1 || r[0] != "lib/util/util.go" {
+		t.Errorf("cross-module import resolved to %v", r)
+	}
+}
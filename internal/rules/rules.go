@@ -0,0 +1,126 @@
+// Package rules evaluates the architecture constraints declared under
+// `rules:` in .depviz.yml against scan results.
+package rules
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/jtoloui/depviz/internal/config"
+	"github.com/jtoloui/depviz/internal/glob"
+	"github.com/jtoloui/depviz/internal/graph"
+	"github.com/jtoloui/depviz/internal/scanner"
+)
+
+// Violation is one import that breaks a rule.
+type Violation struct {
+	Rule   string               `json:"rule"`
+	File   string               `json:"file"`
+	Import scanner.ImportDetail `json:"import"`
+}
+
+type rule struct {
+	name  string
+	from  glob.Set
+	to    glob.Set
+	allow glob.Set
+	deny  glob.Set
+}
+
+// Engine holds compiled rules ready to check.
+type Engine struct {
+	rules []rule
+}
+
+// New compiles rules. Selectors are validated by config.Load, but New
+// re-checks so an Engine is never half-built.
+func New(rules []config.Rule) (*Engine, error) {
+	e := &Engine{rules: make([]rule, len(rules))}
+	for i, r := range rules {
+		name := r.Name
+		if name == "" {
+			name = fmt.Sprintf("rule %d (%s)", i+1, strings.Join(r.From, ", "))
+		}
+
+		var err error
+		compile := func(sels config.Selectors) glob.Set {
+			if err != nil {
+				return nil
+			}
+			var set glob.Set
+			set, err = glob.CompileAll(sels)
+			return set
+		}
+		e.rules[i] = rule{
+			name:  name,
+			from:  compile(r.From),
+			to:    compile(r.To),
+			allow: compile(r.Allow),
+			deny:  compile(r.Deny),
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+	}
+	return e, nil
+}
+
+// Check evaluates every import in results against every rule. Each import is
+// matched by its specifier and by any project file res resolves it to, so
+// "src/db/**" catches "../db/query" as well. Violations are ordered by file
+// then line.
+func (e *Engine) Check(results []scanner.FileImports, res *graph.Resolver) []Violation {
+	var out []Violation
+	for _, fi := range results {
+		var applicable []rule
+		for _, r := range e.rules {
+			if r.from.Match(fi.File) {
+				applicable = append(applicable, r)
+			}
+		}
+		if len(applicable) == 0 {
+			continue
+		}
+
+		for i, imp := range fi.Imports {
+			d := scanner.ImportDetail{Path: imp}
+			if i < len(fi.Details) {
+				d = fi.Details[i]
+			}
+			targets := append([]string{imp}, res.Resolve(fi.File, fi.Lang, imp)...)
+			for _, r := range applicable {
+				if r.violatedBy(targets) {
+					out = append(out, Violation{Rule: r.name, File: fi.File, Import: d})
+				}
+			}
+		}
+	}
+
+	sort.SliceStable(out, func(i, j int) bool {
+		if out[i].File != out[j].File {
+			return out[i].File < out[j].File
+		}
+		return out[i].Import.Line < out[j].Import.Line
+	})
+	return out
+}
+
+func (r rule) violatedBy(targets []string) bool {
+	if len(r.to) > 0 && !anyMatch(r.to, targets) {
+		return false
+	}
+	if anyMatch(r.allow, targets) {
+		return false
+	}
+	return len(r.allow) > 0 || anyMatch(r.deny, targets)
+}
+
+func anyMatch(set glob.Set, targets []string) bool {
+	for _, t := range targets {
+		if set.Match(t) {
+			return true
+		}
+	}
+	return false
+}
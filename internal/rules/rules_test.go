@@ -0,0 +1,115 @@
+package rules_test
+
+import (
+	"testing"
+
+	"github.com/jtoloui/depviz/internal/config"
+	"github.com/jtoloui/depviz/internal/graph"
+	"github.com/jtoloui/depviz/internal/rules"
+	"github.com/jtoloui/depviz/internal/scanner"
+)
+
+func TestCheck(t *testing.T) {
+	t.Parallel()
+
+	results := []scanner.FileImports{
+		{
+			File: "src/ui/Button.tsx", Lang: "js",
+			Imports: []string{"react", "../db/query", "@mui/material", "@mui/icons-material"},
+			Details: []scanner.ImportDetail{
+				{Path: "react", Line: 1},
+				{Path: "../db/query", Line: 2, Snippet: "import { q } from '../db/query';"},
+				{Path: "@mui/material", Line: 3},
+				{Path: "@mui/icons-material", Line: 4},
+			},
+		},
+		{File: "src/db/query.ts", Lang: "js", Imports: []string{"pg"}},
+		{File: "src/api/handler.ts", Lang: "js", Imports: []string{"../db/query"}},
+	}
+
+	res, err := graph.NewResolver(t.TempDir(), config.Layout{}, results)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name  string
+		rule  config.Rule
+		files []string
+		lines []int
+	}{
+		{
+			name:  "deny resolved path",
+			rule:  config.Rule{Name: "ui-no-db", From: config.Selectors{"src/ui/**"}, Deny: config.Selectors{"src/db/**"}},
+			files: []string{"src/ui/Button.tsx"},
+			lines: []int{2},
+		},
+		{
+			name:  "deny by specifier regex",
+			rule:  config.Rule{From: config.Selectors{"src/**"}, Deny: config.Selectors{"^pg$"}},
+			files: []string{"src/db/query.ts"},
+			lines: []int{0},
+		},
+		{
+			name:  "allow list scoped by to",
+			rule:  config.Rule{From: config.Selectors{"src/ui/**"}, To: config.Selectors{"^@mui/"}, Allow: config.Selectors{"^@mui/material$"}},
+			files: []string{"src/ui/Button.tsx"},
+			lines: []int{4},
+		},
+		{
+			name:  "allow exempts from deny",
+			rule:  config.Rule{From: config.Selectors{"src/**"}, Deny: config.Selectors{"src/db/**"}, Allow: config.Selectors{"src/db/**", "^react$", "^@mui/", "^pg$"}},
+			files: nil,
+		},
+		{
+			name:  "from does not match",
+			rule:  config.Rule{From: config.Selectors{"lib/**"}, Deny: config.Selectors{"**"}},
+			files: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			e, err := rules.New([]config.Rule{tt.rule})
+			if err != nil {
+				t.Fatalf("New: %v", err)
+			}
+			got := e.Check(results, res)
+			if len(got) != len(tt.files) {
+				t.Fatalf("got %d violations (%+v), want %d", len(got), got, len(tt.files))
+			}
+			for i, v := range got {
+				if v.File != tt.files[i] || v.Import.Line != tt.lines[i] {
+					t.Errorf("violation %d = %s:%d, want %s:%d", i, v.File, v.Import.Line, tt.files[i], tt.lines[i])
+				}
+			}
+		})
+	}
+}
+
+func TestNew_DefaultName(t *testing.T) {
+	t.Parallel()
+
+	e, err := rules.New([]config.Rule{{From: config.Selectors{"a/**"}, Deny: config.Selectors{"^x$"}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	results := []scanner.FileImports{{File: "a/f.go", Lang: "go", Imports: []string{"x"}}}
+	res, err := graph.NewResolver(t.TempDir(), config.Layout{}, results)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := e.Check(results, res)
+	if len(got) != 1 || got[0].Rule != "rule 1 (a/**)" {
+		t.Errorf("got %+v, want one violation named %q", got, "rule 1 (a/**)")
+	}
+}
+
+func TestNew_InvalidSelector(t *testing.T) {
+	t.Parallel()
+
+	if _, err := rules.New([]config.Rule{{From: config.Selectors{"^[bad"}, Deny: config.Selectors{"x"}}}); err == nil {
+		t.Error("expected error for invalid selector")
+	}
+}
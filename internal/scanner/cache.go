@@ -0,0 +1,219 @@
+package scanner
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/gob"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/jtoloui/depviz/internal/config"
+)
+
+// cacheFormat is bumped whenever FileImports or the parsers change in a way
+// that makes old entries wrong, so they're dropped on the next run.
+const cacheFormat = 5
+
+// Cache stores per-file parse results on disk so unchanged files aren't
+// parsed again. An entry is reused when the file's size and mtime match, or
+// failing that when its content hash does. The whole cache is discarded when
+// its fingerprint (depviz version + config) differs.
+//
+// A nil *Cache is valid and caches nothing.
+type Cache struct {
+	path        string
+	fingerprint string
+
+	mu      sync.Mutex
+	entries map[string]cacheEntry
+	seen    map[string]bool
+	dirty   bool
+	hits    int
+	misses  int
+}
+
+type cacheEntry struct {
+	Size    int64
+	ModTime int64 // UnixNano
+	Hash    [sha256.Size]byte
+	Result  *FileImports // nil for files the parser skipped
+}
+
+type cacheFile struct {
+	Format      int
+	Fingerprint string
+	Entries     map[string]cacheEntry
+}
+
+// CacheFingerprint identifies what produced cached results: entries made by
+// another depviz version or under another config are never reused.
+func CacheFingerprint(version string, cfg *config.Config) string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%d\x00%s\x00", cacheFormat, version)
+	_ = json.NewEncoder(h).Encode(cfg)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// OpenCache loads the cache at path. A missing, unreadable or stale cache
+// file starts an empty cache rather than failing the scan.
+func OpenCache(path, fingerprint string) *Cache {
+	c := &Cache{
+		path:        path,
+		fingerprint: fingerprint,
+		entries:     map[string]cacheEntry{},
+		seen:        map[string]bool{},
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return c
+	}
+	var f cacheFile
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&f); err != nil {
+		return c
+	}
+	if f.Format == cacheFormat && f.Fingerprint == fingerprint && f.Entries != nil {
+		c.entries = f.Entries
+	}
+	return c
+}
+
+// Save writes the cache back if anything changed, dropping entries for files
+// that no scan visited since it was opened.
+func (c *Cache) Save() error {
+	if c == nil {
+		return nil
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for path := range c.entries {
+		if !c.seen[path] {
+			delete(c.entries, path)
+			c.dirty = true
+		}
+	}
+	if !c.dirty {
+		return nil
+	}
+
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(cacheFile{Format: cacheFormat, Fingerprint: c.fingerprint, Entries: c.entries}); err != nil {
+		return fmt.Errorf("encoding cache: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(c.path), 0o755); err != nil {
+		return err
+	}
+	// Write then rename, so an interrupted save never leaves a torn file.
+	tmp := c.path + ".tmp"
+	if err := os.WriteFile(tmp, buf.Bytes(), 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, c.path)
+}
+
+// Stats returns how many files were served from the cache and how many had
+// to be parsed.
+func (c *Cache) Stats() (hits, misses int) {
+	if c == nil {
+		return 0, 0
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.hits, c.misses
+}
+
+// RemoveCache deletes the cache file at path. It reports whether there was
+// one.
+func RemoveCache(path string) (bool, error) {
+	err := os.Remove(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// cacheSlot gives a scanner a Cache to parse through. Embed it to implement
+// Cacheable.
+type cacheSlot struct {
+	cache *Cache
+}
+
+// UseCache makes later Scan calls reuse results from c.
+func (s *cacheSlot) UseCache(c *Cache) { s.cache = c }
+
+// wrap returns parse with cache lookups in front of it. Safe for use by
+// walkAndParse's concurrent workers.
+func (c *Cache) wrap(parse parseFunc) parseFunc {
+	if c == nil {
+		return parse
+	}
+	return func(root, path string) (*FileImports, error) {
+		key, err := filepath.Rel(root, path)
+		if err != nil {
+			return parse(root, path)
+		}
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+		size, mtime := info.Size(), info.ModTime().UnixNano()
+
+		c.mu.Lock()
+		e, ok := c.entries[key]
+		c.seen[key] = true
+		c.mu.Unlock()
+		if ok && e.Size == size && e.ModTime == mtime {
+			return c.hit(key, e, false)
+		}
+
+		src, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		hash := sha256.Sum256(src)
+		if ok && e.Hash == hash {
+			// Touched but not changed (checkout, formatter no-op): refresh
+			// the stat fields so next time is a fast hit.
+			e.Size, e.ModTime = size, mtime
+			return c.hit(key, e, true)
+		}
+
+		fi, err := parse(root, path)
+		if err != nil {
+			return nil, err
+		}
+		c.mu.Lock()
+		c.entries[key] = cacheEntry{Size: size, ModTime: mtime, Hash: hash, Result: clone(fi)}
+		c.dirty = true
+		c.misses++
+		c.mu.Unlock()
+		return fi, nil
+	}
+}
+
+func (c *Cache) hit(key string, e cacheEntry, updated bool) (*FileImports, error) {
+	c.mu.Lock()
+	if updated {
+		c.entries[key] = e
+		c.dirty = true
+	}
+	c.hits++
+	c.mu.Unlock()
+	return clone(e.Result), nil
+}
+
+// clone copies a result so callers can't mutate what the cache holds.
+// Slices are shared: nothing downstream modifies them in place.
+func clone(fi *FileImports) *FileImports {
+	if fi == nil {
+		return nil
+	}
+	cp := *fi
+	return &cp
+}
@@ -7,45 +7,80 @@ import (
 	"go/token"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 
 	"github.com/jtoloui/depviz/internal/config"
 )
 
-var _ Scanner = (*GoScanner)(nil)
+var (
+	_ FileScanner = (*GoScanner)(nil)
+	_ Cacheable   = (*GoScanner)(nil)
+)
 
 type GoScanner struct {
-	cfg *config.Config
+	cfg   *config.Config
+	build buildContext
+	tests config.Tests
+	cacheSlot
 }
 
+// NewGoScanner returns a scanner for cfg. Unless cfg.Tests says otherwise,
+// _test.go files are skipped.
 func NewGoScanner(cfg *config.Config) *GoScanner {
-	return &GoScanner{cfg: cfg}
+	tests := cfg.Tests
+	if tests == config.TestsDefault {
+		tests = config.TestsExclude
+	}
+	return &GoScanner{cfg: cfg, build: newBuildContext(cfg.Build), tests: tests}
 }
 
 func (g *GoScanner) Scan(root string) ([]FileImports, error) {
-	skip := toSet(g.cfg.Exclude)
+	return walkAndParse(root, toSet(g.cfg.Exclude), keepTests(root, g.tests, isGoTest, includeGo), g.cache.wrap(g.parse))
+}
 
-	include := func(path string, info os.FileInfo) bool {
-		return !info.IsDir() &&
-			strings.HasSuffix(path, ".go") &&
-			!strings.HasSuffix(path, "_test.go")
-	}
+func (g *GoScanner) ScanFile(root, path string) (*FileImports, error) {
+	return parseOne(root, path, toSet(g.cfg.Exclude), keepTests(root, g.tests, isGoTest, includeGo), g.parse)
+}
+
+func (g *GoScanner) parse(root, path string) (*FileImports, error) {
+	return parseGoFile(root, path, g.build)
+}
+
+func includeGo(path string, info os.FileInfo) bool {
+	return !info.IsDir() && strings.HasSuffix(path, ".go")
+}
 
-	return walkAndParse(root, skip, include, parseGoFile)
+func isGoTest(rel string) bool {
+	return strings.HasSuffix(rel, "_test.go")
 }
 
-func parseGoFile(root, path string) (*FileImports, error) {
+// parseGoFile parses one file, or returns nil if bc skips it.
+func parseGoFile(root, path string, bc buildContext) (*FileImports, error) {
 	src, err := os.ReadFile(path)
 	if err != nil {
 		return nil, err
 	}
 
+	var build string
+	if bc.filter || bc.record {
+		if x := goConstraint(path, src); x != nil {
+			if bc.filter && !x.Eval(bc.holds) {
+				return nil, nil
+			}
+			if bc.record {
+				build = x.String()
+			}
+		}
+	}
+
 	fset := token.NewFileSet()
 	file, err := parser.ParseFile(fset, path, src, 0)
 	if err != nil {
 		return nil, err
 	}
 
+	used := selectors(file)
 	var imports []string
 	var details []ImportDetail
 	for _, imp := range file.Imports {
@@ -64,6 +99,12 @@ func parseGoFile(root, path string) (*FileImports, error) {
 				d.Alias = imp.Name.Name
 			}
 		}
+		switch d.Kind {
+		case ImportNamed:
+			d.Names = used[goPackageName(modPath)]
+		case ImportAlias:
+			d.Names = used[d.Alias]
+		}
 
 		start := fset.Position(imp.Pos()).Offset
 		end := fset.Position(imp.End()).Offset
@@ -89,8 +130,12 @@ func parseGoFile(root, path string) (*FileImports, error) {
 			for _, spec := range d.Specs {
 				switch s := spec.(type) {
 				case *ast.TypeSpec:
+					kind := ExportType
+					if _, ok := s.Type.(*ast.InterfaceType); ok {
+						kind = ExportInterface
+					}
 					exports = append(exports, ExportDetail{
-						Name: s.Name.Name, Kind: ExportType, Private: !s.Name.IsExported(),
+						Name: s.Name.Name, Kind: kind, Private: !s.Name.IsExported(),
 						Line: fset.Position(s.Pos()).Line,
 					})
 				case *ast.ValueSpec:
@@ -117,5 +162,63 @@ func parseGoFile(root, path string) (*FileImports, error) {
 	}
 
 	rel, _ := filepath.Rel(root, path)
-	return &FileImports{File: rel, Lang: "go", Imports: imports, Details: details, Exports: exports, Lines: bytes.Count(src, []byte{'\n'}) + 1}, nil
+	return &FileImports{File: rel, Lang: "go", Build: build, IsTest: isGoTest(rel), Imports: imports, Details: details, Exports: exports, Lines: bytes.Count(src, []byte{'\n'}) + 1}, nil
+}
+
+// selectors returns, for each package qualifier in file, the identifiers
+// selected from it (`fmt.Println` → "fmt": ["Println"]), sorted.
+func selectors(file *ast.File) map[string][]string {
+	seen := map[string]map[string]bool{}
+	ast.Inspect(file, func(n ast.Node) bool {
+		sel, ok := n.(*ast.SelectorExpr)
+		if !ok {
+			return true
+		}
+		// Package qualifiers are never resolved to a local object, so this
+		// skips field and method selections on variables.
+		if x, ok := sel.X.(*ast.Ident); ok && x.Obj == nil {
+			if seen[x.Name] == nil {
+				seen[x.Name] = map[string]bool{}
+			}
+			seen[x.Name][sel.Sel.Name] = true
+		}
+		return true
+	})
+	out := make(map[string][]string, len(seen))
+	for pkg, names := range seen {
+		for name := range names {
+			out[pkg] = append(out[pkg], name)
+		}
+		sort.Strings(out[pkg])
+	}
+	return out
+}
+
+// goPackageName guesses the name an unaliased import is referred to by: its
+// last path element, minus a major-version element or suffix and the usual
+// go-/-go decorations (gopkg.in/yaml.v3 → yaml, example.com/go-foo/v2 → foo).
+func goPackageName(importPath string) string {
+	parts := strings.Split(importPath, "/")
+	name := parts[len(parts)-1]
+	if len(parts) > 1 && isMajorVersion(name) {
+		name = parts[len(parts)-2]
+	}
+	if i := strings.Index(name, ".v"); i > 0 && isMajorVersion(name[i+1:]) {
+		name = name[:i]
+	}
+	name = strings.TrimPrefix(name, "go-")
+	name = strings.TrimSuffix(name, "-go")
+	return strings.ReplaceAll(name, "-", "_")
+}
+
+func isMajorVersion(s string) bool {
+	if len(s) < 2 || s[0] != 'v' {
+		return false
+	}
+	for _, r := range s[1:] {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
 }
@@ -0,0 +1,137 @@
+package scanner
+
+import (
+	"bufio"
+	"bytes"
+	"go/build"
+	"go/build/constraint"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/jtoloui/depviz/internal/config"
+)
+
+// unixOS are the GOOS values the "unix" build tag holds for.
+var unixOS = map[string]bool{
+	"aix": true, "android": true, "darwin": true, "dragonfly": true, "freebsd": true, "hurd": true,
+	"illumos": true, "ios": true, "linux": true, "netbsd": true, "openbsd": true, "solaris": true,
+}
+
+// buildContext decides which Go files a scan keeps, following cfg.Build.
+type buildContext struct {
+	filter bool // skip files whose constraint doesn't hold
+	record bool // keep every file, recording its constraint
+	goos   string
+	goarch string
+	tags   map[string]bool
+}
+
+func newBuildContext(b config.Build) buildContext {
+	c := buildContext{
+		filter: b.Filters(),
+		record: b.AllPlatforms,
+		goos:   b.GOOS,
+		goarch: b.GOARCH,
+		tags:   toSet(b.Tags),
+	}
+	if c.goos == "" {
+		c.goos = build.Default.GOOS
+	}
+	if c.goarch == "" {
+		c.goarch = build.Default.GOARCH
+	}
+	return c
+}
+
+// holds reports whether tag is satisfied, as `go build` would decide it:
+// GOOS, GOARCH, the compiler, the release tags of the running toolchain
+// and any --tags. "cgo" counts only when given as a tag.
+func (c buildContext) holds(tag string) bool {
+	switch {
+	case c.tags[tag], tag == c.goos, tag == c.goarch, tag == "gc":
+		return true
+	case tag == "unix":
+		return unixOS[c.goos]
+	case tag == "linux":
+		return c.goos == "android"
+	case tag == "solaris":
+		return c.goos == "illumos"
+	case tag == "darwin":
+		return c.goos == "ios"
+	}
+	return slices.Contains(build.Default.ReleaseTags, tag)
+}
+
+// goConstraint is the constraint on a Go file: the one its name implies
+// (`_windows.go`, `_linux_arm64.go`) and its //go:build line — or, in older
+// files, its // +build lines — joined with &&. It is nil for a file that
+// builds everywhere.
+func goConstraint(path string, src []byte) constraint.Expr {
+	return and(nameConstraint(filepath.Base(path)), lineConstraint(src))
+}
+
+// nameConstraint applies the file name rules of go/build: after stripping
+// the extension and a _test suffix, a name ending _GOOS, _GOARCH or
+// _GOOS_GOARCH builds only there. A name with no underscore before the
+// suffix, like linux.go, is unconstrained.
+func nameConstraint(name string) constraint.Expr {
+	name, _, _ = strings.Cut(name, ".")
+	i := strings.Index(name, "_")
+	if i < 0 {
+		return nil
+	}
+	parts := strings.Split(name[i:], "_")
+	if n := len(parts); n > 0 && parts[n-1] == "test" {
+		parts = parts[:n-1]
+	}
+	n := len(parts)
+	switch {
+	case n >= 2 && config.KnownOS[parts[n-2]] && config.KnownArch[parts[n-1]]:
+		return and(&constraint.TagExpr{Tag: parts[n-2]}, &constraint.TagExpr{Tag: parts[n-1]})
+	case n >= 1 && config.KnownOS[parts[n-1]]:
+		return &constraint.TagExpr{Tag: parts[n-1]}
+	case n >= 1 && config.KnownArch[parts[n-1]]:
+		return &constraint.TagExpr{Tag: parts[n-1]}
+	}
+	return nil
+}
+
+// lineConstraint reads the build constraint from the comments before the
+// package clause. A //go:build line wins; otherwise every // +build line
+// must hold. Malformed lines are ignored, as the compiler would reject the
+// file anyway.
+func lineConstraint(src []byte) constraint.Expr {
+	var plus constraint.Expr
+	s := bufio.NewScanner(bytes.NewReader(src))
+	for s.Scan() {
+		line := strings.TrimSpace(s.Text())
+		if line == "" {
+			continue
+		}
+		if !strings.HasPrefix(line, "//") {
+			break
+		}
+		switch {
+		case constraint.IsGoBuild(line):
+			if x, err := constraint.Parse(line); err == nil {
+				return x
+			}
+		case constraint.IsPlusBuild(line):
+			if x, err := constraint.Parse(line); err == nil {
+				plus = and(plus, x)
+			}
+		}
+	}
+	return plus
+}
+
+func and(x, y constraint.Expr) constraint.Expr {
+	switch {
+	case x == nil:
+		return y
+	case y == nil:
+		return x
+	}
+	return &constraint.AndExpr{X: x, Y: y}
+}
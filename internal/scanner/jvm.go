@@ -0,0 +1,166 @@
+package scanner
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/jtoloui/depviz/internal/config"
+)
+
+var (
+	_ FileScanner = (*JVMScanner)(nil)
+	_ Cacheable   = (*JVMScanner)(nil)
+)
+
+// JVM source patterns, matched against comment- and string-blanked source.
+// Kotlin makes the trailing semicolon optional, so statements are matched per
+// line instead.
+var (
+	jvmPackageRe = regexp.MustCompile(`(?m)^[ \t]*package\s+([\w.]+)`)
+	jvmImportRe  = regexp.MustCompile(`(?m)^[ \t]*import\s+(static\s+)?(\w+(?:\.\w+)*)(\.\*)?(?:\s+as\s+(\w+))?`)
+
+	// Top-level declarations only (column 0) — members of a class aren't
+	// part of the file's API surface.
+	javaTypeRe = regexp.MustCompile(`(?m)^(?:@\w+(?:\([^)]*\))?\s+)*((?:(?:public|protected|private|abstract|final|static|sealed|non-sealed|strictfp)\s+)*)(class|interface|enum|record|@interface)\s+(\w+)`)
+	ktDeclRe   = regexp.MustCompile(`(?m)^(?:@\w+(?:\([^)]*\))?\s+)*((?:(?:public|private|internal|protected|abstract|open|final|sealed|data|enum|annotation|inner|value|inline|suspend|tailrec|operator|infix|external|expect|actual)\s+)*)(class|interface|object|fun)\s+(?:<[^>]*>\s*)?(?:[\w<>?, ]+\.)?(\w+)`)
+)
+
+var (
+	javaSyntax   = lexSyntax{textBlocks: true}
+	kotlinSyntax = lexSyntax{nestedComments: true, textBlocks: true}
+)
+
+var jvmExportKinds = map[string]ExportKind{
+	"class": ExportClass, "enum": ExportClass, "record": ExportClass, "object": ExportClass,
+	"interface": ExportInterface, "@interface": ExportInterface,
+	"fun": ExportFunction,
+}
+
+// JVMScanner extracts package and import declarations from Java and Kotlin
+// files. Imports are a flat, line-oriented syntax in both languages, so it
+// works on the lexically-cleaned source rather than a full grammar.
+type JVMScanner struct {
+	cfg *config.Config
+	cacheSlot
+}
+
+func NewJVMScanner(cfg *config.Config) *JVMScanner {
+	return &JVMScanner{cfg: cfg}
+}
+
+func (j *JVMScanner) Scan(root string) ([]FileImports, error) {
+	return walkAndParse(root, toSet(j.cfg.Exclude), keepTests(root, j.cfg.Tests, isJVMTest, includeJVM), j.cache.wrap(parseJVMFile))
+}
+
+func (j *JVMScanner) ScanFile(root, path string) (*FileImports, error) {
+	return parseOne(root, path, toSet(j.cfg.Exclude), keepTests(root, j.cfg.Tests, isJVMTest, includeJVM), parseJVMFile)
+}
+
+func includeJVM(path string, info os.FileInfo) bool {
+	if info.IsDir() {
+		return false
+	}
+	switch filepath.Ext(path) {
+	case ".java", ".kt", ".kts":
+		return true
+	}
+	return false
+}
+
+// isJVMTest matches the Maven/Gradle test source set, src/test/.
+func isJVMTest(rel string) bool {
+	rel = filepath.ToSlash(rel)
+	return strings.HasPrefix(rel, "src/test/") || strings.Contains(rel, "/src/test/")
+}
+
+func parseJVMFile(root, path string) (*FileImports, error) {
+	src, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read %s: %w", path, err)
+	}
+
+	lang, syn, declRe := "java", javaSyntax, javaTypeRe
+	if filepath.Ext(path) != ".java" {
+		lang, syn, declRe = "kotlin", kotlinSyntax, ktDeclRe
+	}
+	clean := blankLiterals(src, syn)
+	lineAt := func(off int) int { return bytes.Count(src[:off], []byte{'\n'}) + 1 }
+
+	var pkg string
+	if m := jvmPackageRe.FindSubmatch(clean); m != nil {
+		pkg = string(m[1])
+	}
+
+	var imports []string
+	var details []ImportDetail
+	for _, m := range jvmImportRe.FindAllSubmatchIndex(clean, -1) {
+		d := jvmImportDetail(string(clean[m[4]:m[5]]), m[2] >= 0, m[6] >= 0)
+		if m[8] >= 0 {
+			d.Alias = string(clean[m[8]:m[9]])
+		}
+		d.Snippet = strings.TrimSpace(string(src[m[0]:m[1]]))
+		d.Line = lineAt(m[0])
+		imports = append(imports, d.Path)
+		details = append(details, d)
+	}
+
+	var exports []ExportDetail
+	for _, m := range declRe.FindAllSubmatchIndex(clean, -1) {
+		mods := strings.Fields(string(clean[m[2]:m[3]]))
+		exports = append(exports, ExportDetail{
+			Name:    string(clean[m[6]:m[7]]),
+			Kind:    jvmExportKinds[string(clean[m[4]:m[5]])],
+			Private: !jvmPublic(lang, mods),
+			Line:    lineAt(m[0]),
+		})
+	}
+
+	if len(imports) == 0 && len(exports) == 0 {
+		return nil, nil
+	}
+
+	rel, _ := filepath.Rel(root, path)
+	return &FileImports{File: rel, Lang: lang, Package: pkg, IsTest: isJVMTest(rel), Imports: imports, Details: details, Exports: exports, Lines: bytes.Count(src, []byte{'\n'}) + 1}, nil
+}
+
+// jvmImportDetail builds the detail for an import of name. Single-type
+// imports keep the fully qualified name as the path; wildcard imports point
+// at the package (or, for static imports, the class) they open up.
+//
+//	java.util.List              → java.util.List [List]
+//	java.util.*                 → java.util [*]
+//	static org.junit.Assert.*   → org.junit.Assert [*] (static)
+//	static java.lang.Math.max   → java.lang.Math [max] (static)
+func jvmImportDetail(name string, static, wildcard bool) ImportDetail {
+	kind := ImportNamed
+	if static {
+		kind = ImportStatic
+	}
+	if wildcard {
+		return ImportDetail{Path: name, Kind: kind, Names: []string{"*"}}
+	}
+	i := strings.LastIndex(name, ".")
+	if static && i > 0 {
+		return ImportDetail{Path: name[:i], Kind: kind, Names: []string{name[i+1:]}}
+	}
+	return ImportDetail{Path: name, Kind: kind, Names: []string{name[i+1:]}}
+}
+
+// jvmPublic applies each language's default visibility: Java declarations
+// are package-private unless marked public, Kotlin ones are public unless
+// marked otherwise.
+func jvmPublic(lang string, mods []string) bool {
+	for _, m := range mods {
+		switch m {
+		case "public":
+			return true
+		case "private", "protected", "internal":
+			return false
+		}
+	}
+	return lang == "kotlin"
+}
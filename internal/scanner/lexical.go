@@ -0,0 +1,131 @@
+package scanner
+
+import (
+	"bytes"
+	"unicode/utf8"
+)
+
+// lexSyntax describes the comment and string forms of a C-family language
+// that differ between the languages we scan lexically.
+type lexSyntax struct {
+	nestedComments bool // /* /* */ */ nests (Rust, Kotlin)
+	rawStrings     bool // r"…", r#"…"# (Rust)
+	textBlocks     bool // """…""" (Java, Kotlin)
+}
+
+// blankLiterals returns a copy of src with comments and the contents of
+// string and char literals replaced by spaces. Newlines are kept so byte
+// offsets and line numbers still line up with the original, and regexes run
+// over the result never match commented-out code or text inside strings.
+func blankLiterals(src []byte, syn lexSyntax) []byte {
+	out := bytes.Clone(src)
+	blank := func(from, to int) {
+		for i := from; i < to && i < len(out); i++ {
+			if out[i] != '\n' {
+				out[i] = ' '
+			}
+		}
+	}
+
+	for i := 0; i < len(src); {
+		switch {
+		case bytes.HasPrefix(src[i:], []byte("//")):
+			end := bytes.IndexByte(src[i:], '\n')
+			if end < 0 {
+				end = len(src) - i
+			}
+			blank(i, i+end)
+			i += end
+
+		case bytes.HasPrefix(src[i:], []byte("/*")):
+			depth, j := 1, i+2
+			for j < len(src) && depth > 0 {
+				switch {
+				case syn.nestedComments && bytes.HasPrefix(src[j:], []byte("/*")):
+					depth++
+					j += 2
+				case bytes.HasPrefix(src[j:], []byte("*/")):
+					depth--
+					j += 2
+				default:
+					j++
+				}
+			}
+			blank(i, j)
+			i = j
+
+		case syn.rawStrings && src[i] == 'r' && (i == 0 || !isIdentByte(src[i-1])) && rawStringStart(src[i:]) > 0:
+			hashes := rawStringStart(src[i:]) - 2
+			body := i + hashes + 2
+			closing := append([]byte{'"'}, bytes.Repeat([]byte{'#'}, hashes)...)
+			end := bytes.Index(src[body:], closing)
+			if end < 0 {
+				end = len(src) - body
+			}
+			blank(body, body+end)
+			i = body + end + len(closing)
+
+		case syn.textBlocks && bytes.HasPrefix(src[i:], []byte(`"""`)):
+			end := bytes.Index(src[i+3:], []byte(`"""`))
+			if end < 0 {
+				end = len(src) - i - 3
+			}
+			blank(i+3, i+3+end)
+			i += 3 + end + 3
+
+		case src[i] == '"':
+			j := i + 1
+			for j < len(src) && src[j] != '"' {
+				if src[j] == '\\' {
+					j++
+				}
+				j++
+			}
+			blank(i+1, j)
+			i = j + 1
+
+		case src[i] == '\'':
+			// Char literal ('x', '\n', 'é') or a lifetime ('a) — only the
+			// former has a closing quote right after one (escaped) char.
+			j := i + 1
+			if j < len(src) && src[j] == '\\' {
+				if end := bytes.IndexByte(src[j+1:], '\''); end >= 0 {
+					blank(i+1, j+1+end)
+					i = j + 2 + end
+					continue
+				}
+			}
+			_, size := utf8.DecodeRune(src[min(j, len(src)):])
+			if j+size < len(src) && src[j+size] == '\'' {
+				blank(j, j+size)
+				i = j + size + 1
+				continue
+			}
+			i++
+
+		default:
+			i++
+		}
+	}
+	return out
+}
+
+// rawStringStart reports the length of a raw string opener (r", r#", r##"…)
+// at the start of s, or 0 if there isn't one.
+func rawStringStart(s []byte) int {
+	if len(s) < 2 || s[0] != 'r' {
+		return 0
+	}
+	i := 1
+	for i < len(s) && s[i] == '#' {
+		i++
+	}
+	if i < len(s) && s[i] == '"' {
+		return i + 1
+	}
+	return 0
+}
+
+func isIdentByte(b byte) bool {
+	return b == '_' || b >= '0' && b <= '9' || b >= 'a' && b <= 'z' || b >= 'A' && b <= 'Z'
+}
@@ -1,8 +1,15 @@
 package scanner
 
-import "github.com/jtoloui/depviz/internal/config"
+import (
+	"path/filepath"
 
-var _ Scanner = (*MultiScanner)(nil)
+	"github.com/jtoloui/depviz/internal/config"
+)
+
+var (
+	_ FileScanner = (*MultiScanner)(nil)
+	_ Cacheable   = (*MultiScanner)(nil)
+)
 
 // MultiScanner delegates to GoScanner and TreeSitterScanner, merging results.
 type MultiScanner struct {
@@ -25,3 +32,15 @@ func (m *MultiScanner) Scan(root string) ([]FileImports, error) {
 	}
 	return append(goFiles, jsFiles...), nil
 }
+
+func (m *MultiScanner) ScanFile(root, path string) (*FileImports, error) {
+	if filepath.Ext(path) == ".go" {
+		return m.go_.ScanFile(root, path)
+	}
+	return m.js.ScanFile(root, path)
+}
+
+func (m *MultiScanner) UseCache(c *Cache) {
+	m.go_.UseCache(c)
+	m.js.UseCache(c)
+}
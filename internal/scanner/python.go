@@ -0,0 +1,246 @@
+package scanner
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"unsafe"
+
+	tree_sitter "github.com/tree-sitter/go-tree-sitter"
+	tree_sitter_python "github.com/tree-sitter/tree-sitter-python/bindings/go"
+
+	"github.com/jtoloui/depviz/internal/config"
+)
+
+var (
+	_ FileScanner = (*PythonScanner)(nil)
+	_ Cacheable   = (*PythonScanner)(nil)
+)
+
+// Captures import statements anywhere in the file — imports guarded by
+// try/except or inside functions are still dependencies.
+const pythonImportQuery = `
+(import_statement) @stmt
+(import_from_statement) @stmt
+(future_import_statement) @stmt
+`
+
+// PythonScanner parses Python files with tree-sitter.
+type PythonScanner struct {
+	cfg *config.Config
+	cacheSlot
+}
+
+func NewPythonScanner(cfg *config.Config) *PythonScanner {
+	return &PythonScanner{cfg: cfg}
+}
+
+func (p *PythonScanner) Scan(root string) ([]FileImports, error) {
+	q, err := tree_sitter.NewQuery(pythonLanguage(), pythonImportQuery)
+	if err != nil {
+		return nil, fmt.Errorf("query compile for python: %w", err)
+	}
+	defer q.Close()
+
+	return walkAndParse(root, toSet(p.cfg.Exclude), keepTests(root, p.cfg.Tests, isPythonTest, includePython), p.cache.wrap(func(root, path string) (*FileImports, error) {
+		return parsePythonFile(root, path, q)
+	}))
+}
+
+func (p *PythonScanner) ScanFile(root, path string) (*FileImports, error) {
+	return parseOne(root, path, toSet(p.cfg.Exclude), keepTests(root, p.cfg.Tests, isPythonTest, includePython), func(root, path string) (*FileImports, error) {
+		q, err := tree_sitter.NewQuery(pythonLanguage(), pythonImportQuery)
+		if err != nil {
+			return nil, fmt.Errorf("query compile for python: %w", err)
+		}
+		defer q.Close()
+		return parsePythonFile(root, path, q)
+	})
+}
+
+func includePython(path string, info os.FileInfo) bool {
+	return !info.IsDir() && (filepath.Ext(path) == ".py" || filepath.Ext(path) == ".pyi")
+}
+
+// isPythonTest matches pytest's default discovery: test_*.py, *_test.py,
+// plus conftest.py fixtures.
+func isPythonTest(rel string) bool {
+	stem := strings.TrimSuffix(filepath.Base(rel), filepath.Ext(rel))
+	return strings.HasPrefix(stem, "test_") || strings.HasSuffix(stem, "_test") || stem == "conftest"
+}
+
+func pythonLanguage() *tree_sitter.Language {
+	return tree_sitter.NewLanguage(unsafe.Pointer(tree_sitter_python.Language()))
+}
+
+func parsePythonFile(root, path string, query *tree_sitter.Query) (*FileImports, error) {
+	src, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read %s: %w", path, err)
+	}
+
+	parser := tree_sitter.NewParser()
+	defer parser.Close()
+	if err := parser.SetLanguage(pythonLanguage()); err != nil {
+		return nil, fmt.Errorf("set language for %s: %w", path, err)
+	}
+
+	tree := parser.Parse(src, nil)
+	defer tree.Close()
+
+	cursor := tree_sitter.NewQueryCursor()
+	defer cursor.Close()
+
+	var imports []string
+	var details []ImportDetail
+	matches := cursor.Matches(query, tree.RootNode(), src)
+	for m := matches.Next(); m != nil; m = matches.Next() {
+		for _, cap := range m.Captures {
+			for _, d := range pythonImportDetails(src, &cap.Node) {
+				imports = append(imports, d.Path)
+				details = append(details, d)
+			}
+		}
+	}
+
+	exports := extractPythonExports(src, tree.RootNode())
+
+	if len(imports) == 0 && len(exports) == 0 {
+		return nil, nil
+	}
+
+	rel, _ := filepath.Rel(root, path)
+	return &FileImports{File: rel, Lang: "python", IsTest: isPythonTest(rel), Imports: imports, Details: details, Exports: exports, Lines: bytes.Count(src, []byte{'\n'}) + 1}, nil
+}
+
+// pythonImportDetails turns one import statement into details. `import a, b`
+// yields one detail per module; `from x import a, b` yields one detail for x
+// with the imported names.
+func pythonImportDetails(src []byte, stmt *tree_sitter.Node) []ImportDetail {
+	snippet := nodeText(src, stmt)
+	line := int(stmt.StartPosition().Row) + 1
+
+	switch stmt.Kind() {
+	case "import_statement":
+		var out []ImportDetail
+		for _, n := range fieldChildren(stmt, "name") {
+			d := ImportDetail{Kind: ImportNamespace, Snippet: snippet, Line: line}
+			if n.Kind() == "aliased_import" {
+				d.Path = nodeText(src, n.ChildByFieldName("name"))
+				d.Alias = nodeText(src, n.ChildByFieldName("alias"))
+			} else {
+				d.Path = nodeText(src, n)
+			}
+			out = append(out, d)
+		}
+		return out
+
+	case "import_from_statement", "future_import_statement":
+		mod := "__future__"
+		if m := stmt.ChildByFieldName("module_name"); m != nil {
+			mod = nodeText(src, m)
+		}
+		d := ImportDetail{Path: mod, Kind: ImportNamed, Snippet: snippet, Line: line}
+		if hasChildKind(stmt, "wildcard_import") {
+			d.Names = []string{"*"}
+		}
+		for _, n := range fieldChildren(stmt, "name") {
+			d.Names = append(d.Names, nodeText(src, n))
+		}
+		return []ImportDetail{d}
+	}
+	return nil
+}
+
+// fieldChildren returns the children of n stored under field.
+func fieldChildren(n *tree_sitter.Node, field string) []*tree_sitter.Node {
+	var out []*tree_sitter.Node
+	for i := uint(0); i < n.ChildCount(); i++ {
+		if n.FieldNameForChild(uint32(i)) == field {
+			out = append(out, n.Child(i))
+		}
+	}
+	return out
+}
+
+// extractPythonExports collects top-level functions and classes. When the
+// module declares __all__, that list is the public API: definitions missing
+// from it are private, and listed names defined elsewhere (re-exported
+// imports) are reported as named exports. Otherwise the leading-underscore
+// convention decides.
+func extractPythonExports(src []byte, root *tree_sitter.Node) []ExportDetail {
+	var defs []ExportDetail
+	var all []ExportDetail
+	hasAll := false
+
+	for i := uint(0); i < root.ChildCount(); i++ {
+		node := root.Child(i)
+		line := int(node.StartPosition().Row) + 1
+		if node.Kind() == "decorated_definition" {
+			if def := node.ChildByFieldName("definition"); def != nil {
+				node = def
+			}
+		}
+
+		switch node.Kind() {
+		case "function_definition":
+			defs = append(defs, ExportDetail{Name: nodeText(src, node.ChildByFieldName("name")), Kind: ExportFunction, Line: line})
+		case "class_definition":
+			defs = append(defs, ExportDetail{Name: nodeText(src, node.ChildByFieldName("name")), Kind: ExportClass, Line: line})
+		case "expression_statement":
+			if names, ok := dunderAll(src, node); ok {
+				hasAll = true
+				for _, n := range names {
+					all = append(all, ExportDetail{Name: n, Kind: ExportNamed, Line: line})
+				}
+			}
+		}
+	}
+
+	if !hasAll {
+		for i := range defs {
+			defs[i].Private = strings.HasPrefix(defs[i].Name, "_")
+		}
+		return defs
+	}
+
+	public := map[string]bool{}
+	for _, e := range all {
+		public[e.Name] = true
+	}
+	defined := map[string]bool{}
+	for i := range defs {
+		defs[i].Private = !public[defs[i].Name]
+		defined[defs[i].Name] = true
+	}
+	for _, e := range all {
+		if !defined[e.Name] {
+			defs = append(defs, e)
+		}
+	}
+	return defs
+}
+
+// dunderAll extracts the string entries of a top-level
+// `__all__ = [...]` (or tuple) assignment.
+func dunderAll(src []byte, stmt *tree_sitter.Node) ([]string, bool) {
+	assign := childByKind(stmt, "assignment")
+	if assign == nil {
+		return nil, false
+	}
+	left := assign.ChildByFieldName("left")
+	right := assign.ChildByFieldName("right")
+	if left == nil || right == nil || nodeText(src, left) != "__all__" {
+		return nil, false
+	}
+
+	var names []string
+	for i := uint(0); i < right.NamedChildCount(); i++ {
+		if s := right.NamedChild(i); s.Kind() == "string" {
+			names = append(names, strings.Trim(nodeText(src, s), `"'`))
+		}
+	}
+	return names, true
+}
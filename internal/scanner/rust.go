@@ -0,0 +1,196 @@
+package scanner
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/jtoloui/depviz/internal/config"
+)
+
+var (
+	_ FileScanner = (*RustScanner)(nil)
+	_ Cacheable   = (*RustScanner)(nil)
+)
+
+// Rust item patterns, matched against source with comments and string
+// contents blanked out so commented-out code and string literals never
+// match. `use` trees never contain semicolons, so [^;]+ spans multi-line
+// groups.
+var (
+	rustUseRe    = regexp.MustCompile(`\buse\s+([^;]+);`)
+	rustModRe    = regexp.MustCompile(`\bmod\s+([A-Za-z_][A-Za-z0-9_]*)\s*;`)
+	rustExternRe = regexp.MustCompile(`\bextern\s+crate\s+([A-Za-z_][A-Za-z0-9_]*)(?:\s+as\s+([A-Za-z_][A-Za-z0-9_]*))?\s*;`)
+	rustItemRe   = regexp.MustCompile(`(?m)^(pub(?:\s*\([^)]*\))?\s+)?(?:(?:async|const|unsafe|extern(?:\s+"[^"]*")?)\s+)*(fn|struct|enum|union|trait|type|const|static)\s+([A-Za-z_][A-Za-z0-9_]*)`)
+)
+
+var rustSyntax = lexSyntax{nestedComments: true, rawStrings: true}
+
+var rustExportKinds = map[string]ExportKind{
+	"fn": ExportFunction, "struct": ExportType, "enum": ExportType, "union": ExportType,
+	"trait": ExportInterface, "type": ExportType, "const": ExportConst, "static": ExportVar,
+}
+
+// RustScanner extracts `use`, `mod` and `extern crate` items from Rust
+// files. There is no tree-sitter Rust grammar in our dependency set, so it
+// works on the lexically-cleaned source instead.
+type RustScanner struct {
+	cfg *config.Config
+	cacheSlot
+}
+
+func NewRustScanner(cfg *config.Config) *RustScanner {
+	return &RustScanner{cfg: cfg}
+}
+
+func (r *RustScanner) Scan(root string) ([]FileImports, error) {
+	return walkAndParse(root, toSet(r.cfg.Exclude), keepTests(root, r.cfg.Tests, isRustTest, includeRust), r.cache.wrap(parseRustFile))
+}
+
+func (r *RustScanner) ScanFile(root, path string) (*FileImports, error) {
+	return parseOne(root, path, toSet(r.cfg.Exclude), keepTests(root, r.cfg.Tests, isRustTest, includeRust), parseRustFile)
+}
+
+func includeRust(path string, info os.FileInfo) bool {
+	return !info.IsDir() && filepath.Ext(path) == ".rs"
+}
+
+// isRustTest matches Cargo's integration tests under tests/. Unit tests
+// live in #[cfg(test)] modules inside the code they test, so they can't be
+// told apart by file.
+func isRustTest(rel string) bool {
+	return inDir(rel, "tests")
+}
+
+func parseRustFile(root, path string) (*FileImports, error) {
+	src, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read %s: %w", path, err)
+	}
+	clean := blankLiterals(src, rustSyntax)
+
+	type found struct {
+		at int
+		d  []ImportDetail
+	}
+	var all []found
+	lineAt := func(off int) int { return bytes.Count(src[:off], []byte{'\n'}) + 1 }
+	snippet := func(m []int) string { return string(src[m[0]:m[1]]) }
+
+	for _, m := range rustUseRe.FindAllSubmatchIndex(clean, -1) {
+		tree := strings.Join(strings.Fields(string(clean[m[2]:m[3]])), " ")
+		ds := parseUseTree(tree)
+		for i := range ds {
+			ds[i].Snippet = snippet(m)
+			ds[i].Line = lineAt(m[0])
+		}
+		all = append(all, found{m[0], ds})
+	}
+	for _, m := range rustModRe.FindAllSubmatchIndex(clean, -1) {
+		d := ImportDetail{Path: "self::" + string(clean[m[2]:m[3]]), Kind: ImportMod, Snippet: snippet(m), Line: lineAt(m[0])}
+		all = append(all, found{m[0], []ImportDetail{d}})
+	}
+	for _, m := range rustExternRe.FindAllSubmatchIndex(clean, -1) {
+		d := ImportDetail{Path: string(clean[m[2]:m[3]]), Kind: ImportExternCrate, Snippet: snippet(m), Line: lineAt(m[0])}
+		if m[4] >= 0 {
+			d.Alias = string(clean[m[4]:m[5]])
+		}
+		all = append(all, found{m[0], []ImportDetail{d}})
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].at < all[j].at })
+
+	var imports []string
+	var details []ImportDetail
+	for _, f := range all {
+		for _, d := range f.d {
+			imports = append(imports, d.Path)
+			details = append(details, d)
+		}
+	}
+
+	var exports []ExportDetail
+	for _, m := range rustItemRe.FindAllSubmatchIndex(clean, -1) {
+		vis := strings.TrimSpace(string(clean[max(m[2], 0):max(m[3], 0)]))
+		exports = append(exports, ExportDetail{
+			Name:    string(clean[m[6]:m[7]]),
+			Kind:    rustExportKinds[string(clean[m[4]:m[5]])],
+			Private: vis != "pub",
+			Line:    lineAt(m[0]),
+		})
+	}
+
+	if len(imports) == 0 && len(exports) == 0 {
+		return nil, nil
+	}
+
+	rel, _ := filepath.Rel(root, path)
+	return &FileImports{File: rel, Lang: "rust", IsTest: isRustTest(rel), Imports: imports, Details: details, Exports: exports, Lines: bytes.Count(src, []byte{'\n'}) + 1}, nil
+}
+
+// parseUseTree turns a whitespace-normalised use tree into details. The
+// path is the module being imported from and Names the items taken from it:
+//
+//	std::io::Read          → std::io [Read]
+//	std::{fmt, io::Write}  → std [fmt io::Write]
+//	serde as s             → serde (alias s)
+//	crate::db::*           → crate::db [*]
+func parseUseTree(tree string) []ImportDetail {
+	tree = strings.TrimPrefix(strings.TrimSpace(tree), "::")
+
+	if open := strings.Index(tree, "{"); open >= 0 {
+		prefix := strings.TrimSuffix(strings.TrimSpace(tree[:open]), "::")
+		inner := strings.TrimSuffix(strings.TrimSpace(tree[open+1:]), "}")
+		items := splitTopLevel(inner)
+		if prefix == "" {
+			// `use {a, b::c};` — each item is its own tree.
+			var out []ImportDetail
+			for _, it := range items {
+				out = append(out, parseUseTree(it)...)
+			}
+			return out
+		}
+		return []ImportDetail{{Path: prefix, Kind: ImportNamed, Names: items}}
+	}
+
+	if strings.HasSuffix(tree, "::*") {
+		return []ImportDetail{{Path: strings.TrimSuffix(tree, "::*"), Kind: ImportNamed, Names: []string{"*"}}}
+	}
+
+	path, alias, _ := strings.Cut(tree, " as ")
+	i := strings.LastIndex(path, "::")
+	if i < 0 {
+		return []ImportDetail{{Path: path, Kind: ImportNamespace, Alias: alias}}
+	}
+	return []ImportDetail{{Path: path[:i], Kind: ImportNamed, Names: []string{tree[i+2:]}}}
+}
+
+// splitTopLevel splits a use group on commas that aren't inside nested
+// braces, dropping empty entries left by trailing commas.
+func splitTopLevel(s string) []string {
+	var out []string
+	depth, start := 0, 0
+	flush := func(end int) {
+		if it := strings.TrimSpace(s[start:end]); it != "" {
+			out = append(out, it)
+		}
+	}
+	for i, c := range s {
+		switch c {
+		case '{':
+			depth++
+		case '}':
+			depth--
+		case ',':
+			if depth == 0 {
+				flush(i)
+				start = i + 1
+			}
+		}
+	}
+	flush(len(s))
+	return out
+}
@@ -17,6 +17,11 @@ const (
 	ImportBlank ImportKind = "blank"
 	ImportDot   ImportKind = "dot"
 	ImportAlias ImportKind = "alias"
+	// Rust-specific
+	ImportMod         ImportKind = "mod"
+	ImportExternCrate ImportKind = "extern-crate"
+	// Java/Kotlin-specific
+	ImportStatic ImportKind = "static"
 )
 
 // ImportDetail captures what is imported from a module.
@@ -56,6 +61,9 @@ type ExportDetail struct {
 type FileImports struct {
 	File    string         `json:"file"`
 	Lang    string         `json:"-"`
+	Package string         `json:"package,omitempty"` // declared package, for languages where it isn't the directory (Java, Kotlin)
+	Build   string         `json:"build,omitempty"`   // Go build constraint, e.g. "linux && amd64", recorded by all-platforms scans
+	IsTest  bool           `json:"test,omitempty"`    // test file by the language's convention (_test.go, *.test.ts, test_*.py, ...)
 	Imports []string       `json:"imports"`
 	Details []ImportDetail `json:"details,omitempty"`
 	Exports []ExportDetail `json:"exports,omitempty"`
@@ -67,6 +75,21 @@ type Scanner interface {
 	Scan(root string) ([]FileImports, error)
 }
 
+// Cacheable is a Scanner that can serve unchanged files from a Cache
+// instead of parsing them again.
+type Cacheable interface {
+	Scanner
+	UseCache(c *Cache)
+}
+
+// FileScanner is a Scanner that can also re-parse a single file, for
+// incremental rescans. ScanFile returns nil for files the scanner doesn't
+// handle or that Scan would have excluded.
+type FileScanner interface {
+	Scanner
+	ScanFile(root, path string) (*FileImports, error)
+}
+
 // toSet converts a string slice to a map for O(1) lookups.
 func toSet(ss []string) map[string]bool {
 	m := make(map[string]bool, len(ss))
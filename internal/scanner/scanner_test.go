@@ -5,7 +5,9 @@ import (
 	"os"
 	"path/filepath"
 	"sort"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/jtoloui/depviz/internal/config"
 	"github.com/jtoloui/depviz/internal/scanner"
@@ -199,9 +201,10 @@ export const MAX = 100;
 export type ID = string;
 export interface Props { name: string; }
 export default function main() {}
-export { helper, util };
-export { foo } from './foo';
+export { helper, util as utility };
+export { foo, baz as qux } from './foo';
 export * from './bar';
+export * as ns from './ns';
 `)
 
 	cfg := &config.Config{Language: "js", Exclude: []string{".git"}}
@@ -226,9 +229,11 @@ export * from './bar';
 		{"Props", scanner.ExportInterface},
 		{"main", scanner.ExportDefault},
 		{"helper", scanner.ExportNamed},
-		{"util", scanner.ExportNamed},
+		{"utility", scanner.ExportNamed},
 		{"foo", scanner.ExportReExport},
+		{"qux", scanner.ExportReExport},
 		{"* from ./bar", scanner.ExportReExport},
+		{"ns", scanner.ExportReExport},
 	}
 
 	if len(exports) != len(wantExports) {
@@ -427,6 +432,123 @@ func TestTreeSitterScanner_EmptyFile(t *testing.T) {
 	}
 }
 
+func TestTreeSitterScanner_Components(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		file      string
+		src       string
+		wantPaths []string
+		wantLines []int
+		wantKinds []scanner.ImportKind
+		exports   []string
+	}{
+		{
+			name: "vue script and script setup",
+			file: "Card.vue",
+			src: `<template>
+  <p>import nope from 'template-text'</p>
+  <Button />
+</template>
+
+<!-- <script>import gone from 'commented-out'</script> -->
+<script lang="ts">
+export default defineComponent({ name: 'Card' })
+</script>
+
+<script setup lang="ts">
+import Button from './Button.vue'
+import type { Props } from './types'
+</script>
+`,
+			wantPaths: []string{"./Button.vue", "./types"},
+			wantLines: []int{12, 13},
+			wantKinds: []scanner.ImportKind{scanner.ImportDefault, scanner.ImportType},
+			exports:   []string{"default"},
+		},
+		{
+			name: "svelte module and instance scripts",
+			file: "Nav.svelte",
+			src: `<script context="module">
+  export { load } from './api'
+</script>
+
+<script lang="ts">
+  import { onMount } from 'svelte'
+</script>
+
+<script type="application/ld+json">{"import": "data"}</script>
+
+<nav>{#each items as item}{item}{/each}</nav>
+`,
+			wantPaths: []string{"./api", "svelte"},
+			wantLines: []int{2, 6},
+			wantKinds: []scanner.ImportKind{scanner.ImportReExport, scanner.ImportNamed},
+			exports:   []string{"load"},
+		},
+		{
+			name: "astro frontmatter and client script",
+			file: "index.astro",
+			src: `---
+import Layout from '../layouts/Layout.astro'
+const { title } = Astro.props as { title: string }
+---
+<Layout title={title}>
+  <h1>import x from 'markup'</h1>
+</Layout>
+<script>
+  import '../scripts/menu'
+</script>
+`,
+			wantPaths: []string{"../layouts/Layout.astro", "../scripts/menu"},
+			wantLines: []int{2, 9},
+			wantKinds: []scanner.ImportKind{scanner.ImportDefault, scanner.ImportSideEffect},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			dir := t.TempDir()
+			writeFile(t, filepath.Join(dir, tt.file), tt.src)
+
+			cfg := &config.Config{Language: "js", Exclude: []string{".git"}}
+			results, err := scanner.NewTreeSitterScanner(cfg).Scan(dir)
+			if err != nil {
+				t.Fatalf("Scan: %v", err)
+			}
+			if len(results) != 1 {
+				t.Fatalf("got %d files, want 1", len(results))
+			}
+			f := results[0]
+
+			if f.File != tt.file || f.Lang != "js" {
+				t.Errorf("File, Lang = %q, %q, want %q, js", f.File, f.Lang, tt.file)
+			}
+			if !slicesEqual(f.Imports, tt.wantPaths) {
+				t.Fatalf("Imports = %v, want %v", f.Imports, tt.wantPaths)
+			}
+			for i, d := range f.Details {
+				if d.Line != tt.wantLines[i] || d.Kind != tt.wantKinds[i] {
+					t.Errorf("Details[%d] = line %d %q, want line %d %q", i, d.Line, d.Kind, tt.wantLines[i], tt.wantKinds[i])
+				}
+			}
+			var exports []string
+			for _, e := range f.Exports {
+				exports = append(exports, e.Name)
+			}
+			if !slicesEqual(exports, tt.exports) {
+				t.Errorf("Exports = %v, want %v", exports, tt.exports)
+			}
+			if want := strings.Count(tt.src, "\n") + 1; f.Lines != want {
+				t.Errorf("Lines = %d, want %d", f.Lines, want)
+			}
+		})
+	}
+}
+
 func TestGoScanner_ExportsAndDetails(t *testing.T) {
 	t.Parallel()
 
@@ -444,6 +566,7 @@ func Exported() {}
 func unexported() {}
 type MyType struct{}
 type myPrivate struct{}
+type Reader interface{ Read() }
 const MaxRetries = 3
 const internal = 5
 var GlobalVar = "x"
@@ -503,6 +626,7 @@ var localVar = "y"
 		{"unexported", scanner.ExportFunction, true},
 		{"MyType", scanner.ExportType, false},
 		{"myPrivate", scanner.ExportType, true},
+		{"Reader", scanner.ExportInterface, false},
 		{"MaxRetries", scanner.ExportConst, false},
 		{"internal", scanner.ExportConst, true},
 		{"GlobalVar", scanner.ExportVar, false},
@@ -524,6 +648,49 @@ var localVar = "y"
 	}
 }
 
+func TestGoScanner_SelectorNames(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "main.go"), `package main
+
+import (
+	"fmt"
+	"os"
+	yml "gopkg.in/yaml.v3"
+	"github.com/spf13/cobra/v2"
+)
+
+type cli struct{ os string }
+
+func main() {
+	c := cli{}
+	fmt.Println(c.os, os.Args)
+	fmt.Printf("%v", yml.Marshal)
+	_ = cobra.Command{}
+	os.Exit(0)
+}
+`)
+
+	cfg := &config.Config{Language: "go", Exclude: []string{".git"}}
+	results, err := scanner.NewGoScanner(cfg).Scan(dir)
+	if err != nil {
+		t.Fatalf("Scan: %v", err)
+	}
+
+	want := map[string][]string{
+		"fmt":                       {"Printf", "Println"},
+		"os":                        {"Args", "Exit"},
+		"gopkg.in/yaml.v3":          {"Marshal"},
+		"github.com/spf13/cobra/v2": {"Command"},
+	}
+	for _, d := range results[0].Details {
+		if !slicesEqual(d.Names, want[d.Path]) {
+			t.Errorf("%s names = %v, want %v", d.Path, d.Names, want[d.Path])
+		}
+	}
+}
+
 func TestGoScanner_LineCount(t *testing.T) {
 	t.Parallel()
 
@@ -575,6 +742,145 @@ func Free() {}
 	}
 }
 
+func TestGoScanner_BuildConstraints(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	for name, src := range map[string]string{
+		"main.go":             "package main\n\nimport \"fmt\"\n",
+		"sys_windows.go":      "package main\n\nimport \"syscall\"\n",
+		"sys_linux_arm64.go":  "package main\n\nimport \"syscall\"\n",
+		"linux.go":            "package main\n\nimport \"os\"\n",
+		"unix.go":             "//go:build unix && !integration\n\npackage main\n\nimport \"os\"\n",
+		"integration.go":      "// Copyright.\n\n//go:build integration\n\npackage main\n\nimport \"testing\"\n",
+		"legacy.go":           "// +build darwin\n// +build amd64\n\npackage main\n\nimport \"os\"\n",
+		"not_a_constraint.go": "package main\n\n//go:build windows\nimport \"os\"\n",
+	} {
+		writeFile(t, filepath.Join(dir, name), src)
+	}
+
+	tests := []struct {
+		name  string
+		build config.Build
+		want  []string // file[build]
+	}{
+		{"no settings", config.Build{}, []string{
+			"integration.go", "legacy.go", "linux.go", "main.go", "not_a_constraint.go", "sys_linux_arm64.go", "sys_windows.go", "unix.go",
+		}},
+		{"linux/arm64", config.Build{GOOS: "linux", GOARCH: "arm64"}, []string{
+			"linux.go", "main.go", "not_a_constraint.go", "sys_linux_arm64.go", "unix.go",
+		}},
+		{"windows with tag", config.Build{GOOS: "windows", GOARCH: "amd64", Tags: []string{"integration"}}, []string{
+			"integration.go", "linux.go", "main.go", "not_a_constraint.go", "sys_windows.go",
+		}},
+		{"darwin/amd64", config.Build{GOOS: "darwin", GOARCH: "amd64"}, []string{
+			"legacy.go", "linux.go", "main.go", "not_a_constraint.go", "unix.go",
+		}},
+		{"all platforms", config.Build{AllPlatforms: true}, []string{
+			"integration.go[integration]", "legacy.go[darwin && amd64]", "linux.go", "main.go", "not_a_constraint.go",
+			"sys_linux_arm64.go[linux && arm64]", "sys_windows.go[windows]", "unix.go[unix && !integration]",
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			results, err := scanner.NewGoScanner(&config.Config{Language: "go", Build: tt.build}).Scan(dir)
+			if err != nil {
+				t.Fatalf("Scan: %v", err)
+			}
+			var got []string
+			for _, fi := range results {
+				f := fi.File
+				if fi.Build != "" {
+					f += "[" + fi.Build + "]"
+				}
+				got = append(got, f)
+			}
+			sort.Strings(got)
+			if !slicesEqual(got, tt.want) {
+				t.Errorf("files = %v\nwant %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScanners_Tests(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	for name, src := range map[string]string{
+		"svc/svc.go":                  "package svc\n\nimport \"fmt\"\n",
+		"svc/svc_test.go":             "package svc\n\nimport \"testing\"\n",
+		"svc/export_test.go":          "package svc_test\n\nimport \"github.com/stretchr/testify/assert\"\n",
+		"web/app.ts":                  "import x from 'x';\n",
+		"web/app.test.ts":             "import { it } from 'vitest';\n",
+		"web/app.spec.tsx":            "import { render } from '@testing-library/react';\n",
+		"web/__tests__/util.ts":       "import y from 'y';\n",
+		"py/app.py":                   "import os\n",
+		"py/test_app.py":              "import pytest\n",
+		"py/conftest.py":              "import pytest\n",
+		"crate/src/lib.rs":            "use std::fmt;\n",
+		"crate/tests/it.rs":           "use crate_name::thing;\n",
+		"jvm/src/main/java/App.java":  "package app;\nimport java.util.List;\n",
+		"jvm/src/test/java/AppT.java": "package app;\nimport org.junit.Test;\n",
+	} {
+		writeFile(t, filepath.Join(dir, filepath.FromSlash(name)), src)
+	}
+
+	tests := []struct {
+		name  string
+		scan  func(*config.Config) scanner.Scanner
+		tests config.Tests
+		want  []string // file, with * for test files
+	}{
+		{"go default", func(c *config.Config) scanner.Scanner { return scanner.NewGoScanner(c) }, config.TestsDefault, []string{
+			"svc/svc.go",
+		}},
+		{"go include", func(c *config.Config) scanner.Scanner { return scanner.NewGoScanner(c) }, config.TestsInclude, []string{
+			"svc/export_test.go*", "svc/svc.go", "svc/svc_test.go*",
+		}},
+		{"go only", func(c *config.Config) scanner.Scanner { return scanner.NewGoScanner(c) }, config.TestsOnly, []string{
+			"svc/export_test.go*", "svc/svc_test.go*",
+		}},
+		{"js default", func(c *config.Config) scanner.Scanner { return scanner.NewTreeSitterScanner(c) }, config.TestsDefault, []string{
+			"web/__tests__/util.ts*", "web/app.spec.tsx*", "web/app.test.ts*", "web/app.ts",
+		}},
+		{"js exclude", func(c *config.Config) scanner.Scanner { return scanner.NewTreeSitterScanner(c) }, config.TestsExclude, []string{
+			"web/app.ts",
+		}},
+		{"python only", func(c *config.Config) scanner.Scanner { return scanner.NewPythonScanner(c) }, config.TestsOnly, []string{
+			"py/conftest.py*", "py/test_app.py*",
+		}},
+		{"rust default", func(c *config.Config) scanner.Scanner { return scanner.NewRustScanner(c) }, config.TestsDefault, []string{
+			"crate/src/lib.rs", "crate/tests/it.rs*",
+		}},
+		{"jvm exclude", func(c *config.Config) scanner.Scanner { return scanner.NewJVMScanner(c) }, config.TestsExclude, []string{
+			"jvm/src/main/java/App.java",
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			results, err := tt.scan(&config.Config{Tests: tt.tests}).Scan(dir)
+			if err != nil {
+				t.Fatalf("Scan: %v", err)
+			}
+			var got []string
+			for _, fi := range results {
+				f := filepath.ToSlash(fi.File)
+				if fi.IsTest {
+					f += "*"
+				}
+				got = append(got, f)
+			}
+			sort.Strings(got)
+			if !slicesEqual(got, tt.want) {
+				t.Errorf("files = %v\nwant %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestWalkAndParse_SkipDirs(t *testing.T) {
 	t.Parallel()
 
@@ -743,6 +1049,514 @@ func main() {}
 	}
 }
 
+func TestMultiScanner_ScanFile(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "main.go"), "package main\n\nimport \"fmt\"\n\nfunc main() { fmt.Println() }\n")
+	writeFile(t, filepath.Join(dir, "main_test.go"), "package main\n\nimport \"testing\"\n")
+	writeFile(t, filepath.Join(dir, "src", "app.ts"), "import express from 'express';\n")
+	writeFile(t, filepath.Join(dir, "node_modules", "x", "index.js"), "import y from 'y';\n")
+	writeFile(t, filepath.Join(dir, "README.md"), "# hi\n")
+
+	cfg := &config.Config{Language: "multi", Exclude: []string{"node_modules"}}
+	s := scanner.NewMultiScanner(cfg)
+
+	full, err := s.Scan(dir)
+	if err != nil {
+		t.Fatalf("Scan: %v", err)
+	}
+	byFile := map[string]scanner.FileImports{}
+	for _, fi := range full {
+		byFile[fi.File] = fi
+	}
+
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{"main.go", true},
+		{filepath.Join("src", "app.ts"), true},
+		{"main_test.go", false},
+		{filepath.Join("node_modules", "x", "index.js"), false},
+		{"README.md", false},
+	}
+	for _, tt := range tests {
+		fi, err := s.ScanFile(dir, filepath.Join(dir, tt.path))
+		if err != nil {
+			t.Fatalf("ScanFile(%s): %v", tt.path, err)
+		}
+		if (fi != nil) != tt.want {
+			t.Errorf("ScanFile(%s) = %v, want result: %v", tt.path, fi, tt.want)
+			continue
+		}
+		if fi != nil && !slicesEqual(fi.Imports, byFile[tt.path].Imports) {
+			t.Errorf("ScanFile(%s).Imports = %v, Scan gave %v", tt.path, fi.Imports, byFile[tt.path].Imports)
+		}
+	}
+
+	if _, err := s.ScanFile(dir, filepath.Join(dir, "missing.go")); err == nil {
+		t.Error("expected error for missing file")
+	}
+}
+
+func TestCache(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "main.go"), "package main\n\nimport \"fmt\"\n\nfunc main() { fmt.Println() }\n")
+	writeFile(t, filepath.Join(dir, "app.ts"), "import express from 'express';\nexport const app = express();\n")
+	writeFile(t, filepath.Join(dir, "util.ts"), "import path from 'path';\n")
+	cachePath := filepath.Join(dir, ".depviz", "scan-cache.gob")
+	cfg := &config.Config{Language: "multi", Exclude: []string{".depviz"}}
+
+	scan := func(fingerprint string) ([]scanner.FileImports, int, int) {
+		t.Helper()
+		c := scanner.OpenCache(cachePath, fingerprint)
+		s := scanner.NewMultiScanner(cfg)
+		s.UseCache(c)
+		results, err := s.Scan(dir)
+		if err != nil {
+			t.Fatalf("Scan: %v", err)
+		}
+		if err := c.Save(); err != nil {
+			t.Fatalf("Save: %v", err)
+		}
+		hits, misses := c.Stats()
+		return results, hits, misses
+	}
+
+	if _, hits, misses := scan("v1"); hits != 0 || misses != 3 {
+		t.Errorf("cold: hits=%d misses=%d, want 0/3", hits, misses)
+	}
+	warm, hits, misses := scan("v1")
+	if hits != 3 || misses != 0 {
+		t.Errorf("warm: hits=%d misses=%d, want 3/0", hits, misses)
+	}
+	for _, fi := range warm {
+		if fi.Lang == "" || len(fi.Imports) == 0 || len(fi.Details) == 0 {
+			t.Errorf("cached result lost data: %+v", fi)
+		}
+	}
+
+	// Same content with a new mtime is still a hit (content hash); changed
+	// content is reparsed.
+	future := time.Now().Add(time.Hour)
+	if err := os.Chtimes(filepath.Join(dir, "util.ts"), future, future); err != nil {
+		t.Fatal(err)
+	}
+	writeFile(t, filepath.Join(dir, "app.ts"), "import koa from 'koa';\n")
+	results, hits, misses := scan("v1")
+	if hits != 2 || misses != 1 {
+		t.Errorf("after edit: hits=%d misses=%d, want 2/1", hits, misses)
+	}
+	for _, fi := range results {
+		if fi.File == "app.ts" && fi.Imports[0] != "koa" {
+			t.Errorf("app.ts served stale imports %v", fi.Imports)
+		}
+	}
+
+	// A different fingerprint (version or config) invalidates everything.
+	if _, hits, _ := scan("v2"); hits != 0 {
+		t.Errorf("new fingerprint: hits=%d, want 0", hits)
+	}
+
+	// Deleted files are pruned rather than served.
+	if err := os.Remove(filepath.Join(dir, "util.ts")); err != nil {
+		t.Fatal(err)
+	}
+	if results, _, _ := scan("v2"); len(results) != 2 {
+		t.Errorf("got %d files after delete, want 2", len(results))
+	}
+
+	removed, err := scanner.RemoveCache(cachePath)
+	if err != nil || !removed {
+		t.Errorf("RemoveCache = %v, %v; want true, nil", removed, err)
+	}
+	if removed, _ := scanner.RemoveCache(cachePath); removed {
+		t.Error("second RemoveCache should report nothing removed")
+	}
+}
+
+func TestCache_CorruptFile(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "main.go"), "package main\n\nimport \"fmt\"\n\nfunc main() { fmt.Println() }\n")
+	cachePath := filepath.Join(dir, "cache.gob")
+	writeFile(t, cachePath, "not a gob")
+
+	c := scanner.OpenCache(cachePath, "v1")
+	s := scanner.NewGoScanner(&config.Config{Language: "go"})
+	s.UseCache(c)
+	if _, err := s.Scan(dir); err != nil {
+		t.Fatalf("Scan with corrupt cache: %v", err)
+	}
+	if _, misses := c.Stats(); misses != 1 {
+		t.Errorf("misses = %d, want 1", misses)
+	}
+}
+
+func TestCacheFingerprint(t *testing.T) {
+	t.Parallel()
+
+	a := &config.Config{Language: "go", Exclude: []string{"vendor"}}
+	b := &config.Config{Language: "go", Exclude: []string{"vendor", "gen"}}
+	if scanner.CacheFingerprint("1.0", a) == scanner.CacheFingerprint("1.0", b) {
+		t.Error("config change should change the fingerprint")
+	}
+	if scanner.CacheFingerprint("1.0", a) == scanner.CacheFingerprint("1.1", a) {
+		t.Error("version change should change the fingerprint")
+	}
+	if scanner.CacheFingerprint("1.0", a) != scanner.CacheFingerprint("1.0", a) {
+		t.Error("fingerprint should be deterministic")
+	}
+}
+
+func TestPythonScanner_Imports(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "app", "main.py"), `from __future__ import annotations
+import os
+import numpy as np, sys
+from collections import OrderedDict, defaultdict
+from . import models
+from ..utils import helper as h
+from .db import *
+
+try:
+    import ujson as json
+except ImportError:
+    import json
+`)
+	writeFile(t, filepath.Join(dir, ".venv", "lib", "site.py"), "import os\n")
+
+	cfg := &config.Config{Language: "python", Exclude: []string{".venv"}}
+	results, err := scanner.NewPythonScanner(cfg).Scan(dir)
+	if err != nil {
+		t.Fatalf("Scan: %v", err)
+	}
+	if len(results) != 1 {
+		t.Fatalf("got %d files, want 1", len(results))
+	}
+
+	got := results[0]
+	if got.File != filepath.Join("app", "main.py") || got.Lang != "python" {
+		t.Errorf("File = %q, Lang = %q", got.File, got.Lang)
+	}
+
+	want := []struct {
+		path  string
+		kind  scanner.ImportKind
+		names []string
+		alias string
+		line  int
+	}{
+		{"__future__", scanner.ImportNamed, []string{"annotations"}, "", 1},
+		{"os", scanner.ImportNamespace, nil, "", 2},
+		{"numpy", scanner.ImportNamespace, nil, "np", 3},
+		{"sys", scanner.ImportNamespace, nil, "", 3},
+		{"collections", scanner.ImportNamed, []string{"OrderedDict", "defaultdict"}, "", 4},
+		{".", scanner.ImportNamed, []string{"models"}, "", 5},
+		{"..utils", scanner.ImportNamed, []string{"helper as h"}, "", 6},
+		{".db", scanner.ImportNamed, []string{"*"}, "", 7},
+		{"ujson", scanner.ImportNamespace, nil, "json", 10},
+		{"json", scanner.ImportNamespace, nil, "", 12},
+	}
+
+	if len(got.Details) != len(want) || len(got.Imports) != len(want) {
+		t.Fatalf("details len = %d, want %d\ngot: %+v", len(got.Details), len(want), got.Details)
+	}
+	for i, w := range want {
+		d := got.Details[i]
+		if d.Path != w.path || d.Kind != w.kind || d.Alias != w.alias || d.Line != w.line || !slicesEqual(d.Names, w.names) {
+			t.Errorf("details[%d] = %+v, want %+v", i, d, w)
+		}
+		if got.Imports[i] != w.path {
+			t.Errorf("Imports[%d] = %q, want %q", i, got.Imports[i], w.path)
+		}
+	}
+}
+
+func TestPythonScanner_Exports(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		src  string
+		want []scanner.ExportDetail
+	}{
+		{
+			name: "underscore convention",
+			src: `def greet(): pass
+
+class Widget:
+    def method(self): pass
+
+def _helper(): pass
+
+@dataclass
+class Config:
+    pass
+`,
+			want: []scanner.ExportDetail{
+				{Name: "greet", Kind: scanner.ExportFunction, Line: 1},
+				{Name: "Widget", Kind: scanner.ExportClass, Line: 3},
+				{Name: "_helper", Kind: scanner.ExportFunction, Line: 6, Private: true},
+				{Name: "Config", Kind: scanner.ExportClass, Line: 8},
+			},
+		},
+		{
+			name: "dunder all",
+			src: `from .core import Engine
+
+__all__ = ["run", 'Engine']
+
+def run(): pass
+
+def setup(): pass
+`,
+			want: []scanner.ExportDetail{
+				{Name: "run", Kind: scanner.ExportFunction, Line: 5},
+				{Name: "setup", Kind: scanner.ExportFunction, Line: 7, Private: true},
+				{Name: "Engine", Kind: scanner.ExportNamed, Line: 3},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			dir := t.TempDir()
+			writeFile(t, filepath.Join(dir, "mod.py"), tt.src)
+
+			results, err := scanner.NewPythonScanner(&config.Config{Language: "python"}).Scan(dir)
+			if err != nil {
+				t.Fatalf("Scan: %v", err)
+			}
+			if len(results) != 1 {
+				t.Fatalf("got %d files, want 1", len(results))
+			}
+
+			got := results[0].Exports
+			if len(got) != len(tt.want) {
+				t.Fatalf("exports = %+v, want %+v", got, tt.want)
+			}
+			for i := range tt.want {
+				if got[i] != tt.want[i] {
+					t.Errorf("exports[%d] = %+v, want %+v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestRustScanner(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "src", "lib.rs"), `//! use commented::Out;
+extern crate serde as sd;
+
+mod db;
+pub mod handlers;
+
+use std::collections::HashMap;
+use std::{
+    fmt,
+    io::{self, Read},
+};
+use crate::db::*;
+use anyhow as ah;
+use super::config::Settings as Cfg;
+
+/* use block::Comment; /* nested */ use still::Comment; */
+const QUERY: &str = "use fake::Import;";
+
+pub struct Store<'a> { name: &'a str }
+pub(crate) fn helper() -> char { '"' }
+pub trait Repo {}
+fn private() {}
+pub const MAX: u32 = 10;
+
+mod inline {
+    pub fn nested() {}
+}
+`)
+	writeFile(t, filepath.Join(dir, "target", "debug", "build.rs"), "use std::env;\n")
+
+	cfg := &config.Config{Language: "rust", Exclude: []string{"target"}}
+	results, err := scanner.NewRustScanner(cfg).Scan(dir)
+	if err != nil {
+		t.Fatalf("Scan: %v", err)
+	}
+	if len(results) != 1 {
+		t.Fatalf("got %d files, want 1", len(results))
+	}
+	got := results[0]
+	if got.Lang != "rust" {
+		t.Errorf("Lang = %q, want rust", got.Lang)
+	}
+
+	wantImports := []struct {
+		path  string
+		kind  scanner.ImportKind
+		names []string
+		alias string
+		line  int
+	}{
+		{"serde", scanner.ImportExternCrate, nil, "sd", 2},
+		{"self::db", scanner.ImportMod, nil, "", 4},
+		{"self::handlers", scanner.ImportMod, nil, "", 5},
+		{"std::collections", scanner.ImportNamed, []string{"HashMap"}, "", 7},
+		{"std", scanner.ImportNamed, []string{"fmt", "io::{self, Read}"}, "", 8},
+		{"crate::db", scanner.ImportNamed, []string{"*"}, "", 12},
+		{"anyhow", scanner.ImportNamespace, nil, "ah", 13},
+		{"super::config", scanner.ImportNamed, []string{"Settings as Cfg"}, "", 14},
+	}
+	if len(got.Details) != len(wantImports) {
+		t.Fatalf("details = %+v, want %d", got.Details, len(wantImports))
+	}
+	for i, w := range wantImports {
+		d := got.Details[i]
+		if d.Path != w.path || d.Kind != w.kind || d.Alias != w.alias || d.Line != w.line || !slicesEqual(d.Names, w.names) {
+			t.Errorf("details[%d] = %+v, want %+v", i, d, w)
+		}
+		if got.Imports[i] != w.path {
+			t.Errorf("Imports[%d] = %q, want %q", i, got.Imports[i], w.path)
+		}
+	}
+
+	wantExports := []scanner.ExportDetail{
+		{Name: "QUERY", Kind: scanner.ExportConst, Line: 17, Private: true},
+		{Name: "Store", Kind: scanner.ExportType, Line: 19},
+		{Name: "helper", Kind: scanner.ExportFunction, Line: 20, Private: true},
+		{Name: "Repo", Kind: scanner.ExportInterface, Line: 21},
+		{Name: "private", Kind: scanner.ExportFunction, Line: 22, Private: true},
+		{Name: "MAX", Kind: scanner.ExportConst, Line: 23},
+	}
+	if len(got.Exports) != len(wantExports) {
+		t.Fatalf("exports = %+v, want %+v", got.Exports, wantExports)
+	}
+	for i := range wantExports {
+		if got.Exports[i] != wantExports[i] {
+			t.Errorf("exports[%d] = %+v, want %+v", i, got.Exports[i], wantExports[i])
+		}
+	}
+}
+
+func TestJVMScanner(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "src", "main", "java", "com", "acme", "App.java"), `package com.acme;
+
+import java.util.List;
+import java.util.*;
+import static org.junit.Assert.assertEquals;
+import static java.lang.Math.*;
+// import commented.Out;
+
+/** Entry point. */
+@SuppressWarnings("unchecked")
+public final class App {
+    public static class Nested {}
+    String s = """
+        import text.Block;
+        """;
+}
+
+interface Repo {}
+`)
+	writeFile(t, filepath.Join(dir, "src", "main", "kotlin", "com", "acme", "Util.kt"), `package com.acme
+
+import kotlinx.coroutines.flow.Flow
+import com.acme.db.Store as DbStore
+
+data class Point(val x: Int)
+internal object Registry
+fun String.shout() = uppercase()
+private fun helper() {}
+`)
+	writeFile(t, filepath.Join(dir, "build", "Gen.java"), "import gen.Code;\n")
+
+	cfg := &config.Config{Language: "jvm", Exclude: []string{"build"}}
+	results, err := scanner.NewJVMScanner(cfg).Scan(dir)
+	if err != nil {
+		t.Fatalf("Scan: %v", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("got %d files, want 2", len(results))
+	}
+	sort.Slice(results, func(i, j int) bool { return results[i].File < results[j].File })
+
+	type imp struct {
+		path  string
+		kind  scanner.ImportKind
+		names []string
+		alias string
+		line  int
+	}
+	tests := []struct {
+		lang    string
+		imports []imp
+		exports []scanner.ExportDetail
+	}{
+		{
+			lang: "java",
+			imports: []imp{
+				{"java.util.List", scanner.ImportNamed, []string{"List"}, "", 3},
+				{"java.util", scanner.ImportNamed, []string{"*"}, "", 4},
+				{"org.junit.Assert", scanner.ImportStatic, []string{"assertEquals"}, "", 5},
+				{"java.lang.Math", scanner.ImportStatic, []string{"*"}, "", 6},
+			},
+			exports: []scanner.ExportDetail{
+				{Name: "App", Kind: scanner.ExportClass, Line: 10},
+				{Name: "Repo", Kind: scanner.ExportInterface, Line: 18, Private: true},
+			},
+		},
+		{
+			lang: "kotlin",
+			imports: []imp{
+				{"kotlinx.coroutines.flow.Flow", scanner.ImportNamed, []string{"Flow"}, "", 3},
+				{"com.acme.db.Store", scanner.ImportNamed, []string{"Store"}, "DbStore", 4},
+			},
+			exports: []scanner.ExportDetail{
+				{Name: "Point", Kind: scanner.ExportClass, Line: 6},
+				{Name: "Registry", Kind: scanner.ExportClass, Line: 7, Private: true},
+				{Name: "shout", Kind: scanner.ExportFunction, Line: 8},
+				{Name: "helper", Kind: scanner.ExportFunction, Line: 9, Private: true},
+			},
+		},
+	}
+
+	for i, tt := range tests {
+		got := results[i]
+		if got.Lang != tt.lang || got.Package != "com.acme" {
+			t.Errorf("%s: Lang = %q, Package = %q", got.File, got.Lang, got.Package)
+		}
+		if len(got.Details) != len(tt.imports) {
+			t.Fatalf("%s: details = %+v, want %d", got.File, got.Details, len(tt.imports))
+		}
+		for j, w := range tt.imports {
+			d := got.Details[j]
+			if d.Path != w.path || d.Kind != w.kind || d.Alias != w.alias || d.Line != w.line || !slicesEqual(d.Names, w.names) {
+				t.Errorf("%s: details[%d] = %+v, want %+v", got.File, j, d, w)
+			}
+		}
+		if len(got.Exports) != len(tt.exports) {
+			t.Fatalf("%s: exports = %+v, want %+v", got.File, got.Exports, tt.exports)
+		}
+		for j := range tt.exports {
+			if got.Exports[j] != tt.exports[j] {
+				t.Errorf("%s: exports[%d] = %+v, want %+v", got.File, j, got.Exports[j], tt.exports[j])
+			}
+		}
+	}
+}
+
 func writeFile(t *testing.T, path, content string) {
 	t.Helper()
 	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
@@ -0,0 +1,100 @@
+package scanner
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+)
+
+// sfcExts are the single-file component formats whose scripts are scanned
+// as JS/TS.
+var sfcExts = map[string]bool{".vue": true, ".svelte": true, ".astro": true}
+
+var (
+	scriptTagRe   = regexp.MustCompile(`(?is)<script\b([^>]*)>(.*?)</script\s*>`)
+	htmlCommentRe = regexp.MustCompile(`(?s)<!--.*?-->`)
+	langAttrRe    = regexp.MustCompile(`(?i)(?:^|\s)lang\s*=\s*["']?([\w-]+)`)
+	typeAttrRe    = regexp.MustCompile(`(?i)(?:^|\s)type\s*=\s*["']?([\w/+.-]+)`)
+)
+
+// sfcScript extracts the code of a Vue, Svelte or Astro component: its
+// <script> blocks (<script setup>, <script context="module">, ...) and, for
+// Astro, the --- frontmatter. Everything else is blanked to spaces with
+// newlines kept, so the result lines up byte for byte with src and
+// tree-sitter positions need no mapping back to the original file.
+//
+// grammar is the extension whose grammar parses the result: .tsx when a
+// block says lang="tsx", .ts when one says lang="ts" and for Astro, which
+// is always TypeScript, otherwise .js.
+func sfcScript(ext string, src []byte) (code []byte, grammar string) {
+	code = blank(src)
+	markup := htmlCommentRe.ReplaceAllFunc(src, blank)
+	grammar = ".js"
+
+	if ext == ".astro" {
+		grammar = ".ts"
+		if start, end, ok := frontmatter(src); ok {
+			copy(code[start:end], src[start:end])
+			copy(markup[start:end], blank(src[start:end]))
+		}
+	}
+
+	for _, m := range scriptTagRe.FindAllSubmatchIndex(markup, -1) {
+		attrs := markup[m[2]:m[3]]
+		if t := typeAttrRe.FindSubmatch(attrs); t != nil && !isScriptType(string(t[1])) {
+			continue
+		}
+		copy(code[m[4]:m[5]], src[m[4]:m[5]])
+		if l := langAttrRe.FindSubmatch(attrs); l != nil {
+			switch strings.ToLower(string(l[1])) {
+			case "tsx":
+				grammar = ".tsx"
+			case "ts", "typescript":
+				if grammar == ".js" {
+					grammar = ".ts"
+				}
+			}
+		}
+	}
+	return code, grammar
+}
+
+// frontmatter returns the byte range of the lines between the --- fence
+// that opens an Astro file and the one that closes it.
+func frontmatter(src []byte) (start, end int, ok bool) {
+	i := len(src) - len(bytes.TrimLeft(src, " \t\r\n\ufeff"))
+	nl := bytes.IndexByte(src[i:], '\n')
+	if nl < 0 || string(bytes.TrimSpace(src[i:i+nl])) != "---" {
+		return 0, 0, false
+	}
+	start = i + nl + 1
+	for pos := start; pos < len(src); {
+		lineEnd := len(src)
+		if next := bytes.IndexByte(src[pos:], '\n'); next >= 0 {
+			lineEnd = pos + next
+		}
+		if string(bytes.TrimSpace(src[pos:lineEnd])) == "---" {
+			return start, pos, true
+		}
+		pos = lineEnd + 1
+	}
+	return 0, 0, false
+}
+
+// isScriptType reports whether a <script type="..."> holds JS/TS rather
+// than data such as JSON or a template.
+func isScriptType(t string) bool {
+	t = strings.ToLower(t)
+	return t == "module" || strings.HasSuffix(t, "javascript") || strings.HasSuffix(t, "typescript")
+}
+
+// blank returns a copy of b with every byte but newlines replaced by a space.
+func blank(b []byte) []byte {
+	out := bytes.Repeat([]byte{' '}, len(b))
+	for i, c := range b {
+		if c == '\n' {
+			out[i] = '\n'
+		}
+	}
+	return out
+}
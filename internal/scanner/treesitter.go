@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"unsafe"
 
 	tree_sitter "github.com/tree-sitter/go-tree-sitter"
@@ -14,7 +15,10 @@ import (
 	"github.com/jtoloui/depviz/internal/config"
 )
 
-var _ Scanner = (*TreeSitterScanner)(nil)
+var (
+	_ FileScanner = (*TreeSitterScanner)(nil)
+	_ Cacheable   = (*TreeSitterScanner)(nil)
+)
 
 // S-expression query that captures import/export/require/dynamic-import sources.
 const importQuery = `
@@ -31,38 +35,85 @@ const importQuery = `
 
 type TreeSitterScanner struct {
 	cfg *config.Config
+	cacheSlot
 }
 
 func NewTreeSitterScanner(cfg *config.Config) *TreeSitterScanner {
 	return &TreeSitterScanner{cfg: cfg}
 }
 
-func (t *TreeSitterScanner) Scan(root string) ([]FileImports, error) {
-	exts := map[string]bool{".js": true, ".jsx": true, ".ts": true, ".tsx": true, ".mjs": true}
-	skip := toSet(t.cfg.Exclude)
+var jsExts = map[string]bool{".js": true, ".jsx": true, ".ts": true, ".tsx": true, ".mjs": true}
+
+// grammarExts are the extensions languageForExt tells apart: every file is
+// parsed with the grammar of one of them.
+var grammarExts = []string{".js", ".ts", ".tsx"}
 
-	include := func(path string, info os.FileInfo) bool {
-		return !info.IsDir() && exts[filepath.Ext(path)]
+func (t *TreeSitterScanner) Scan(root string) ([]FileImports, error) {
+	// Pre-compile queries per grammar (thread-safe for reads).
+	queries, err := compileQueries()
+	if err != nil {
+		return nil, err
 	}
+	defer closeQueries(queries)
 
-	// Pre-compile queries per language (thread-safe for reads).
-	queries := make(map[string]*tree_sitter.Query)
-	for ext, lang := range map[string]*tree_sitter.Language{
-		".js": languageForExt(".js"), ".jsx": languageForExt(".jsx"),
-		".ts": languageForExt(".ts"), ".tsx": languageForExt(".tsx"),
-		".mjs": languageForExt(".mjs"),
-	} {
-		q, err := tree_sitter.NewQuery(lang, importQuery)
+	return walkAndParse(root, toSet(t.cfg.Exclude), keepTests(root, t.cfg.Tests, isJSTest, includeJS), t.cache.wrap(func(root, path string) (*FileImports, error) {
+		return t.parseFile(root, path, queries)
+	}))
+}
+
+func (t *TreeSitterScanner) ScanFile(root, path string) (*FileImports, error) {
+	return parseOne(root, path, toSet(t.cfg.Exclude), keepTests(root, t.cfg.Tests, isJSTest, includeJS), func(root, path string) (*FileImports, error) {
+		queries, err := compileQueries()
 		if err != nil {
+			return nil, err
+		}
+		defer closeQueries(queries)
+		return t.parseFile(root, path, queries)
+	})
+}
+
+// compileQueries compiles importQuery for each grammar, keyed by its
+// extension in grammarExts. The caller closes them with closeQueries.
+func compileQueries() (map[string]*tree_sitter.Query, error) {
+	queries := make(map[string]*tree_sitter.Query, len(grammarExts))
+	for _, ext := range grammarExts {
+		q, err := tree_sitter.NewQuery(languageForExt(ext), importQuery)
+		if err != nil {
+			closeQueries(queries)
 			return nil, fmt.Errorf("query compile for %s: %w", ext, err)
 		}
-		defer q.Close()
 		queries[ext] = q
 	}
+	return queries, nil
+}
+
+func closeQueries(queries map[string]*tree_sitter.Query) {
+	for _, q := range queries {
+		q.Close()
+	}
+}
 
-	return walkAndParse(root, skip, include, func(root, path string) (*FileImports, error) {
-		return t.parseFile(root, path, queries[filepath.Ext(path)])
-	})
+func includeJS(path string, info os.FileInfo) bool {
+	ext := filepath.Ext(path)
+	return !info.IsDir() && (jsExts[ext] || sfcExts[ext])
+}
+
+// isJSTest matches the Jest/Vitest conventions: foo.test.ts, foo.spec.tsx
+// and anything under __tests__.
+func isJSTest(rel string) bool {
+	stem := strings.TrimSuffix(filepath.Base(rel), filepath.Ext(rel))
+	return strings.HasSuffix(stem, ".test") || strings.HasSuffix(stem, ".spec") || inDir(rel, "__tests__")
+}
+
+// grammarFor returns the grammarExts entry a file with extension ext parses
+// with.
+func grammarFor(ext string) string {
+	switch ext {
+	case ".ts", ".tsx":
+		return ext
+	default:
+		return ".js"
+	}
 }
 
 func languageForExt(ext string) *tree_sitter.Language {
@@ -89,17 +140,23 @@ func nodeText(src []byte, n *tree_sitter.Node) string {
 	return string(src[n.StartByte():n.EndByte()])
 }
 
-func (t *TreeSitterScanner) parseFile(root, path string, query *tree_sitter.Query) (*FileImports, error) {
-	src, err := os.ReadFile(path)
+func (t *TreeSitterScanner) parseFile(root, path string, queries map[string]*tree_sitter.Query) (*FileImports, error) {
+	raw, err := os.ReadFile(path)
 	if err != nil {
 		return nil, fmt.Errorf("read %s: %w", path, err)
 	}
 
-	lang := languageForExt(filepath.Ext(path))
+	// Components keep only their scripts, blanked in place so lines and
+	// byte offsets still match the original file.
+	src, grammar := raw, grammarFor(filepath.Ext(path))
+	if ext := filepath.Ext(path); sfcExts[ext] {
+		src, grammar = sfcScript(ext, raw)
+	}
+	query := queries[grammar]
 
 	parser := tree_sitter.NewParser()
 	defer parser.Close()
-	if err := parser.SetLanguage(lang); err != nil {
+	if err := parser.SetLanguage(languageForExt(grammar)); err != nil {
 		return nil, fmt.Errorf("set language for %s: %w", path, err)
 	}
 
@@ -139,7 +196,7 @@ func (t *TreeSitterScanner) parseFile(root, path string, query *tree_sitter.Quer
 	}
 
 	rel, _ := filepath.Rel(root, path)
-	return &FileImports{File: rel, Lang: "js", Imports: imports, Details: details, Exports: exports, Lines: bytes.Count(src, []byte{'\n'}) + 1}, nil
+	return &FileImports{File: rel, Lang: "js", IsTest: isJSTest(rel), Imports: imports, Details: details, Exports: exports, Lines: bytes.Count(src, []byte{'\n'}) + 1}, nil
 }
 
 // extractDetail walks up from the captured string node to the statement
@@ -243,6 +300,15 @@ func extractImportStatement(src []byte, stmt *tree_sitter.Node, modPath string)
 }
 
 func extractExportStatement(src []byte, stmt *tree_sitter.Node, modPath string) ImportDetail {
+	// export * as ns from "x"
+	if ns := childByKind(stmt, "namespace_export"); ns != nil {
+		names := []string{"*"}
+		if id := childByKind(ns, "identifier"); id != nil {
+			names[0] = "* as " + nodeText(src, id)
+		}
+		return ImportDetail{Path: modPath, Kind: ImportReExport, Names: names}
+	}
+
 	// export * from "x"
 	if hasChildKind(stmt, "*") {
 		return ImportDetail{Path: modPath, Kind: ImportReExportAll}
@@ -315,11 +381,16 @@ func extractExports(src []byte, root *tree_sitter.Node) []ExportDetail {
 				for k := uint(0); k < uint(clause.ChildCount()); k++ {
 					spec := clause.Child(k)
 					if spec.Kind() == "export_specifier" {
-						if id := spec.Child(0); id != nil {
-							exports = append(exports, ExportDetail{Name: nodeText(src, id), Kind: ExportReExport, Line: line})
+						if name := exportedName(src, spec); name != "" {
+							exports = append(exports, ExportDetail{Name: name, Kind: ExportReExport, Line: line})
 						}
 					}
 				}
+			} else if ns := childByKind(node, "namespace_export"); ns != nil {
+				// export * as ns from './x' — a single binding
+				if id := childByKind(ns, "identifier"); id != nil {
+					exports = append(exports, ExportDetail{Name: nodeText(src, id), Kind: ExportReExport, Line: line})
+				}
 			} else {
 				exports = append(exports, ExportDetail{Name: name, Kind: ExportReExport, Line: line})
 			}
@@ -344,7 +415,7 @@ func extractExports(src []byte, root *tree_sitter.Node) []ExportDetail {
 				exports = append(exports, ExportDetail{Name: name, Kind: kind, Line: line})
 			case "class_declaration":
 				name := "default"
-				if id := childByKind(child, "type_identifier"); id != nil {
+				if id := child.ChildByFieldName("name"); id != nil {
 					name = nodeText(src, id)
 				}
 				kind := ExportClass
@@ -374,8 +445,8 @@ func extractExports(src []byte, root *tree_sitter.Node) []ExportDetail {
 				for k := uint(0); k < uint(child.ChildCount()); k++ {
 					spec := child.Child(k)
 					if spec.Kind() == "export_specifier" {
-						if id := spec.Child(0); id != nil {
-							exports = append(exports, ExportDetail{Name: nodeText(src, id), Kind: ExportNamed, Line: line})
+						if name := exportedName(src, spec); name != "" {
+							exports = append(exports, ExportDetail{Name: name, Kind: ExportNamed, Line: line})
 						}
 					}
 				}
@@ -400,6 +471,18 @@ func extractExports(src []byte, root *tree_sitter.Node) []ExportDetail {
 	return exports
 }
 
+// exportedName returns the name an export_specifier is visible as to
+// importers: the alias in `export { a as b }`, otherwise the name itself.
+func exportedName(src []byte, spec *tree_sitter.Node) string {
+	if alias := spec.ChildByFieldName("alias"); alias != nil {
+		return nodeText(src, alias)
+	}
+	if name := spec.ChildByFieldName("name"); name != nil {
+		return nodeText(src, name)
+	}
+	return ""
+}
+
 func hasExportDecl(node *tree_sitter.Node) bool {
 	for i := uint(0); i < uint(node.ChildCount()); i++ {
 		switch node.Child(i).Kind() {
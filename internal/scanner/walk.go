@@ -4,7 +4,11 @@ import (
 	"os"
 	"path/filepath"
 	"runtime"
+	"slices"
+	"strings"
 	"sync"
+
+	"github.com/jtoloui/depviz/internal/config"
 )
 
 // parseFunc extracts imports from a single file.
@@ -76,3 +80,60 @@ func walkAndParse(root string, skip map[string]bool, shouldInclude func(string,
 
 	return files, nil
 }
+
+// parseOne applies the same filtering as walkAndParse to a single path, so a
+// file re-parsed on its own gets exactly the result a full walk would give.
+// It returns nil for paths the walk would never have visited.
+func parseOne(root, path string, skip map[string]bool, shouldInclude func(string, os.FileInfo) bool, parse parseFunc) (*FileImports, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if !shouldInclude(path, info) || inSkippedDir(root, path, skip) {
+		return nil, nil
+	}
+	return parse(root, path)
+}
+
+// inSkippedDir reports whether any directory between root and path is one
+// walkAndParse skips. Paths outside root count as skipped.
+func inSkippedDir(root, path string, skip map[string]bool) bool {
+	rel, err := filepath.Rel(root, filepath.Dir(path))
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return true
+	}
+	if rel == "." {
+		return false
+	}
+	for _, dir := range strings.Split(rel, string(filepath.Separator)) {
+		if skip[dir] {
+			return true
+		}
+	}
+	return false
+}
+
+// keepTests narrows include to the files mode keeps, where isTest reports
+// whether a root-relative path is a test file. TestsDefault and
+// TestsInclude keep everything include does.
+func keepTests(root string, mode config.Tests, isTest func(rel string) bool, include func(string, os.FileInfo) bool) func(string, os.FileInfo) bool {
+	if mode != config.TestsExclude && mode != config.TestsOnly {
+		return include
+	}
+	return func(path string, info os.FileInfo) bool {
+		if !include(path, info) {
+			return false
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return false
+		}
+		return isTest(rel) == (mode == config.TestsOnly)
+	}
+}
+
+// inDir reports whether any directory in the relative path rel is named dir.
+func inDir(rel, dir string) bool {
+	parts := strings.Split(filepath.ToSlash(filepath.Dir(rel)), "/")
+	return slices.Contains(parts, dir)
+}
@@ -0,0 +1,104 @@
+package tsconfig
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+)
+
+// stripJSONC turns tsconfig's JSON-with-comments into plain JSON by dropping
+// // and /* */ comments and trailing commas outside of strings.
+func stripJSONC(data []byte) []byte {
+	out := make([]byte, 0, len(data))
+	for i := 0; i < len(data); i++ {
+		c := data[i]
+		switch {
+		case c == '"':
+			j := i + 1
+			for j < len(data) && data[j] != '"' {
+				if data[j] == '\\' {
+					j++
+				}
+				j++
+			}
+			end := min(j+1, len(data))
+			out = append(out, data[i:end]...)
+			i = end - 1
+		case c == '/' && i+1 < len(data) && data[i+1] == '/':
+			for i < len(data) && data[i] != '\n' {
+				i++
+			}
+			i--
+		case c == '/' && i+1 < len(data) && data[i+1] == '*':
+			end := bytes.Index(data[i+2:], []byte("*/"))
+			if end < 0 {
+				return out
+			}
+			i += 2 + end + 1
+		case c == ',' && closesContainer(data[i+1:]):
+			// trailing comma: drop it
+		default:
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+// closesContainer reports whether the next significant byte (skipping
+// whitespace and comments) closes an object or array.
+func closesContainer(rest []byte) bool {
+	c := nextSignificant(rest)
+	return c == '}' || c == ']'
+}
+
+func nextSignificant(rest []byte) byte {
+	for i := 0; i < len(rest); i++ {
+		switch c := rest[i]; {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+		case c == '/' && i+1 < len(rest) && rest[i+1] == '/':
+			for i < len(rest) && rest[i] != '\n' {
+				i++
+			}
+		case c == '/' && i+1 < len(rest) && rest[i+1] == '*':
+			end := bytes.Index(rest[i+2:], []byte("*/"))
+			if end < 0 {
+				return 0
+			}
+			i += 2 + end + 1
+		default:
+			return c
+		}
+	}
+	return 0
+}
+
+// orderedPaths is compilerOptions.paths with its key order preserved, so
+// ties between equally specific patterns resolve the way tsc does.
+type orderedPaths struct {
+	entries []pathEntry
+}
+
+type pathEntry struct {
+	key     string
+	targets []string
+}
+
+func (p *orderedPaths) UnmarshalJSON(data []byte) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	if t, err := dec.Token(); err != nil || t != json.Delim('{') {
+		return errors.New("paths must be an object")
+	}
+	for dec.More() {
+		t, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		key, _ := t.(string)
+		var targets []string
+		if err := dec.Decode(&targets); err != nil {
+			return err
+		}
+		p.entries = append(p.entries, pathEntry{key: key, targets: targets})
+	}
+	return nil
+}
@@ -0,0 +1,269 @@
+// Package tsconfig reads the module resolution settings — baseUrl and paths —
+// from a project's tsconfig.json or jsconfig.json, following extends chains.
+package tsconfig
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Config is the effective resolution settings of a project. Directories are
+// absolute.
+type Config struct {
+	BaseURL string  // empty when no config in the chain sets baseUrl
+	Paths   []Alias // in declaration order
+}
+
+// Alias is one entry of compilerOptions.paths. Pattern and targets contain at
+// most one *; targets are absolute.
+type Alias struct {
+	Pattern string
+	Targets []string
+}
+
+type file struct {
+	Extends         json.RawMessage `json:"extends"`
+	CompilerOptions struct {
+		BaseURL *string       `json:"baseUrl"`
+		Paths   *orderedPaths `json:"paths"`
+	} `json:"compilerOptions"`
+}
+
+// Load reads tsconfig.json (or jsconfig.json) in root. It returns an error
+// wrapping os.ErrNotExist when the project has neither.
+func Load(root string) (*Config, error) {
+	for _, name := range []string{"tsconfig.json", "jsconfig.json"} {
+		path := filepath.Join(root, name)
+		if _, err := os.Stat(path); err != nil {
+			continue
+		}
+		var st state
+		if err := st.load(path, map[string]bool{}); err != nil {
+			return nil, err
+		}
+		return st.config(), nil
+	}
+	return nil, fmt.Errorf("no tsconfig.json or jsconfig.json in %s: %w", root, os.ErrNotExist)
+}
+
+// state accumulates settings while walking an extends chain. Later (more
+// derived) configs overwrite earlier ones field by field, and each setting
+// remembers the directory of the file that declared it.
+type state struct {
+	baseURL  string
+	paths    *orderedPaths
+	pathsDir string
+}
+
+func (st *state) load(path string, visiting map[string]bool) error {
+	if visiting[path] {
+		return fmt.Errorf("extends cycle at %s", path)
+	}
+	visiting[path] = true
+	defer delete(visiting, path) // diamonds are fine, only true cycles fail
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	var f file
+	if err := json.Unmarshal(stripJSONC(data), &f); err != nil {
+		return fmt.Errorf("parsing %s: %w", path, err)
+	}
+	dir := filepath.Dir(path)
+
+	parents, err := extendsList(f.Extends)
+	if err != nil {
+		return fmt.Errorf("parsing %s: %w", path, err)
+	}
+	for _, p := range parents {
+		parent, err := resolveExtends(dir, p)
+		if err != nil {
+			return fmt.Errorf("%s: extends %q: %w", path, p, err)
+		}
+		if err := st.load(parent, visiting); err != nil {
+			return err
+		}
+	}
+
+	if b := f.CompilerOptions.BaseURL; b != nil {
+		st.baseURL = filepath.Join(dir, filepath.FromSlash(*b))
+	}
+	if p := f.CompilerOptions.Paths; p != nil {
+		st.paths = p
+		st.pathsDir = dir
+	}
+	return nil
+}
+
+// config resolves path targets: against baseUrl when one is set, otherwise
+// against the config that declared paths (TypeScript 4.1+ behaviour).
+func (st *state) config() *Config {
+	c := &Config{BaseURL: st.baseURL}
+	if st.paths == nil {
+		return c
+	}
+	base := st.pathsDir
+	if st.baseURL != "" {
+		base = st.baseURL
+	}
+	for _, e := range st.paths.entries {
+		a := Alias{Pattern: e.key}
+		for _, t := range e.targets {
+			a.Targets = append(a.Targets, filepath.Join(base, filepath.FromSlash(t)))
+		}
+		c.Paths = append(c.Paths, a)
+	}
+	return c
+}
+
+func extendsList(raw json.RawMessage) ([]string, error) {
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil, nil
+	}
+	var one string
+	if err := json.Unmarshal(raw, &one); err == nil {
+		return []string{one}, nil
+	}
+	var many []string
+	if err := json.Unmarshal(raw, &many); err != nil {
+		return nil, errors.New("extends must be a string or an array of strings")
+	}
+	return many, nil
+}
+
+// resolveExtends finds the file an extends entry names: a relative path
+// (with or without .json) or a package in a node_modules directory above
+// dir.
+func resolveExtends(dir, spec string) (string, error) {
+	var candidates []string
+	if strings.HasPrefix(spec, ".") || filepath.IsAbs(spec) {
+		p := spec
+		if !filepath.IsAbs(p) {
+			p = filepath.Join(dir, filepath.FromSlash(spec))
+		}
+		candidates = []string{p, p + ".json"}
+	} else {
+		for d := dir; ; d = filepath.Dir(d) {
+			p := filepath.Join(d, "node_modules", filepath.FromSlash(spec))
+			candidates = append(candidates, p, p+".json", filepath.Join(p, "tsconfig.json"))
+			if filepath.Dir(d) == d {
+				break
+			}
+		}
+	}
+	for _, c := range candidates {
+		if info, err := os.Stat(c); err == nil && !info.IsDir() {
+			return c, nil
+		}
+	}
+	return "", os.ErrNotExist
+}
+
+// Resolve returns the absolute paths spec may refer to, best match first,
+// without extensions probed. Relative specifiers return nil. The paths
+// pattern with the longest prefix before its * wins, as in tsc; baseUrl is
+// tried last.
+func (c *Config) Resolve(spec string) []string {
+	if strings.HasPrefix(spec, ".") || strings.HasPrefix(spec, "/") {
+		return nil
+	}
+
+	var out []string
+	best, bestLen := -1, -1
+	var bestMatch string
+	for i, a := range c.Paths {
+		m, ok := match(a.Pattern, spec)
+		if !ok {
+			continue
+		}
+		if n := prefixLen(a.Pattern); n > bestLen {
+			best, bestLen, bestMatch = i, n, m
+		}
+	}
+	if best >= 0 {
+		for _, t := range c.Paths[best].Targets {
+			out = append(out, strings.Replace(t, "*", filepath.FromSlash(bestMatch), 1))
+		}
+	}
+	if c.BaseURL != "" {
+		out = append(out, filepath.Join(c.BaseURL, filepath.FromSlash(spec)))
+	}
+	return out
+}
+
+// Patterns returns classification regexes for every specifier the config
+// makes local: each paths pattern, plus the top-level entries of baseUrl.
+// A bare "*" catch-all is skipped since it would claim every package.
+func (c *Config) Patterns() []string {
+	var out []string
+	for _, a := range c.Paths {
+		if a.Pattern == "*" {
+			continue
+		}
+		prefix, suffix, wild := strings.Cut(a.Pattern, "*")
+		if wild {
+			out = append(out, `^`+regexp.QuoteMeta(prefix)+`.*`+regexp.QuoteMeta(suffix)+`$`)
+		} else {
+			out = append(out, `^`+regexp.QuoteMeta(a.Pattern)+`$`)
+		}
+	}
+
+	if c.BaseURL == "" {
+		return out
+	}
+	entries, err := os.ReadDir(c.BaseURL)
+	if err != nil {
+		return out
+	}
+	var names []string
+	for _, e := range entries {
+		name := e.Name()
+		switch {
+		case strings.HasPrefix(name, "."), name == "node_modules":
+		case e.IsDir():
+			names = append(names, name)
+		case isSource(name):
+			names = append(names, strings.TrimSuffix(name, filepath.Ext(name)))
+		}
+	}
+	sort.Strings(names)
+	for _, n := range names {
+		out = append(out, `^`+regexp.QuoteMeta(n)+`(/|$)`)
+	}
+	return out
+}
+
+func isSource(name string) bool {
+	switch filepath.Ext(name) {
+	case ".ts", ".tsx", ".js", ".jsx", ".mjs":
+		return !strings.HasSuffix(name, ".d.ts")
+	}
+	return false
+}
+
+// match reports whether spec matches pattern and returns what the * matched.
+func match(pattern, spec string) (string, bool) {
+	prefix, suffix, wild := strings.Cut(pattern, "*")
+	if !wild {
+		return "", spec == pattern
+	}
+	if len(spec) < len(prefix)+len(suffix) || !strings.HasPrefix(spec, prefix) || !strings.HasSuffix(spec, suffix) {
+		return "", false
+	}
+	return spec[len(prefix) : len(spec)-len(suffix)], true
+}
+
+func prefixLen(pattern string) int {
+	if i := strings.Index(pattern, "*"); i >= 0 {
+		return i
+	}
+	// Exact patterns beat any wildcard.
+	return len(pattern) + 1<<16
+}
@@ -0,0 +1,165 @@
+package tsconfig_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/jtoloui/depviz/internal/tsconfig"
+)
+
+func TestLoad_Extends(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	// Shared base from a package, then a local base, then the project config.
+	writeFile(t, filepath.Join(dir, "node_modules", "@acme", "tsconfig", "base.json"), `{
+		"compilerOptions": { "strict": true, "paths": { "ignored/*": ["nowhere/*"] } }
+	}`)
+	writeFile(t, filepath.Join(dir, "config", "tsconfig.base.json"), `{
+		// baseUrl is relative to this file, not the project
+		"extends": "@acme/tsconfig/base.json",
+		"compilerOptions": {
+			"baseUrl": "../src",
+		},
+	}`)
+	writeFile(t, filepath.Join(dir, "tsconfig.json"), `{
+		"extends": "./config/tsconfig.base",
+		/* aliases */
+		"compilerOptions": {
+			"paths": {
+				"@/*": ["*"],
+				"~lib/*": ["../lib/*", "../vendor/lib/*"],
+				"config": ["config/index.ts"],
+			},
+		},
+		"include": ["src/**/*.ts"] // trailing comment
+	}`)
+
+	cfg, err := tsconfig.Load(dir)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+
+	if want := filepath.Join(dir, "src"); cfg.BaseURL != want {
+		t.Errorf("BaseURL = %q, want %q", cfg.BaseURL, want)
+	}
+	if len(cfg.Paths) != 3 || cfg.Paths[0].Pattern != "@/*" || cfg.Paths[2].Pattern != "config" {
+		t.Fatalf("Paths = %+v, want @/*, ~lib/*, config in order", cfg.Paths)
+	}
+
+	tests := []struct {
+		spec string
+		want []string
+	}{
+		{"@/components/Button", []string{"src/components/Button", "src/@/components/Button"}},
+		{"~lib/db", []string{"lib/db", "vendor/lib/db", "src/~lib/db"}},
+		{"config", []string{"src/config/index.ts", "src/config"}},
+		{"utils/date", []string{"src/utils/date"}},
+		{"./relative", nil},
+	}
+	for _, tt := range tests {
+		got := cfg.Resolve(tt.spec)
+		if len(got) != len(tt.want) {
+			t.Errorf("Resolve(%q) = %v, want %v", tt.spec, got, tt.want)
+			continue
+		}
+		for i := range tt.want {
+			if want := filepath.Join(dir, filepath.FromSlash(tt.want[i])); got[i] != want {
+				t.Errorf("Resolve(%q)[%d] = %q, want %q", tt.spec, i, got[i], want)
+			}
+		}
+	}
+}
+
+func TestResolve_LongestPrefixWins(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "jsconfig.json"), `{
+		"compilerOptions": { "paths": { "@app/*": ["app/*"], "@app/ui/*": ["packages/ui/src/*"] } }
+	}`)
+
+	cfg, err := tsconfig.Load(dir)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	// Without baseUrl, targets are relative to the config file.
+	got := cfg.Resolve("@app/ui/Button")
+	if want := filepath.Join(dir, "packages", "ui", "src", "Button"); len(got) != 1 || got[0] != want {
+		t.Errorf("Resolve = %v, want [%s]", got, want)
+	}
+}
+
+func TestPatterns(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "tsconfig.json"), `{
+		"compilerOptions": { "baseUrl": "src", "paths": { "@/*": ["*"], "*": ["types/*"] } }
+	}`)
+	writeFile(t, filepath.Join(dir, "src", "components", "Button.tsx"), "")
+	writeFile(t, filepath.Join(dir, "src", "main.ts"), "")
+	writeFile(t, filepath.Join(dir, "src", "env.d.ts"), "")
+
+	cfg, err := tsconfig.Load(dir)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	patterns := cfg.Patterns()
+
+	tests := []struct {
+		spec string
+		want bool
+	}{
+		{"@/components/Button", true},
+		{"components/Button", true},
+		{"main", true},
+		{"react", false},
+		{"@mui/material", false},
+		{"env", false},
+	}
+	for _, tt := range tests {
+		matched := false
+		for _, p := range patterns {
+			if regexp.MustCompile(p).MatchString(tt.spec) {
+				matched = true
+			}
+		}
+		if matched != tt.want {
+			t.Errorf("%q matched = %v, want %v (patterns %v)", tt.spec, matched, tt.want, patterns)
+		}
+	}
+}
+
+func TestLoad_Missing(t *testing.T) {
+	t.Parallel()
+
+	if _, err := tsconfig.Load(t.TempDir()); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("err = %v, want os.ErrNotExist", err)
+	}
+}
+
+func TestLoad_ExtendsCycle(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "tsconfig.json"), `{"extends": "./a.json"}`)
+	writeFile(t, filepath.Join(dir, "a.json"), `{"extends": "./tsconfig.json"}`)
+
+	if _, err := tsconfig.Load(dir); err == nil {
+		t.Error("expected error for extends cycle")
+	}
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
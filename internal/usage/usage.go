@@ -0,0 +1,241 @@
+// Package usage cross-references the symbols files export with the imports
+// that consume them.
+package usage
+
+import (
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/jtoloui/depviz/internal/graph"
+	"github.com/jtoloui/depviz/internal/scanner"
+)
+
+// all marks every export of a file as used: a namespace import, require(),
+// dynamic import() or Go dot import can reach any of them.
+const all = "*"
+
+// DeadExport is an exported symbol no other file imports.
+type DeadExport struct {
+	File string             `json:"file"`
+	Name string             `json:"name"`
+	Kind scanner.ExportKind `json:"kind"`
+	Line int                `json:"line,omitempty"`
+}
+
+type binding struct {
+	file, name string
+}
+
+// index tracks which exported bindings are used, following re-exports to the
+// file that defines them.
+type index struct {
+	exports  map[string]map[string]bool // file → binding names it exports
+	forwards map[binding][]binding      // export { a as b } from: F.b → T.a
+	stars    map[string][]string        // export * from: F → T
+	used     map[binding]bool
+}
+
+// DeadExports returns the exports in results that no other file imports,
+// sorted by file and line. g must be built from results.
+//
+// JS/TS exports are checked across the whole project: named and default
+// imports use the bindings they name, namespace imports, require() and
+// import() use every export, and re-exports pass use on to the file they
+// re-export from. Go exports are only checked in internal/ packages, where
+// every possible consumer is in the project, and count as used when a file
+// in another package selects them. Other languages are skipped.
+func DeadExports(results []scanner.FileImports, g *graph.Graph) []DeadExport {
+	ix := &index{
+		exports:  map[string]map[string]bool{},
+		forwards: map[binding][]binding{},
+		stars:    map[string][]string{},
+		used:     map[binding]bool{},
+	}
+	for _, fi := range results {
+		names := map[string]bool{}
+		for _, e := range fi.Exports {
+			names[bindingName(e)] = true
+		}
+		ix.exports[fi.File] = names
+	}
+
+	// Wire up every re-export before following any use through them.
+	edges := g.Edges()
+	for _, e := range edges {
+		if g.Node(e.From).Lang == "js" {
+			ix.addReExport(e)
+		}
+	}
+	for _, e := range edges {
+		switch g.Node(e.From).Lang {
+		case "js":
+			ix.useJS(e)
+		case "go":
+			ix.useGo(e)
+		}
+	}
+
+	var dead []DeadExport
+	for _, fi := range results {
+		if !checked(fi) || ix.used[binding{fi.File, all}] {
+			continue
+		}
+		for _, e := range fi.Exports {
+			if e.Private || isStarReExport(e) || ix.used[binding{fi.File, bindingName(e)}] {
+				continue
+			}
+			dead = append(dead, DeadExport{File: fi.File, Name: e.Name, Kind: e.Kind, Line: e.Line})
+		}
+	}
+	sort.SliceStable(dead, func(i, j int) bool {
+		if dead[i].File != dead[j].File {
+			return dead[i].File < dead[j].File
+		}
+		return dead[i].Line < dead[j].Line
+	})
+	return dead
+}
+
+func (ix *index) addReExport(e graph.Edge) {
+	switch e.Import.Kind {
+	case scanner.ImportReExportAll:
+		ix.stars[e.From] = append(ix.stars[e.From], e.To)
+	case scanner.ImportReExport:
+		for _, spec := range e.Import.Names {
+			// "* as ns" re-exports the whole module under one name.
+			if ns, ok := strings.CutPrefix(spec, "* as "); ok {
+				ix.forwards[binding{e.From, ns}] = append(ix.forwards[binding{e.From, ns}], binding{e.To, all})
+				continue
+			}
+			from, as := splitAlias(spec)
+			ix.forwards[binding{e.From, as}] = append(ix.forwards[binding{e.From, as}], binding{e.To, from})
+		}
+	}
+}
+
+func (ix *index) useJS(e graph.Edge) {
+	d := e.Import
+	switch d.Kind {
+	case scanner.ImportNamed:
+		ix.useNames(e.To, d.Names)
+	case scanner.ImportDefault:
+		ix.use(e.To, "default")
+		if len(d.Names) > 1 {
+			ix.useNames(e.To, d.Names[1:])
+		}
+	case scanner.ImportType:
+		if d.Alias != "" {
+			ix.use(e.To, all)
+			return
+		}
+		// `import type X from` and `import type { X } from` both leave
+		// just X in Names, so count it either way.
+		ix.use(e.To, "default")
+		ix.useNames(e.To, d.Names)
+	case scanner.ImportNamespace, scanner.ImportRequire, scanner.ImportDynamic:
+		ix.use(e.To, all)
+	case scanner.ImportReExport:
+		if len(d.Names) == 0 {
+			ix.use(e.To, all)
+		}
+	}
+}
+
+func (ix *index) useGo(e graph.Edge) {
+	if filepath.Dir(e.From) == filepath.Dir(e.To) {
+		return
+	}
+	switch e.Import.Kind {
+	case scanner.ImportDot:
+		ix.use(e.To, all)
+	case scanner.ImportNamed, scanner.ImportAlias:
+		for _, name := range e.Import.Names {
+			ix.use(e.To, name)
+		}
+	}
+}
+
+func (ix *index) useNames(file string, specs []string) {
+	for _, spec := range specs {
+		name, _ := splitAlias(spec)
+		ix.use(file, name)
+	}
+}
+
+// use marks file's export name as used, along with whatever it re-exports.
+func (ix *index) use(file, name string) {
+	b := binding{file, name}
+	if ix.used[b] {
+		return
+	}
+	ix.used[b] = true
+
+	if name == all {
+		for fwd, targets := range ix.forwards {
+			if fwd.file == file {
+				for _, t := range targets {
+					ix.use(t.file, t.name)
+				}
+			}
+		}
+		for _, t := range ix.stars[file] {
+			ix.use(t, all)
+		}
+		return
+	}
+	for _, t := range ix.forwards[b] {
+		ix.use(t.file, t.name)
+	}
+	// export * never forwards default, and a local export shadows it.
+	if name != "default" && !ix.exports[file][name] {
+		for _, t := range ix.stars[file] {
+			ix.use(t, name)
+		}
+	}
+}
+
+// checked reports whether fi's exports are analysed.
+func checked(fi scanner.FileImports) bool {
+	switch fi.Lang {
+	case "js":
+		return true
+	case "go":
+		for _, part := range strings.Split(filepath.Dir(fi.File), string(filepath.Separator)) {
+			if part == "internal" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// bindingName is the name importers refer to an export by.
+func bindingName(e scanner.ExportDetail) string {
+	if e.Kind == scanner.ExportDefault {
+		return "default"
+	}
+	return e.Name
+}
+
+// isStarReExport reports whether e is the placeholder the JS scanner records
+// for `export * from`, which names no binding of its own.
+func isStarReExport(e scanner.ExportDetail) bool {
+	return e.Kind == scanner.ExportReExport && strings.HasPrefix(e.Name, "* from ")
+}
+
+// splitAlias splits an import or export specifier ("a", "a as b", "type a")
+// into the name it refers to and the name it binds.
+func splitAlias(spec string) (name, as string) {
+	fields := strings.Fields(spec)
+	if len(fields) > 1 && fields[0] == "type" {
+		fields = fields[1:]
+	}
+	if len(fields) == 0 {
+		return spec, spec
+	}
+	if len(fields) >= 3 && fields[1] == "as" {
+		return fields[0], fields[2]
+	}
+	return fields[0], fields[0]
+}
@@ -0,0 +1,168 @@
+package usage_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jtoloui/depviz/internal/config"
+	"github.com/jtoloui/depviz/internal/graph"
+	"github.com/jtoloui/depviz/internal/scanner"
+	"github.com/jtoloui/depviz/internal/usage"
+)
+
+func TestDeadExports_JS(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	files := map[string]string{
+		"src/app.ts": `import Button, { theme } from './ui';
+import { format as fmt } from './util';
+import * as api from './api';
+const legacy = require('./legacy');
+export function main() {}
+`,
+		// Barrel: forwards some bindings, renames one, re-exports a module.
+		"src/ui/index.ts": `export { default } from './Button';
+export { Link as Anchor } from './Link';
+export * from './theme';
+export * as icons from './icons';
+`,
+		"src/ui/Button.ts": `export default function Button() {}
+export const size = 1;
+`,
+		"src/ui/Link.ts":  "export function Link() {}\n",
+		"src/ui/theme.ts": "export const theme = {};\nexport const dark = {};\n",
+		"src/ui/icons.ts": "export const star = 1;\n",
+		"src/util.ts": `export function format() {}
+export function parse() {}
+const local = 1;
+export { local as renamed };
+`,
+		"src/api.ts":    "export const get = 1;\nexport const post = 2;\n",
+		"src/legacy.js": "export function old() {}\n",
+	}
+	for name, src := range files {
+		writeFile(t, filepath.Join(dir, name), src)
+	}
+
+	got := deadExports(t, dir, "js")
+
+	want := []usage.DeadExport{
+		{File: "src/app.ts", Name: "main", Kind: scanner.ExportFunction, Line: 5},
+		{File: "src/ui/Button.ts", Name: "size", Kind: scanner.ExportConst, Line: 2},
+		{File: "src/ui/Link.ts", Name: "Link", Kind: scanner.ExportFunction, Line: 1},
+		{File: "src/ui/icons.ts", Name: "star", Kind: scanner.ExportConst, Line: 1},
+		{File: "src/ui/index.ts", Name: "Anchor", Kind: scanner.ExportReExport, Line: 2},
+		{File: "src/ui/index.ts", Name: "icons", Kind: scanner.ExportReExport, Line: 4},
+		{File: "src/ui/theme.ts", Name: "dark", Kind: scanner.ExportConst, Line: 2},
+		{File: "src/util.ts", Name: "parse", Kind: scanner.ExportFunction, Line: 2},
+		{File: "src/util.ts", Name: "renamed", Kind: scanner.ExportNamed, Line: 4},
+	}
+	assertDead(t, got, want)
+}
+
+func TestDeadExports_Go(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "go.mod"), "module example.com/app\n\ngo 1.22\n")
+	writeFile(t, filepath.Join(dir, "main.go"), `package main
+
+import (
+	"fmt"
+
+	"example.com/app/internal/store"
+	cfg "example.com/app/internal/config"
+)
+
+func Unchecked() {}
+
+func main() {
+	s := store.Open(cfg.Path)
+	fmt.Println(s)
+}
+`)
+	writeFile(t, filepath.Join(dir, "internal", "store", "store.go"), `package store
+
+type Store struct{}
+
+func Open(path string) *Store { return nil }
+
+func Close() {}
+
+var helper = 1
+`)
+	// Used only inside its own package, so still a candidate.
+	writeFile(t, filepath.Join(dir, "internal", "store", "util.go"), `package store
+
+func Flush() { Close() }
+`)
+	writeFile(t, filepath.Join(dir, "internal", "config", "config.go"), `package config
+
+const Path = "x"
+
+const Unused = "y"
+`)
+
+	got := deadExports(t, dir, "go")
+
+	want := []usage.DeadExport{
+		{File: filepath.Join("internal", "config", "config.go"), Name: "Unused", Kind: scanner.ExportConst, Line: 5},
+		{File: filepath.Join("internal", "store", "store.go"), Name: "Store", Kind: scanner.ExportType, Line: 3},
+		{File: filepath.Join("internal", "store", "store.go"), Name: "Close", Kind: scanner.ExportFunction, Line: 7},
+		{File: filepath.Join("internal", "store", "util.go"), Name: "Flush", Kind: scanner.ExportFunction, Line: 3},
+	}
+	assertDead(t, got, want)
+}
+
+func deadExports(t *testing.T, dir, lang string) []usage.DeadExport {
+	t.Helper()
+	cfg := &config.Config{Language: lang, Exclude: []string{".git"}}
+	var s scanner.Scanner = scanner.NewTreeSitterScanner(cfg)
+	if lang == "go" {
+		s = scanner.NewGoScanner(cfg)
+	}
+	results, err := s.Scan(dir)
+	if err != nil {
+		t.Fatalf("Scan: %v", err)
+	}
+	g, err := graph.Build(dir, loadLayout(t, dir), results)
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+	return usage.DeadExports(results, g)
+}
+
+func assertDead(t *testing.T, got, want []usage.DeadExport) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("got %d dead exports, want %d\ngot:  %+v\nwant: %+v", len(got), len(want), got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("[%d] = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// loadLayout finds the Go modules and workspace packages of the project
+// written to dir.
+func loadLayout(t *testing.T, dir string) config.Layout {
+	t.Helper()
+	l, err := config.LoadLayout(dir, "multi")
+	if err != nil {
+		t.Fatalf("LoadLayout: %v", err)
+	}
+	return l
+}
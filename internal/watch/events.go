@@ -0,0 +1,93 @@
+package watch
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+)
+
+// Hub broadcasts updates to every connected browser as Server-Sent Events.
+// Each client only ever needs the latest state, so a slow client skips
+// intermediate updates rather than blocking the others.
+type Hub struct {
+	mu      sync.Mutex
+	clients map[chan []byte]bool
+	closed  bool
+}
+
+// NewHub returns a Hub with no clients.
+func NewHub() *Hub {
+	return &Hub{clients: map[chan []byte]bool{}}
+}
+
+// Publish sends data to every client as an "update" event. data must not
+// contain newlines; compact JSON never does.
+func (h *Hub) Publish(data []byte) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for ch := range h.clients {
+		select {
+		case <-ch: // drop the update this client hasn't read yet
+		default:
+		}
+		ch <- data
+	}
+}
+
+// Close disconnects every client. Register it with http.Server's
+// RegisterOnShutdown: open event streams would otherwise hold Shutdown up
+// until its deadline.
+func (h *Hub) Close() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.closed = true
+	for ch := range h.clients {
+		close(ch)
+		delete(h.clients, ch)
+	}
+}
+
+// ServeHTTP streams updates to one client until it disconnects or the hub
+// is closed.
+func (h *Hub) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	ch := make(chan []byte, 1)
+	h.mu.Lock()
+	if h.closed {
+		h.mu.Unlock()
+		http.Error(w, "shutting down", http.StatusServiceUnavailable)
+		return
+	}
+	h.clients[ch] = true
+	h.mu.Unlock()
+	defer func() {
+		h.mu.Lock()
+		delete(h.clients, ch)
+		h.mu.Unlock()
+	}()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case data, ok := <-ch:
+			if !ok {
+				return
+			}
+			if _, err := fmt.Fprintf(w, "event: update\ndata: %s\n\n", data); err != nil {
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
@@ -0,0 +1,241 @@
+// Package watch keeps a project's scan results up to date as files change,
+// re-parsing only the files that were touched, and fans updates out to
+// browsers over Server-Sent Events.
+package watch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+
+	"github.com/jtoloui/depviz/internal/scanner"
+)
+
+// debounce is how long the watcher waits for a burst of events (an editor
+// save, a git checkout) to settle before rescanning.
+const debounce = 150 * time.Millisecond
+
+// Watcher holds the current scan results of a project and updates them from
+// file system events.
+type Watcher struct {
+	root    string
+	scanner scanner.FileScanner
+	skip    map[string]bool
+	fsw     *fsnotify.Watcher
+
+	mu    sync.RWMutex
+	files map[string]scanner.FileImports // keyed by path relative to root
+}
+
+// New starts watching root, skipping directories named in exclude, with
+// results as the initial state.
+func New(root string, s scanner.FileScanner, exclude []string, results []scanner.FileImports) (*Watcher, error) {
+	fsw, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, fmt.Errorf("creating watcher: %w", err)
+	}
+	w := &Watcher{
+		root:    root,
+		scanner: s,
+		skip:    map[string]bool{},
+		fsw:     fsw,
+		files:   make(map[string]scanner.FileImports, len(results)),
+	}
+	for _, e := range exclude {
+		w.skip[e] = true
+	}
+	for _, fi := range results {
+		w.files[fi.File] = fi
+	}
+	if err := w.addTree(root); err != nil {
+		_ = fsw.Close()
+		return nil, err
+	}
+	return w, nil
+}
+
+// Results returns a copy of the current scan results, sorted by file.
+func (w *Watcher) Results() []scanner.FileImports {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	out := make([]scanner.FileImports, 0, len(w.files))
+	for _, fi := range w.files {
+		out = append(out, fi)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].File < out[j].File })
+	return out
+}
+
+// Run processes events until ctx is cancelled, calling onChange with the new
+// results and the changed files after each batch that altered anything.
+func (w *Watcher) Run(ctx context.Context, onChange func(results []scanner.FileImports, changed []string)) error {
+	defer func() { _ = w.fsw.Close() }()
+
+	pending := map[string]bool{}
+	timer := time.NewTimer(debounce)
+	timer.Stop()
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case ev, ok := <-w.fsw.Events:
+			if !ok {
+				return nil
+			}
+			if w.skipped(ev.Name) {
+				continue
+			}
+			pending[ev.Name] = true
+			timer.Reset(debounce)
+		case err, ok := <-w.fsw.Errors:
+			if !ok {
+				return nil
+			}
+			slog.Warn("watch error", "err", err)
+		case <-timer.C:
+			var paths []string
+			for p := range pending {
+				paths = append(paths, p)
+			}
+			clear(pending)
+			if changed := w.apply(paths); len(changed) > 0 {
+				onChange(w.Results(), changed)
+			}
+		}
+	}
+}
+
+// apply rescans paths and returns the relative paths whose results changed.
+// A path may be a file or a directory, and may no longer exist.
+func (w *Watcher) apply(paths []string) []string {
+	sort.Strings(paths)
+	var changed []string
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		switch {
+		case errors.Is(err, fs.ErrNotExist):
+			changed = append(changed, w.remove(path)...)
+		case err != nil:
+			slog.Warn("stat failed", "path", path, "err", err)
+		case info.IsDir():
+			// A new (or moved-in) directory: watch it and pick up its files.
+			if err := w.addTree(path); err != nil {
+				slog.Warn("watch failed", "path", path, "err", err)
+			}
+			_ = filepath.WalkDir(path, func(p string, d fs.DirEntry, err error) error {
+				if err != nil {
+					return nil
+				}
+				if d.IsDir() && p != path && w.skip[d.Name()] {
+					return filepath.SkipDir
+				}
+				if !d.IsDir() && w.rescan(p) {
+					changed = append(changed, w.rel(p))
+				}
+				return nil
+			})
+		default:
+			if w.rescan(path) {
+				changed = append(changed, w.rel(path))
+			}
+		}
+	}
+	return changed
+}
+
+// rescan re-parses a single file and reports whether its entry changed. A
+// file that fails to parse — usually mid-edit — keeps its previous result.
+func (w *Watcher) rescan(path string) bool {
+	fi, err := w.scanner.ScanFile(w.root, path)
+	if err != nil {
+		slog.Warn("rescan failed, keeping previous result", "path", path, "err", err)
+		return false
+	}
+	rel := w.rel(path)
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	prev, had := w.files[rel]
+	if fi == nil {
+		delete(w.files, rel)
+		return had
+	}
+	if had && reflect.DeepEqual(prev, *fi) {
+		return false
+	}
+	w.files[rel] = *fi
+	return true
+}
+
+// remove drops the entry for a deleted file, or every entry under a deleted
+// directory.
+func (w *Watcher) remove(path string) []string {
+	rel := w.rel(path)
+	prefix := rel + string(filepath.Separator)
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	var removed []string
+	for f := range w.files {
+		if f == rel || strings.HasPrefix(f, prefix) {
+			delete(w.files, f)
+			removed = append(removed, f)
+		}
+	}
+	sort.Strings(removed)
+	return removed
+}
+
+// addTree watches dir and every directory below it that isn't excluded.
+func (w *Watcher) addTree(dir string) error {
+	return filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() {
+			return nil
+		}
+		if p != w.root && w.skip[d.Name()] {
+			return filepath.SkipDir
+		}
+		if err := w.fsw.Add(p); err != nil {
+			return fmt.Errorf("watching %s: %w", p, err)
+		}
+		return nil
+	})
+}
+
+// skipped reports whether path lies in an excluded directory.
+func (w *Watcher) skipped(path string) bool {
+	rel, err := filepath.Rel(w.root, path)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return true
+	}
+	for _, part := range strings.Split(rel, string(filepath.Separator)) {
+		if w.skip[part] {
+			return true
+		}
+	}
+	return false
+}
+
+func (w *Watcher) rel(path string) string {
+	rel, err := filepath.Rel(w.root, path)
+	if err != nil {
+		return path
+	}
+	return rel
+}
@@ -0,0 +1,170 @@
+package watch_test
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jtoloui/depviz/internal/config"
+	"github.com/jtoloui/depviz/internal/scanner"
+	"github.com/jtoloui/depviz/internal/watch"
+)
+
+type update struct {
+	results []scanner.FileImports
+	changed []string
+}
+
+func TestWatcher(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "a", "a.go"), "package a\n\nimport \"fmt\"\n\nvar A = fmt.Sprint\n")
+	writeFile(t, filepath.Join(dir, "b", "b.go"), "package b\n\nimport \"os\"\n\nvar B = os.Args\n")
+
+	cfg := &config.Config{Language: "go", Exclude: []string{"vendor"}}
+	s := scanner.NewGoScanner(cfg)
+	initial, err := s.Scan(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	w, err := watch.New(dir, s, cfg.Exclude, initial)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	updates := make(chan update, 10)
+	go func() {
+		_ = w.Run(ctx, func(results []scanner.FileImports, changed []string) {
+			updates <- update{results, changed}
+		})
+	}()
+
+	next := func(what string) update {
+		t.Helper()
+		select {
+		case u := <-updates:
+			return u
+		case <-time.After(5 * time.Second):
+			t.Fatalf("no update after %s", what)
+		}
+		return update{}
+	}
+
+	// Edit an existing file.
+	writeFile(t, filepath.Join(dir, "a", "a.go"), "package a\n\nimport \"strings\"\n\nvar A = strings.ToUpper\n")
+	u := next("edit")
+	if len(u.changed) != 1 || u.changed[0] != filepath.Join("a", "a.go") {
+		t.Errorf("changed = %v, want [a/a.go]", u.changed)
+	}
+	if got := find(u.results, filepath.Join("a", "a.go")); got == nil || got.Imports[0] != "strings" {
+		t.Errorf("a.go after edit = %+v", got)
+	}
+
+	// Saving a file without changing what it imports or exports sends nothing.
+	writeFile(t, filepath.Join(dir, "a", "a.go"), "package a\n\nimport \"strings\"\n\nvar A = strings.ToUpper\n")
+	select {
+	case u := <-updates:
+		t.Errorf("unexpected update for unchanged save: %v", u.changed)
+	case <-time.After(500 * time.Millisecond):
+	}
+
+	// Delete a file.
+	if err := os.Remove(filepath.Join(dir, "b", "b.go")); err != nil {
+		t.Fatal(err)
+	}
+	u = next("delete")
+	if find(u.results, filepath.Join("b", "b.go")) != nil {
+		t.Error("b.go should be gone after delete")
+	}
+
+	// A new directory is picked up along with its files.
+	writeFile(t, filepath.Join(dir, "c", "d", "d.go"), "package d\n\nimport \"io\"\n\nvar D = io.EOF\n")
+	deadline := time.After(5 * time.Second)
+	for find(u.results, filepath.Join("c", "d", "d.go")) == nil {
+		select {
+		case u = <-updates:
+		case <-deadline:
+			t.Fatal("new directory never scanned")
+		}
+	}
+
+	// Excluded directories are ignored.
+	writeFile(t, filepath.Join(dir, "vendor", "v.go"), "package v\n\nimport \"fmt\"\n\nvar V = fmt.Sprint\n")
+	select {
+	case u := <-updates:
+		t.Errorf("unexpected update for excluded dir: %v", u.changed)
+	case <-time.After(500 * time.Millisecond):
+	}
+
+	if n := len(w.Results()); n != 2 {
+		t.Errorf("Results() has %d files, want 2", n)
+	}
+}
+
+func TestHub(t *testing.T) {
+	t.Parallel()
+
+	hub := watch.NewHub()
+	srv := httptest.NewServer(hub)
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Errorf("Content-Type = %q", ct)
+	}
+
+	// The client registers before headers are flushed, so it's subscribed now.
+	hub.Publish([]byte(`[{"file":"a.go"}]`))
+
+	r := bufio.NewReader(resp.Body)
+	var lines []string
+	for len(lines) < 2 {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			t.Fatal(err)
+		}
+		lines = append(lines, strings.TrimSpace(line))
+	}
+	if lines[0] != "event: update" || lines[1] != `data: [{"file":"a.go"}]` {
+		t.Errorf("event = %q", lines)
+	}
+
+	hub.Close()
+	if _, err := r.ReadString('\n'); err == nil {
+		if _, err := r.ReadString('\n'); err == nil {
+			t.Error("stream should end after Close")
+		}
+	}
+}
+
+func find(results []scanner.FileImports, file string) *scanner.FileImports {
+	for i := range results {
+		if results[i].File == file {
+			return &results[i]
+		}
+	}
+	return nil
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
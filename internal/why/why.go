@@ -0,0 +1,230 @@
+// Package why explains how one file comes to depend on another file or an
+// external package, as chains of imports through the dependency graph.
+package why
+
+import (
+	"strings"
+
+	"github.com/jtoloui/depviz/internal/graph"
+	"github.com/jtoloui/depviz/internal/scanner"
+)
+
+// Path is a chain of imports. Each edge's Import carries the statement and
+// line that makes the hop. When the target is a package rather than a
+// scanned file, the last edge's To is the package specifier.
+type Path []graph.Edge
+
+// Finder searches a graph for import chains.
+type Finder struct {
+	g       *graph.Graph
+	results map[string]scanner.FileImports
+}
+
+// New returns a Finder over g. results must be the scan g was built from;
+// they supply the imports of packages outside the graph.
+func New(g *graph.Graph, results []scanner.FileImports) *Finder {
+	byFile := make(map[string]scanner.FileImports, len(results))
+	for _, fi := range results {
+		byFile[fi.File] = fi
+	}
+	return &Finder{g: g, results: byFile}
+}
+
+// target is where a path may end: a scanned file, or any file importing the
+// package (with the import that does so as the final hop).
+type target struct {
+	file  string
+	final map[string]graph.Edge
+}
+
+func (f *Finder) target(to string) target {
+	if f.g.Node(to) != nil {
+		return target{file: to}
+	}
+	t := target{final: map[string]graph.Edge{}}
+	for file, fi := range f.results {
+		for i, imp := range fi.Imports {
+			if !matchesPackage(imp, to, fi.Lang) {
+				continue
+			}
+			d := scanner.ImportDetail{Path: imp}
+			if i < len(fi.Details) {
+				d = fi.Details[i]
+			}
+			t.final[file] = graph.Edge{From: file, To: imp, Import: d}
+			break
+		}
+	}
+	return t
+}
+
+// matchesPackage reports whether spec, imported by a lang file, imports pkg
+// or something inside it, using the language's path separator: "lodash/fp"
+// is in "lodash" but "lodash.debounce" isn't, "os.path" is in "os" and
+// "serde::de" in "serde".
+func matchesPackage(spec, pkg, lang string) bool {
+	if spec == pkg {
+		return true
+	}
+	rest, ok := strings.CutPrefix(spec, pkg)
+	return ok && strings.HasPrefix(rest, separator(lang))
+}
+
+// separator is the delimiter between the parts of a lang import path.
+func separator(lang string) string {
+	switch lang {
+	case "python", "java", "kotlin":
+		return "."
+	case "rust":
+		return "::"
+	}
+	return "/"
+}
+
+func (t target) reached(file string) bool {
+	if t.file != "" {
+		return file == t.file
+	}
+	_, ok := t.final[file]
+	return ok
+}
+
+// finish appends the package hop, if any, to a path ending at file.
+func (t target) finish(p Path, file string) Path {
+	out := append(Path(nil), p...)
+	if e, ok := t.final[file]; ok {
+		out = append(out, e)
+	}
+	return out
+}
+
+// Shortest returns the shortest chain of imports from the file from to to —
+// a scanned file or a package specifier — or nil if there is none.
+func (f *Finder) Shortest(from, to string) Path {
+	if f.g.Node(from) == nil || from == to {
+		return nil
+	}
+	t := f.target(to)
+	if t.reached(from) {
+		return t.finish(nil, from)
+	}
+
+	via := map[string]graph.Edge{from: {}}
+	queue := []string{from}
+	for len(queue) > 0 {
+		cur := queue[0]
+		queue = queue[1:]
+		for _, e := range f.g.Node(cur).Out {
+			if _, seen := via[e.To]; seen {
+				continue
+			}
+			via[e.To] = e
+			if t.reached(e.To) {
+				return t.finish(unwind(via, from, e.To), e.To)
+			}
+			queue = append(queue, e.To)
+		}
+	}
+	return nil
+}
+
+func unwind(via map[string]graph.Edge, from, to string) Path {
+	var p Path
+	for cur := to; cur != from; cur = via[cur].From {
+		p = append(p, via[cur])
+	}
+	for i, j := 0, len(p)-1; i < j; i, j = i+1, j-1 {
+		p[i], p[j] = p[j], p[i]
+	}
+	return p
+}
+
+// All returns the simple import chains from from to to, shortest first.
+// Chains are found one length at a time, so the search stops after the
+// limit shortest chains; the second result reports whether there were
+// more.
+func (f *Finder) All(from, to string, limit int) ([]Path, bool) {
+	if f.g.Node(from) == nil || from == to {
+		return nil, false
+	}
+	t := f.target(to)
+	useful := f.canReach(t)
+
+	var paths []Path
+	truncated := false
+	onPath := map[string]bool{from: true}
+	var cur Path
+	// walk collects the chains of exactly depth hops through file, and
+	// notes whether any chain was cut short by depth.
+	var cut bool
+	var walk func(file string, depth int)
+	walk = func(file string, depth int) {
+		if truncated {
+			return
+		}
+		if len(cur) == depth {
+			if !t.reached(file) {
+				cut = true
+				return
+			}
+			if len(paths) == limit {
+				truncated = true
+				return
+			}
+			paths = append(paths, t.finish(cur, file))
+			// A package may also be imported further along.
+			if t.file == "" {
+				cut = true
+			}
+			return
+		}
+		// A path to a file target ends there.
+		if t.file != "" && t.reached(file) {
+			return
+		}
+		followed := map[string]bool{}
+		for _, e := range f.g.Node(file).Out {
+			if onPath[e.To] || !useful[e.To] || followed[e.To] {
+				continue
+			}
+			followed[e.To] = true
+			onPath[e.To] = true
+			cur = append(cur, e)
+			walk(e.To, depth)
+			cur = cur[:len(cur)-1]
+			onPath[e.To] = false
+		}
+	}
+	for depth := 0; !truncated; depth++ {
+		cut = false
+		walk(from, depth)
+		if !cut {
+			break
+		}
+	}
+	return paths, truncated
+}
+
+// canReach returns the files from which t is reachable, so the path search
+// never wanders into parts of the graph that can't lead there.
+func (f *Finder) canReach(t target) map[string]bool {
+	seen := map[string]bool{}
+	var queue []string
+	for _, n := range f.g.Nodes() {
+		if t.reached(n.File) {
+			seen[n.File] = true
+			queue = append(queue, n.File)
+		}
+	}
+	for len(queue) > 0 {
+		cur := queue[0]
+		queue = queue[1:]
+		for _, e := range f.g.Node(cur).In {
+			if !seen[e.From] {
+				seen[e.From] = true
+				queue = append(queue, e.From)
+			}
+		}
+	}
+	return seen
+}
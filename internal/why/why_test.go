@@ -0,0 +1,127 @@
+package why_test
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/jtoloui/depviz/internal/config"
+	"github.com/jtoloui/depviz/internal/graph"
+	"github.com/jtoloui/depviz/internal/scanner"
+	"github.com/jtoloui/depviz/internal/why"
+)
+
+func newFinder(t *testing.T) *why.Finder {
+	t.Helper()
+	imp := func(path string, line int) scanner.ImportDetail {
+		return scanner.ImportDetail{Path: path, Line: line, Snippet: "import x from '" + path + "'"}
+	}
+	results := []scanner.FileImports{
+		{File: "app.tsx", Lang: "js", Imports: []string{"./page", "./util"}, Details: []scanner.ImportDetail{imp("./page", 1), imp("./util", 2)}},
+		{File: "page.tsx", Lang: "js", Imports: []string{"./api", "./util"}, Details: []scanner.ImportDetail{imp("./api", 3), imp("./util", 4)}},
+		{File: "util.ts", Lang: "js", Imports: []string{"./api"}, Details: []scanner.ImportDetail{imp("./api", 5)}},
+		{File: "api.ts", Lang: "js", Imports: []string{"./db", "react"}, Details: []scanner.ImportDetail{imp("./db", 6), imp("react", 7)}},
+		{File: "db.ts", Lang: "js", Imports: []string{"pg/lib/client", "./api"}, Details: []scanner.ImportDetail{imp("pg/lib/client", 8), imp("./api", 9)}},
+		{File: "lonely.ts", Lang: "js", Imports: []string{"lodash.debounce"}, Details: []scanner.ImportDetail{imp("lodash.debounce", 10)}},
+		{File: "tool.py", Lang: "python", Imports: []string{"os.path"}, Details: []scanner.ImportDetail{imp("os.path", 11)}},
+		{File: "main.rs", Lang: "rust", Imports: []string{"serde::de"}, Details: []scanner.ImportDetail{imp("serde::de", 12)}},
+	}
+	g, err := graph.Build(t.TempDir(), config.Layout{}, results)
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+	return why.New(g, results)
+}
+
+// hops renders a path as "from:line>to ..." for comparison.
+func hops(p why.Path) string {
+	var parts []string
+	for _, e := range p {
+		parts = append(parts, e.From+":"+strconv.Itoa(e.Import.Line)+">"+e.To)
+	}
+	return strings.Join(parts, " ")
+}
+
+func TestShortest(t *testing.T) {
+	t.Parallel()
+	f := newFinder(t)
+
+	tests := []struct {
+		name, from, to, want string
+	}{
+		{"file", "app.tsx", "db.ts", "app.tsx:1>page.tsx page.tsx:3>api.ts api.ts:6>db.ts"},
+		{"package subpath", "app.tsx", "pg", "app.tsx:1>page.tsx page.tsx:3>api.ts api.ts:6>db.ts db.ts:8>pg/lib/client"},
+		{"direct package", "api.ts", "react", "api.ts:7>react"},
+		{"no path", "lonely.ts", "db.ts", ""},
+		{"not a prefix match", "app.tsx", "pg/lib/cli", ""},
+		{"dotted npm name", "lonely.ts", "lodash.debounce", "lonely.ts:10>lodash.debounce"},
+		{"dot isn't a JS separator", "lonely.ts", "lodash", ""},
+		{"python submodule", "tool.py", "os", "tool.py:11>os.path"},
+		{"rust path", "main.rs", "serde", "main.rs:12>serde::de"},
+		{"unknown from", "missing.ts", "db.ts", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := hops(f.Shortest(tt.from, tt.to)); got != tt.want {
+				t.Errorf("Shortest(%s, %s) = %q, want %q", tt.from, tt.to, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAll(t *testing.T) {
+	t.Parallel()
+	f := newFinder(t)
+
+	paths, truncated := f.All("app.tsx", "api.ts", 10)
+	want := []string{
+		"app.tsx:1>page.tsx page.tsx:3>api.ts",
+		"app.tsx:2>util.ts util.ts:5>api.ts",
+		"app.tsx:1>page.tsx page.tsx:4>util.ts util.ts:5>api.ts",
+	}
+	if truncated {
+		t.Error("truncated = true, want false")
+	}
+	if len(paths) != len(want) {
+		t.Fatalf("got %d paths, want %d: %v", len(paths), len(want), paths)
+	}
+	for i := range want {
+		if got := hops(paths[i]); got != want[i] {
+			t.Errorf("path %d = %q, want %q", i, got, want[i])
+		}
+	}
+
+	paths, truncated = f.All("app.tsx", "api.ts", 2)
+	if len(paths) != 2 || !truncated {
+		t.Errorf("limit 2: got %d paths, truncated=%v", len(paths), truncated)
+	}
+
+	if paths, _ := f.All("lonely.ts", "api.ts", 10); paths != nil {
+		t.Errorf("lonely.ts paths = %v, want none", paths)
+	}
+}
+
+func TestAll_ShortestFirst(t *testing.T) {
+	t.Parallel()
+	// Following imports in order reaches d.ts the long way first.
+	results := []scanner.FileImports{
+		{File: "a.ts", Lang: "js", Imports: []string{"./b", "./d"}},
+		{File: "b.ts", Lang: "js", Imports: []string{"./c"}},
+		{File: "c.ts", Lang: "js", Imports: []string{"./d"}},
+		{File: "d.ts", Lang: "js"},
+	}
+	g, err := graph.Build(t.TempDir(), config.Layout{}, results)
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+	f := why.New(g, results)
+
+	paths, truncated := f.All("a.ts", "d.ts", 1)
+	if len(paths) != 1 || !truncated {
+		t.Fatalf("got %d paths, truncated=%v, want 1 and true", len(paths), truncated)
+	}
+	if got, want := hops(paths[0]), "a.ts:0>d.ts"; got != want {
+		t.Errorf("path = %q, want %q", got, want)
+	}
+}